@@ -0,0 +1,224 @@
+#include <OpenColorIO/OpenColorIO.h>
+
+#include <sstream>
+#include <cstring>
+#include <string>
+
+#include "ocio.h"
+#include "ocio_abi.h"
+#include "storage.h"
+#include "config.h"
+
+namespace OCIO = OCIO_NAMESPACE;
+
+namespace ocigo {
+
+IndexMap<OCIO::BakerRcPtr> g_Baker_map;
+
+}
+
+extern "C" {
+
+    void deleteBaker(BakerId p) {
+        if (p != NULL) {
+            if (p->handle) {
+                ocigo::g_Baker_map.remove(p->handle);
+                p->handle = 0;
+            }
+            freeHandleContext(p);
+        }
+    }
+
+    BakerId Baker_Create() {
+        BakerId p = NEW_HANDLE_CONTEXT();
+        BEGIN_CATCH_CTX_ERR(p)
+        p->handle = ocigo::g_Baker_map.add(OCIO::Baker::Create());
+        END_CATCH_CTX_ERR(p)
+        return p;
+    }
+
+    BakerId Baker_createEditableCopy(BakerId p) {
+        OCIO::BakerRcPtr ptr;
+        BEGIN_CATCH_CTX_ERR(p)
+        ptr = ocigo::g_Baker_map.get(p->handle).get()->createEditableCopy();
+        END_CATCH_CTX_ERR(p)
+        if (ptr == NULL) { return NULL; }
+        return NEW_HANDLE_CONTEXT(ocigo::g_Baker_map.add(ptr));
+    }
+
+    Config* Baker_getConfig(BakerId p) {
+        OCIO::ConstConfigRcPtr ptr;
+        BEGIN_CATCH_CTX_ERR(p)
+        ptr = ocigo::g_Baker_map.get(p->handle).get()->getConfig();
+        END_CATCH_CTX_ERR(p)
+        if (ptr == NULL) { return NULL; }
+        return (Config*) NEW_HANDLE_CONTEXT(
+                ocigo::g_Config_map.add(OCIO_CONST_POINTER_CAST<OCIO::Config>(ptr)));
+    }
+
+    void Baker_setConfig(BakerId p, Config* config) {
+        BEGIN_CATCH_CTX_ERR(p)
+        OCIO::ConstConfigRcPtr cfg = ocigo::g_Config_map.get(config->handle);
+        ocigo::g_Baker_map.get(p->handle).get()->setConfig(cfg);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    const char* Baker_getFormat(BakerId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Baker_map.get(p->handle).get()->getFormat();
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Baker_setFormat(BakerId p, const char* formatName) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Baker_map.get(p->handle).get()->setFormat(formatName);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    const char* Baker_getType(BakerId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Baker_map.get(p->handle).get()->getType();
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Baker_setType(BakerId p, const char* type) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Baker_map.get(p->handle).get()->setType(type);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    const char* Baker_getMetadata(BakerId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Baker_map.get(p->handle).get()->getMetadata();
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Baker_setMetadata(BakerId p, const char* metadata) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Baker_map.get(p->handle).get()->setMetadata(metadata);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    const char* Baker_getInputSpace(BakerId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Baker_map.get(p->handle).get()->getInputSpace();
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Baker_setInputSpace(BakerId p, const char* inputSpace) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Baker_map.get(p->handle).get()->setInputSpace(inputSpace);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    const char* Baker_getShaperSpace(BakerId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Baker_map.get(p->handle).get()->getShaperSpace();
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Baker_setShaperSpace(BakerId p, const char* shaperSpace) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Baker_map.get(p->handle).get()->setShaperSpace(shaperSpace);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    const char* Baker_getLooks(BakerId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Baker_map.get(p->handle).get()->getLooks();
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Baker_setLooks(BakerId p, const char* looks) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Baker_map.get(p->handle).get()->setLooks(looks);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    const char* Baker_getTargetSpace(BakerId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Baker_map.get(p->handle).get()->getTargetSpace();
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Baker_setTargetSpace(BakerId p, const char* targetSpace) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Baker_map.get(p->handle).get()->setTargetSpace(targetSpace);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    int Baker_getShaperSize(BakerId p) {
+        int ret = -1;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Baker_map.get(p->handle).get()->getShaperSize();
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Baker_setShaperSize(BakerId p, int shapersize) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Baker_map.get(p->handle).get()->setShaperSize(shapersize);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    int Baker_getCubeSize(BakerId p) {
+        int ret = -1;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Baker_map.get(p->handle).get()->getCubeSize();
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Baker_setCubeSize(BakerId p, int cubesize) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Baker_map.get(p->handle).get()->setCubeSize(cubesize);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    char* Baker_bake(BakerId p) {
+        std::ostringstream s;
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Baker_map.get(p->handle).get()->bake(s);
+        END_CATCH_CTX_ERR(p)
+        return strdup(s.str().c_str());
+    }
+
+    int Baker_getNumFormats() {
+        int ret = 0;
+        BEGIN_CATCH_ERR
+        ret = OCIO::Baker::getNumFormats();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    const char* Baker_getFormatNameByIndex(int index) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO::Baker::getFormatNameByIndex(index);
+        END_CATCH_ERR
+        return ret;
+    }
+
+    const char* Baker_getFormatExtensionByIndex(int index) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO::Baker::getFormatExtensionByIndex(index);
+        END_CATCH_ERR
+        return ret;
+    }
+
+}
@@ -0,0 +1,265 @@
+package ocio
+
+// #include "stdlib.h"
+//
+// #include "ocio.h"
+//
+import "C"
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"unsafe"
+)
+
+/*
+Baker
+
+In certain situations it is necessary to serialize transforms into a
+variety of application specific lut formats. The Baker can be used to
+create lut formats that OCIO supports for writing.
+
+	baker := ocio.NewBaker(cfg)
+	baker.SetFormat("flame")
+	baker.SetInputSpace("lnf")
+	baker.SetTargetSpace("srgb8")
+	err := baker.Bake(w)
+*/
+type Baker struct {
+	ptr C.BakerId
+}
+
+func newBaker(p C.BakerId) *Baker {
+	b := &Baker{p}
+	runtime.SetFinalizer(b, deleteBaker)
+	return b
+}
+
+func deleteBaker(b *Baker) {
+	if b == nil {
+		return
+	}
+	if b.ptr != nil {
+		runtime.SetFinalizer(b, nil)
+		C.deleteBaker(b.ptr)
+		b.ptr = nil
+	}
+	runtime.KeepAlive(b)
+}
+
+// Create a new Baker, bound to the given Config.
+// A nil Config may be passed, and set later with SetConfig.
+func NewBaker(config *Config) *Baker {
+	b := newBaker(C.Baker_Create())
+	if config != nil {
+		b.SetConfig(config)
+	}
+	return b
+}
+
+func (b *Baker) lastError(op string, errno ...error) error {
+	if b == nil {
+		return nil
+	}
+	err := getLastError(op, "Baker", b.ptr, errno...)
+	runtime.KeepAlive(b)
+	return err
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (b *Baker) Destroy() {
+	deleteBaker(b)
+}
+
+// Create a new editable copy of this Baker
+func (b *Baker) EditableCopy() (*Baker, error) {
+	ptr, err := C.Baker_createEditableCopy(b.ptr)
+	if err = b.lastError("Baker.EditableCopy", err); err != nil {
+		return nil, err
+	}
+	runtime.KeepAlive(b)
+	if ptr == nil {
+		return nil, errors.New("Baker.EditableCopy: failed to copy the Baker")
+	}
+	return newBaker(ptr), nil
+}
+
+// Config returns the Config used to bake, or nil if not set
+func (b *Baker) Config() *Config {
+	c := C.Baker_getConfig(b.ptr)
+	runtime.KeepAlive(b)
+	if c == nil {
+		return nil
+	}
+	return newConfig(c)
+}
+
+// SetConfig sets the Config to use. This shares the specified config.
+func (b *Baker) SetConfig(config *Config) {
+	C.Baker_setConfig(b.ptr, config.ptr)
+	runtime.KeepAlive(b)
+	runtime.KeepAlive(config)
+}
+
+// Format returns the lut output format (see BakerFormats)
+func (b *Baker) Format() string {
+	ret := C.GoString(C.Baker_getFormat(b.ptr))
+	runtime.KeepAlive(b)
+	return ret
+}
+
+// SetFormat sets the lut output format (see BakerFormats)
+func (b *Baker) SetFormat(formatName string) {
+	c_str := C.CString(formatName)
+	defer C.free(unsafe.Pointer(c_str))
+	C.Baker_setFormat(b.ptr, c_str)
+	runtime.KeepAlive(b)
+}
+
+// Type returns the lut output type (optional)
+func (b *Baker) Type() string {
+	ret := C.GoString(C.Baker_getType(b.ptr))
+	runtime.KeepAlive(b)
+	return ret
+}
+
+// SetType sets the lut output type (optional)
+func (b *Baker) SetType(typ string) {
+	c_str := C.CString(typ)
+	defer C.free(unsafe.Pointer(c_str))
+	C.Baker_setType(b.ptr, c_str)
+	runtime.KeepAlive(b)
+}
+
+// Metadata returns the optional metadata written to the lut header
+func (b *Baker) Metadata() string {
+	ret := C.GoString(C.Baker_getMetadata(b.ptr))
+	runtime.KeepAlive(b)
+	return ret
+}
+
+// SetMetadata sets the optional metadata written to the lut header
+func (b *Baker) SetMetadata(metadata string) {
+	c_str := C.CString(metadata)
+	defer C.free(unsafe.Pointer(c_str))
+	C.Baker_setMetadata(b.ptr, c_str)
+	runtime.KeepAlive(b)
+}
+
+// InputSpace returns the input colorspace that the lut will be applied to
+func (b *Baker) InputSpace() string {
+	ret := C.GoString(C.Baker_getInputSpace(b.ptr))
+	runtime.KeepAlive(b)
+	return ret
+}
+
+// SetInputSpace sets the input colorspace that the lut will be applied to
+func (b *Baker) SetInputSpace(inputSpace string) {
+	c_str := C.CString(inputSpace)
+	defer C.free(unsafe.Pointer(c_str))
+	C.Baker_setInputSpace(b.ptr, c_str)
+	runtime.KeepAlive(b)
+}
+
+// ShaperSpace returns the optional shaper colorspace used to improve 3D lut precision
+func (b *Baker) ShaperSpace() string {
+	ret := C.GoString(C.Baker_getShaperSpace(b.ptr))
+	runtime.KeepAlive(b)
+	return ret
+}
+
+// SetShaperSpace sets the optional shaper colorspace used to improve 3D lut precision
+func (b *Baker) SetShaperSpace(shaperSpace string) {
+	c_str := C.CString(shaperSpace)
+	defer C.free(unsafe.Pointer(c_str))
+	C.Baker_setShaperSpace(b.ptr, c_str)
+	runtime.KeepAlive(b)
+}
+
+// Looks returns the looks to be applied during baking, as a comma-delimited list
+func (b *Baker) Looks() string {
+	ret := C.GoString(C.Baker_getLooks(b.ptr))
+	runtime.KeepAlive(b)
+	return ret
+}
+
+// SetLooks sets the looks to be applied during baking, as a comma-delimited list
+func (b *Baker) SetLooks(looks string) {
+	c_str := C.CString(looks)
+	defer C.free(unsafe.Pointer(c_str))
+	C.Baker_setLooks(b.ptr, c_str)
+	runtime.KeepAlive(b)
+}
+
+// TargetSpace returns the target colorspace for the lut
+func (b *Baker) TargetSpace() string {
+	ret := C.GoString(C.Baker_getTargetSpace(b.ptr))
+	runtime.KeepAlive(b)
+	return ret
+}
+
+// SetTargetSpace sets the target colorspace for the lut
+func (b *Baker) SetTargetSpace(targetSpace string) {
+	c_str := C.CString(targetSpace)
+	defer C.free(unsafe.Pointer(c_str))
+	C.Baker_setTargetSpace(b.ptr, c_str)
+	runtime.KeepAlive(b)
+}
+
+// ShaperSize returns the size of the shaper (-1 lets the format decide)
+func (b *Baker) ShaperSize() int {
+	ret := int(C.Baker_getShaperSize(b.ptr))
+	runtime.KeepAlive(b)
+	return ret
+}
+
+// SetShaperSize sets the size of the shaper (-1 lets the format decide)
+func (b *Baker) SetShaperSize(shaperSize int) {
+	C.Baker_setShaperSize(b.ptr, C.int(shaperSize))
+	runtime.KeepAlive(b)
+}
+
+// CubeSize returns the size of the 3D lut cube (-1 lets the format decide)
+func (b *Baker) CubeSize() int {
+	ret := int(C.Baker_getCubeSize(b.ptr))
+	runtime.KeepAlive(b)
+	return ret
+}
+
+// SetCubeSize sets the size of the 3D lut cube (-1 lets the format decide)
+func (b *Baker) SetCubeSize(cubeSize int) {
+	C.Baker_setCubeSize(b.ptr, C.int(cubeSize))
+	runtime.KeepAlive(b)
+}
+
+// Bake writes the lut, in the configured format, to the given writer
+func (b *Baker) Bake(w io.Writer) error {
+	c_str, err := C.Baker_bake(b.ptr)
+	if err = b.lastError("Baker.Bake", err); err != nil {
+		if c_str != nil {
+			C.free(unsafe.Pointer(c_str))
+		}
+		return err
+	}
+	defer C.free(unsafe.Pointer(c_str))
+	runtime.KeepAlive(b)
+	_, err = io.WriteString(w, C.GoString(c_str))
+	return err
+}
+
+// BakerFormats returns the file formats that
+// can be written by a Baker
+func BakerFormats() []FileFormat {
+	num := int(C.Baker_getNumFormats())
+	formats := make([]FileFormat, 0, num)
+	for i := 0; i < num; i++ {
+		formats = append(formats, FileFormat{
+			Name:      C.GoString(C.Baker_getFormatNameByIndex(C.int(i))),
+			Extension: C.GoString(C.Baker_getFormatExtensionByIndex(C.int(i))),
+		})
+	}
+	return formats
+}
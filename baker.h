@@ -0,0 +1,13 @@
+#ifndef _OPENCOLORIGO_BAKER_H
+#define _OPENCOLORIGO_BAKER_H
+
+#include "storage.h"
+#include <OpenColorIO/OpenColorIO.h>
+
+namespace ocigo {
+
+extern IndexMap<OCIO_NAMESPACE::BakerRcPtr> g_Baker_map;
+
+} // ocigo
+
+#endif //_OPENCOLORIGO_BAKER_H
@@ -0,0 +1,177 @@
+package ocio
+
+import (
+	"context"
+	"fmt"
+)
+
+// bufferChunkPixels is the maximum number of pixels converted
+// to float32 at a time when processing integer and half buffers
+const bufferChunkPixels = 1 << 16
+
+// ApplyUint8 applies the Processor, in place, to packed 8-bit pixel data
+// (BIT_DEPTH_UINT8) with the given dimensions and number of channels.
+//
+// Values are normalized to [0, 1] for processing, then clamped and
+// rounded back to 8 bits. The data is converted in bounded chunks,
+// so a full float32 copy of the image is never allocated.
+func (p *Processor) ApplyUint8(data []uint8, width, height, numChannels int) error {
+	return p.ApplyUint8Context(context.Background(), data, width, height, numChannels)
+}
+
+// ApplyUint8Context is like ApplyUint8, but returns ctx.Err() between chunks
+// if the context has been cancelled. In that case the data may be
+// partially processed.
+func (p *Processor) ApplyUint8Context(ctx context.Context, data []uint8, width, height, numChannels int) error {
+	if err := checkBufferSize(len(data), width, height, numChannels); err != nil {
+		return err
+	}
+	const scale = 1.0 / 0xff
+	return p.applyChunked(ctx, width, height, numChannels,
+		func(dst ColorData, offset int) {
+			for i, v := range data[offset : offset+len(dst)] {
+				dst[i] = float32(v) * scale
+			}
+		},
+		func(src ColorData, offset int) {
+			out := data[offset : offset+len(src)]
+			for i, v := range src {
+				out[i] = uint8(clampUnit(v)*0xff + 0.5)
+			}
+		},
+	)
+}
+
+// ApplyUint16 applies the Processor, in place, to packed integer pixel
+// data stored in 16-bit values, with the given dimensions and number
+// of channels. The depth must be one of BIT_DEPTH_UINT10, BIT_DEPTH_UINT12,
+// BIT_DEPTH_UINT14 or BIT_DEPTH_UINT16, and sets the maximum code value
+// used to normalize the data (ie. 1023 for 10-bit DPX).
+//
+// Values are normalized to [0, 1] for processing, then clamped and
+// rounded back to the integer range. The data is converted in bounded
+// chunks, so a full float32 copy of the image is never allocated.
+func (p *Processor) ApplyUint16(data []uint16, width, height, numChannels int, depth BitDepth) error {
+	return p.ApplyUint16Context(context.Background(), data, width, height, numChannels, depth)
+}
+
+// ApplyUint16Context is like ApplyUint16, but returns ctx.Err() between chunks
+// if the context has been cancelled. In that case the data may be
+// partially processed.
+func (p *Processor) ApplyUint16Context(ctx context.Context, data []uint16, width, height, numChannels int, depth BitDepth) error {
+	var maxValue float32
+	switch depth {
+	case BIT_DEPTH_UINT10:
+		maxValue = 1<<10 - 1
+	case BIT_DEPTH_UINT12:
+		maxValue = 1<<12 - 1
+	case BIT_DEPTH_UINT14:
+		maxValue = 1<<14 - 1
+	case BIT_DEPTH_UINT16:
+		maxValue = 1<<16 - 1
+	default:
+		return fmt.Errorf("unsupported bit depth for a uint16 buffer: %v", depth)
+	}
+	if err := checkBufferSize(len(data), width, height, numChannels); err != nil {
+		return err
+	}
+	scale := 1 / maxValue
+	return p.applyChunked(ctx, width, height, numChannels,
+		func(dst ColorData, offset int) {
+			for i, v := range data[offset : offset+len(dst)] {
+				dst[i] = float32(v) * scale
+			}
+		},
+		func(src ColorData, offset int) {
+			out := data[offset : offset+len(src)]
+			for i, v := range src {
+				out[i] = uint16(clampUnit(v)*maxValue + 0.5)
+			}
+		},
+	)
+}
+
+// ApplyHalf applies the Processor, in place, to packed half-float
+// pixel data (BIT_DEPTH_F16) with the given dimensions and number
+// of channels. Values are not clamped.
+// The data is converted in bounded chunks, so a full float32
+// copy of the image is never allocated.
+func (p *Processor) ApplyHalf(data []Half, width, height, numChannels int) error {
+	return p.ApplyHalfContext(context.Background(), data, width, height, numChannels)
+}
+
+// ApplyHalfContext is like ApplyHalf, but returns ctx.Err() between chunks
+// if the context has been cancelled. In that case the data may be
+// partially processed.
+func (p *Processor) ApplyHalfContext(ctx context.Context, data []Half, width, height, numChannels int) error {
+	if err := checkBufferSize(len(data), width, height, numChannels); err != nil {
+		return err
+	}
+	return p.applyChunked(ctx, width, height, numChannels,
+		func(dst ColorData, offset int) {
+			for i, v := range data[offset : offset+len(dst)] {
+				dst[i] = v.Float32()
+			}
+		},
+		func(src ColorData, offset int) {
+			out := data[offset : offset+len(src)]
+			for i, v := range src {
+				out[i] = NewHalf(v)
+			}
+		},
+	)
+}
+
+func checkBufferSize(size, width, height, numChannels int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("image dimensions must be positive, got %dx%d", width, height)
+	}
+	if numChannels < 3 {
+		return fmt.Errorf("number of channels must be at least 3, got %d", numChannels)
+	}
+	if need := width * height * numChannels; size < need {
+		return fmt.Errorf("buffer of size %d is too small for a %dx%d image with %d channels (need %d)",
+			size, width, height, numChannels, need)
+	}
+	return nil
+}
+
+// applyChunked processes an image in bands of whole rows, through a
+// reusable float32 scratch buffer, stopping early if ctx is cancelled.
+// load fills the scratch buffer from the source data at the given
+// value offset, and store writes the processed values back.
+func (p *Processor) applyChunked(ctx context.Context, width, height, numChannels int,
+	load func(dst ColorData, offset int), store func(src ColorData, offset int)) error {
+
+	rows := bufferChunkPixels / width
+	if rows < 1 {
+		rows = 1
+	}
+	if rows > height {
+		rows = height
+	}
+	rowSize := width * numChannels
+	scratch := make(ColorData, rows*rowSize)
+
+	for y := 0; y < height; y += rows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := rows
+		if y+n > height {
+			n = height - y
+		}
+		chunk := scratch[:n*rowSize]
+		offset := y * rowSize
+
+		load(chunk, offset)
+		desc := NewPackedImageDesc(chunk, width, n, numChannels)
+		err := p.Apply(desc)
+		desc.Destroy()
+		if err != nil {
+			return err
+		}
+		store(chunk, offset)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package ocio
+
+import (
+	"context"
+	"testing"
+)
+
+func TestProcessorApplyUint8(t *testing.T) {
+	proc := getMatrixProcessor(t, 0.5, 0)
+	defer proc.Destroy()
+
+	// Large enough to be processed in multiple chunks
+	width, height, channels := 300, 500, 4
+	data := make([]uint8, width*height*channels)
+	for i := range data {
+		data[i] = uint8(i)
+	}
+	if err := proc.ApplyUint8(data, width, height, channels); err != nil {
+		t.Fatal(err.Error())
+	}
+	for i, v := range data {
+		expect := uint8(i)
+		if i%channels != 3 {
+			expect = uint8((float32(uint8(i))/255*0.5)*255 + 0.5)
+		}
+		if v != expect {
+			t.Fatalf("value %d: expected %d, got %d", i, expect, v)
+		}
+	}
+
+	if err := proc.ApplyUint8(make([]uint8, 10), 2, 2, 3); err == nil {
+		t.Error("expected an error for a buffer that is too small")
+	}
+	if err := proc.ApplyUint8(make([]uint8, 10), 0, 2, 3); err == nil {
+		t.Error("expected an error for an empty image")
+	}
+	if err := proc.ApplyUint8(make([]uint8, 10), 2, 2, 2); err == nil {
+		t.Error("expected an error for less than 3 channels")
+	}
+}
+
+func TestProcessorApplyUint16(t *testing.T) {
+	proc := getMatrixProcessor(t, 0.5, 0)
+	defer proc.Destroy()
+
+	data := []uint16{1000, 1023, 0, 2, 4, 6}
+	if err := proc.ApplyUint16(data, 2, 1, 3, BIT_DEPTH_UINT10); err != nil {
+		t.Fatal(err.Error())
+	}
+	expect := []uint16{500, 512, 0, 1, 2, 3}
+	for i := range expect {
+		if data[i] != expect[i] {
+			t.Errorf("expected %v, got %v", expect, data)
+			break
+		}
+	}
+
+	data = []uint16{65535, 65535, 65535}
+	if err := proc.ApplyUint16(data, 1, 1, 3, BIT_DEPTH_UINT16); err != nil {
+		t.Fatal(err.Error())
+	}
+	if data[0] != 32768 {
+		t.Errorf("expected 32768, got %d", data[0])
+	}
+
+	if err := proc.ApplyUint16(data, 1, 1, 3, BIT_DEPTH_F32); err == nil {
+		t.Error("expected an error for an unsupported bit depth")
+	}
+}
+
+func TestProcessorApplyHalf(t *testing.T) {
+	proc := getMatrixProcessor(t, 0.5, 0)
+	defer proc.Destroy()
+
+	data := []Half{NewHalf(1.5), NewHalf(4), NewHalf(-1), NewHalf(0.25)}
+	if err := proc.ApplyHalf(data, 1, 1, 4); err != nil {
+		t.Fatal(err.Error())
+	}
+	expect := []float32{0.75, 2, -0.5, 0.25}
+	for i, h := range data {
+		if h.Float32() != expect[i] {
+			t.Errorf("value %d: expected %v, got %v", i, expect[i], h.Float32())
+		}
+	}
+}
+
+func TestProcessorApplyBufferContext(t *testing.T) {
+	proc := getMatrixProcessor(t, 0.5, 0)
+	defer proc.Destroy()
+
+	data := []uint8{200, 100, 50}
+	if err := proc.ApplyUint8Context(context.Background(), data, 1, 1, 3); err != nil {
+		t.Fatal(err.Error())
+	}
+	if data[0] != 100 {
+		t.Errorf("expected 100, got %d", data[0])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := proc.ApplyUint8Context(ctx, data, 1, 1, 3); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if err := proc.ApplyUint16Context(ctx, []uint16{1, 2, 3}, 1, 1, 3, BIT_DEPTH_UINT16); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if err := proc.ApplyHalfContext(ctx, []Half{1, 2, 3}, 1, 1, 3); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if data[0] != 100 {
+		t.Errorf("expected data to be unmodified when cancelled, got %d", data[0])
+	}
+}
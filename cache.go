@@ -0,0 +1,232 @@
+package ocio
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// cacheGeneration is incremented by ClearAllCaches,
+// invalidating every ProcessorCache
+var cacheGeneration uint64
+
+// ProcessorCache is a concurrency-safe cache of Processors for a Config,
+// avoiding the cost of building a new Processor for repeated conversions.
+//
+// Processors are keyed by the source and destination colorspace names
+// (or the transform parameters and direction), along with the cache id
+// of the Config and Context, so edits to either produce new Processors.
+// The least recently used Processor is evicted when the cache is full,
+// and the whole cache is invalidated by ClearAllCaches.
+//
+// Each lookup returns a new handle to the cached Processor, with its
+// own error state, so it can be used concurrently with the handles of
+// other callers. The returned handle belongs to the caller, who should
+// Destroy it when done with it. This does not affect the cache.
+type ProcessorCache struct {
+	config  *Config
+	maxSize int
+
+	// Handles to config, each with their own error state,
+	// so lookups can call into OCIO concurrently
+	handles sync.Pool
+
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List
+	generation uint64
+}
+
+type processorCacheEntry struct {
+	key  string
+	proc *Processor
+}
+
+// NewProcessorCache creates a ProcessorCache for the Config, holding
+// at most maxSize Processors. A maxSize <= 0 means the size is unlimited.
+func NewProcessorCache(config *Config, maxSize int) *ProcessorCache {
+	c := &ProcessorCache{
+		config:     config,
+		maxSize:    maxSize,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		generation: atomic.LoadUint64(&cacheGeneration),
+	}
+	c.handles.New = func() interface{} { return config.share() }
+	return c
+}
+
+// Config returns the Config the cache builds Processors from
+func (c *ProcessorCache) Config() *Config {
+	return c.config
+}
+
+// Len returns the number of cached Processors
+func (c *ProcessorCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checkGeneration()
+	return c.lru.Len()
+}
+
+// Clear removes all cached Processors. Handles
+// returned by the cache remain valid.
+func (c *ProcessorCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clear()
+}
+
+// Processor returns a handle to a cached Processor converting between
+// the src and dst colorspace (or role) names, using the current Config
+// context. The handle belongs to the caller.
+func (c *ProcessorCache) Processor(src, dst string) (*Processor, error) {
+	return c.ProcessorWithContext(nil, src, dst)
+}
+
+// ProcessorWithContext returns a handle to a cached Processor converting
+// between the src and dst colorspace (or role) names, using a specific
+// Context. A nil Context uses the current Config context. The handle
+// belongs to the caller.
+func (c *ProcessorCache) ProcessorWithContext(ctx *Context, src, dst string) (*Processor, error) {
+	return c.get(ctx, "cs:"+src+"\x00"+dst, func(cfg *Config) (*Processor, error) {
+		if ctx == nil {
+			return cfg.ProcessorFromNames(src, dst)
+		}
+		return cfg.ProcessorCtxFromNames(ctx, src, dst)
+	})
+}
+
+// ProcessorTransform returns a handle to a cached Processor for the
+// transform and direction, using the current Config context. Transforms
+// are keyed by their parameters, so equal transforms share a Processor.
+// The handle belongs to the caller.
+func (c *ProcessorCache) ProcessorTransform(tx Transform, dir TransformDirection) (*Processor, error) {
+	key, err := transformCacheKey(tx)
+	if err != nil {
+		return nil, err
+	}
+	return c.get(nil, fmt.Sprintf("tx:%d:%s", dir, key), func(cfg *Config) (*Processor, error) {
+		return cfg.ProcessorTransformDir(tx, dir)
+	})
+}
+
+// get returns a new handle to the Processor cached under the key,
+// building it with create if needed. The cached Processor itself is
+// never returned, so it can be destroyed when evicted.
+func (c *ProcessorCache) get(ctx *Context, key string, create func(*Config) (*Processor, error)) (*Processor, error) {
+	cfg := c.handles.Get().(*Config)
+	defer c.handles.Put(cfg)
+
+	var (
+		id  string
+		err error
+	)
+	if ctx == nil {
+		id, err = cfg.CacheID()
+	} else {
+		id, err = cfg.CacheIDWithContext(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	key = id + "\x00" + key
+
+	c.mu.Lock()
+	c.checkGeneration()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		proc := elem.Value.(*processorCacheEntry).proc.share()
+		c.mu.Unlock()
+		return proc, nil
+	}
+	c.mu.Unlock()
+
+	// Build outside of the lock, so a slow Processor
+	// does not block lookups of other keys
+	proc, err := create(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checkGeneration()
+	if elem, ok := c.entries[key]; ok {
+		// Another caller built the same Processor first
+		proc.Destroy()
+		c.lru.MoveToFront(elem)
+		return elem.Value.(*processorCacheEntry).proc.share(), nil
+	}
+	c.entries[key] = c.lru.PushFront(&processorCacheEntry{key, proc})
+	for c.maxSize > 0 && c.lru.Len() > c.maxSize {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		entry := oldest.Value.(*processorCacheEntry)
+		delete(c.entries, entry.key)
+		entry.proc.Destroy()
+	}
+	return proc.share(), nil
+}
+
+// checkGeneration clears the cache if ClearAllCaches has been
+// called since it was last used. Must be called with the lock held.
+func (c *ProcessorCache) checkGeneration() {
+	if gen := atomic.LoadUint64(&cacheGeneration); gen != c.generation {
+		c.clear()
+		c.generation = gen
+	}
+}
+
+// clear must be called with the lock held. The cached Processors are
+// destroyed, since callers only hold their own handles to them.
+func (c *ProcessorCache) clear() {
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		elem.Value.(*processorCacheEntry).proc.Destroy()
+	}
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// transformCacheKey builds a key from the parameters of a Transform
+func transformCacheKey(tx Transform) (string, error) {
+	switch t := tx.(type) {
+	case *AllocationTransform:
+		return fmt.Sprintf("Allocation(%d,%d,%v)", t.Direction(), t.Allocation(), t.Vars()), nil
+	case *CDLTransform:
+		return fmt.Sprintf("CDL(%d,%q)", t.Direction(), t.XML()), nil
+	case *ColorSpaceTransform:
+		return fmt.Sprintf("ColorSpace(%d,%q,%q)", t.Direction(), t.Src(), t.Dst()), nil
+	case *DisplayTransform:
+		return fmt.Sprintf("Display(%d,%q,%q,%q,%q,%t)", t.Direction(), t.InputColorSpace(),
+			t.Display(), t.View(), t.LooksOverride(), t.LooksOverrideEnabled()), nil
+	case *ExponentTransform:
+		return fmt.Sprintf("Exponent(%d,%v)", t.Direction(), t.Value()), nil
+	case *FileTransform:
+		return fmt.Sprintf("File(%d,%q,%q,%d)", t.Direction(), t.Src(), t.CCCID(), t.Interpolation()), nil
+	case *GroupTransform:
+		keys := make([]string, t.Size())
+		for i := range keys {
+			child, err := t.Transform(i)
+			if err != nil {
+				return "", err
+			}
+			if keys[i], err = transformCacheKey(child); err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("Group(%d,[%s])", t.Direction(), strings.Join(keys, ",")), nil
+	case *LogTransform:
+		return fmt.Sprintf("Log(%d,%v)", t.Direction(), t.Base()), nil
+	case *LookTransform:
+		return fmt.Sprintf("Look(%d,%q,%q,%q)", t.Direction(), t.Src(), t.Dst(), t.Looks()), nil
+	case *MatrixTransform:
+		m44, offset4 := t.Value()
+		return fmt.Sprintf("Matrix(%d,%v,%v)", t.Direction(), m44, offset4), nil
+	case nil:
+		return "", fmt.Errorf("cannot cache a Processor for a nil Transform")
+	}
+	return "", fmt.Errorf("unsupported Transform type for caching: %T", tx)
+}
@@ -0,0 +1,240 @@
+package ocio
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestProcessorCache(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	cache := NewProcessorCache(cfg, 2)
+
+	p1, err := cache.Processor("lnf", "lg10")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer p1.Destroy()
+	p2, err := cache.Processor("lnf", "lg10")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer p2.Destroy()
+	if p1 == p2 {
+		t.Error("expected each lookup to return its own handle")
+	}
+	if cache.Len() != 1 {
+		t.Errorf("expected 1 cached Processor for the same src/dst, got %d", cache.Len())
+	}
+
+	p3, err := cache.Processor("lg10", "lnf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer p3.Destroy()
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 cached Processors, got %d", cache.Len())
+	}
+
+	// Evicts the least recently used ("lnf", "lg10")
+	p4, err := cache.Processor("lnf", "vd8")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer p4.Destroy()
+	if cache.Len() != 2 {
+		t.Errorf("expected the cache size to be limited to 2, got %d", cache.Len())
+	}
+
+	// Handles remain usable after their Processor is evicted and cleared
+	cache.Clear()
+	if cache.Len() != 0 {
+		t.Errorf("expected an empty cache after Clear, got %d", cache.Len())
+	}
+	for _, p := range []*Processor{p1, p2, p3, p4} {
+		if _, err = p.ApplyRGB([3]float32{0.1, 0.2, 0.3}); err != nil {
+			t.Errorf("expected a handle to remain valid: %v", err)
+		}
+	}
+
+	if _, err = cache.Processor("lnf", "__bad__"); err == nil {
+		t.Error("expected an error for an invalid colorspace")
+	}
+}
+
+func TestProcessorCacheBuilds(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	cache := NewProcessorCache(cfg, 1)
+	builds := 0
+	get := func(key string) {
+		proc, err := cache.get(nil, key, func(cfg *Config) (*Processor, error) {
+			builds++
+			return cfg.ProcessorFromNames("lnf", "lg10")
+		})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		proc.Destroy()
+	}
+
+	get("a")
+	get("a")
+	if builds != 1 {
+		t.Errorf("expected 1 build for the same key, got %d", builds)
+	}
+	get("b")
+	get("a")
+	if builds != 3 {
+		t.Errorf("expected an evicted Processor to be rebuilt, got %d builds", builds)
+	}
+	ClearAllCaches()
+	get("a")
+	if builds != 4 {
+		t.Errorf("expected a new Processor after ClearAllCaches, got %d builds", builds)
+	}
+}
+
+func TestProcessorCacheContext(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	ct, err := cfg.CurrentContext()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	ct2 := ct.EditableCopy()
+	ct2.SetStringVar("OVERRIDE", "luts2")
+
+	cache := NewProcessorCache(cfg, 0)
+	for _, c := range []*Context{ct, ct2, ct2} {
+		proc, err := cache.ProcessorWithContext(c, "scene_linear", "color_timing")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		proc.Destroy()
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected a Processor for each distinct Context, got %d", cache.Len())
+	}
+}
+
+func TestProcessorCacheTransform(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	cache := NewProcessorCache(cfg, 0)
+
+	newMatrix := func(scale float32) *MatrixTransform {
+		mtx := NewMatrixTransform()
+		mtx.SetMatrix([16]float32{
+			scale, 0, 0, 0,
+			0, scale, 0, 0,
+			0, 0, scale, 0,
+			0, 0, 0, 1,
+		})
+		return mtx
+	}
+	group := NewGroupTransform()
+	group.Push(newMatrix(2))
+
+	for i, tt := range []struct {
+		tx   Transform
+		dir  TransformDirection
+		size int
+	}{
+		{newMatrix(2), TRANSFORM_DIR_FORWARD, 1},
+		{newMatrix(2), TRANSFORM_DIR_FORWARD, 1}, // equal transform
+		{newMatrix(3), TRANSFORM_DIR_FORWARD, 2}, // different transform
+		{newMatrix(2), TRANSFORM_DIR_INVERSE, 3}, // different direction
+		{group, TRANSFORM_DIR_FORWARD, 4},
+	} {
+		proc, err := cache.ProcessorTransform(tt.tx, tt.dir)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		proc.Destroy()
+		if cache.Len() != tt.size {
+			t.Errorf("%d: expected %d cached Processors, got %d", i, tt.size, cache.Len())
+		}
+	}
+
+	if _, err = cache.ProcessorTransform(nil, TRANSFORM_DIR_FORWARD); err == nil {
+		t.Error("expected an error for a nil Transform")
+	}
+}
+
+func TestProcessorCacheClearAllCaches(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	cache := NewProcessorCache(cfg, 0)
+	p1, err := cache.Processor("lnf", "lg10")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer p1.Destroy()
+
+	ClearAllCaches()
+	if cache.Len() != 0 {
+		t.Errorf("expected ClearAllCaches to invalidate the cache, got %d entries", cache.Len())
+	}
+	if _, err = p1.ApplyRGB([3]float32{0.1, 0.2, 0.3}); err != nil {
+		t.Errorf("expected a handle to remain valid after ClearAllCaches: %v", err)
+	}
+}
+
+func TestProcessorCacheConcurrent(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	cache := NewProcessorCache(cfg, 4)
+	spaces := []string{"lnf", "lg10", "vd8", "srgb8", "lnh"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				dst := spaces[(i+j)%len(spaces)]
+				proc, err := cache.Processor("lnf", dst)
+				if err != nil {
+					t.Error(err.Error())
+					return
+				}
+				_, err = proc.ApplyRGB([3]float32{0.1, 0.2, 0.3})
+				proc.Destroy()
+				if err != nil {
+					t.Error(err.Error())
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if cache.Len() > 4 {
+		t.Errorf("expected at most 4 cached Processors, got %d", cache.Len())
+	}
+}
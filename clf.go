@@ -0,0 +1,404 @@
+package ocio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/justinfx/opencolorigo/lut"
+)
+
+/*
+CLFGroupTransform returns a GroupTransform equivalent to a Common LUT
+Format ProcessList, for use with Config.ProcessorTransform.
+
+Matrix nodes, Range nodes with the noClamp style, and Log and Exponent
+nodes with an OCIO v1 equivalent become MatrixTransform, LogTransform and
+ExponentTransform. LUT1D and LUT3D nodes, and clamping Range nodes, are
+written as .spi1d and .spi3d files into lutDir, which is created if
+needed, and applied with a FileTransform. The files must exist for as
+long as the transform is used. OCIO caches the LUT of a FileTransform by
+its path, so each file is named with the ProcessList id, the node index
+and a hash of its contents, and a changed ProcessList with the same id is
+written to new files. The files written are removed if an error is
+returned.
+
+The camera Log styles, the Exponent styles other than basicFwd and
+basicRev, halfDomain LUT1D nodes and Range nodes that only clamp one
+side have no OCIO v1 equivalent, and return an error. Such a
+ProcessList can be baked with lut.ProcessList.ToLUT3D instead.
+*/
+func CLFGroupTransform(pl *lut.ProcessList, lutDir string) (*GroupTransform, error) {
+	if err := pl.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(lutDir, 0755); err != nil {
+		return nil, err
+	}
+
+	prefix := clfFileChars.ReplaceAllString(pl.ID, "_")
+	group := NewGroupTransform()
+	var written []string
+	for i, n := range pl.Nodes {
+		path := filepath.Join(lutDir, fmt.Sprintf("%s_%d", prefix, i))
+		transforms, err := clfNodeTransforms(n, path, &written)
+		if err != nil {
+			group.Destroy()
+			for _, f := range written {
+				os.Remove(f)
+			}
+			return nil, fmt.Errorf("CLF node %d (%T): %w", i, n, err)
+		}
+		for _, tx := range transforms {
+			group.Push(tx)
+			destroyTransform(tx)
+		}
+	}
+	return group, nil
+}
+
+// clfFileChars matches the characters of a ProcessList id
+// replaced in the names of the LUT files it is written to
+var clfFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// clfNodeTransforms returns the transforms equivalent to a ProcessNode,
+// writing any LUT file as with clfFileTransform
+func clfNodeTransforms(n lut.ProcessNode, path string, written *[]string) ([]Transform, error) {
+	h := n.Header()
+	inScale, outScale := h.InBitDepth.Scale(), h.OutBitDepth.Scale()
+
+	switch n := n.(type) {
+	case *lut.MatrixNode:
+		var (
+			m44     [16]float32
+			offset4 [4]float32
+		)
+		// Alpha passes through, as in lut.Matrix.Eval
+		for row := 0; row < 3; row++ {
+			for col := 0; col < 3; col++ {
+				m44[row*4+col] = float32(n.Matrix.M[row*4+col] * inScale / outScale)
+			}
+			offset4[row] = float32(n.Matrix.Offset[row] / outScale)
+		}
+		m44[15] = 1
+		tx := NewMatrixTransform()
+		tx.SetValue(m44, offset4)
+		return []Transform{tx}, nil
+
+	case *lut.LUT1DNode:
+		if n.HalfDomain {
+			return nil, fmt.Errorf("a halfDomain LUT1D has no OCIO v1 equivalent")
+		}
+		l := lut.NewLUT1D(len(n.Values))
+		for i, v := range n.Values {
+			l.Values[i] = [3]float64{v[0] / outScale, v[1] / outScale, v[2] / outScale}
+		}
+		tx, err := clfFileTransform(l, path, INTERP_LINEAR, written)
+		if err != nil {
+			return nil, err
+		}
+		return []Transform{tx}, nil
+
+	case *lut.LUT3DNode:
+		l := lut.NewLUT3D(n.Size)
+		for i, v := range n.Values {
+			l.Values[i] = [3]float64{v[0] / outScale, v[1] / outScale, v[2] / outScale}
+		}
+		interp := INTERP_LINEAR
+		if n.Interpolation == "tetrahedral" {
+			interp = INTERP_TETRAHEDRAL
+		}
+		tx, err := clfFileTransform(l, path, interp, written)
+		if err != nil {
+			return nil, err
+		}
+		return []Transform{tx}, nil
+
+	case *lut.RangeNode:
+		if n.MinInValue == nil || n.MaxInValue == nil {
+			return nil, fmt.Errorf("a Range that only clamps one side has no OCIO v1 equivalent")
+		}
+		minIn, maxIn := *n.MinInValue/inScale, *n.MaxInValue/inScale
+		minOut, maxOut := *n.MinOutValue/outScale, *n.MaxOutValue/outScale
+		if n.Style == lut.RangeNoClamp {
+			scale := (maxOut - minOut) / (maxIn - minIn)
+			return []Transform{scaleOffsetTransform(scale, minOut-minIn*scale)}, nil
+		}
+		// A 1D LUT clamps to its domain, and maps it linearly
+		l := &lut.LUT1D{
+			DomainMin: [3]float64{minIn, minIn, minIn},
+			DomainMax: [3]float64{maxIn, maxIn, maxIn},
+			Values:    [][3]float64{{minOut, minOut, minOut}, {maxOut, maxOut, maxOut}},
+		}
+		tx, err := clfFileTransform(l, path, INTERP_LINEAR, written)
+		if err != nil {
+			return nil, err
+		}
+		return []Transform{tx}, nil
+
+	case *lut.LogNode:
+		transforms, err := clfLogTransforms(n)
+		if err != nil {
+			return nil, err
+		}
+		return scaledTransforms(transforms, inScale, outScale), nil
+
+	case *lut.ExponentNode:
+		dir := TRANSFORM_DIR_FORWARD
+		switch n.Style {
+		case lut.ExponentBasicFwd:
+		case lut.ExponentBasicRev:
+			dir = TRANSFORM_DIR_INVERSE
+		default:
+			return nil, fmt.Errorf("Exponent style %s has no OCIO v1 equivalent", n.Style)
+		}
+		value := [4]float32{1, 1, 1, 1}
+		for c := 0; c < 3; c++ {
+			value[c] = float32(n.ChannelParams(c).Exponent)
+		}
+		tx := NewExponentTransform()
+		tx.SetValue(value)
+		tx.SetDirection(dir)
+		return scaledTransforms([]Transform{tx}, inScale, outScale), nil
+	}
+	return nil, fmt.Errorf("unsupported node type %T", n)
+}
+
+// clfLogTransforms returns the transforms equivalent to a LogNode,
+// in the normalized scale of 32f
+func clfLogTransforms(n *lut.LogNode) ([]Transform, error) {
+	logTx := func(base float64, dir TransformDirection) Transform {
+		tx := NewLogTransform()
+		tx.SetBase(float32(base))
+		tx.SetDirection(dir)
+		return tx
+	}
+
+	switch n.Style {
+	case lut.LogLog10:
+		return []Transform{logTx(10, TRANSFORM_DIR_FORWARD)}, nil
+	case lut.LogAntiLog10:
+		return []Transform{logTx(10, TRANSFORM_DIR_INVERSE)}, nil
+	case lut.LogLog2:
+		return []Transform{logTx(2, TRANSFORM_DIR_FORWARD)}, nil
+	case lut.LogAntiLog2:
+		return []Transform{logTx(2, TRANSFORM_DIR_INVERSE)}, nil
+	case lut.LogLinToLog, lut.LogLogToLin:
+	default:
+		return nil, fmt.Errorf("Log style %s has no OCIO v1 equivalent", n.Style)
+	}
+
+	// log = logSideSlope * log(linSideSlope * lin + linSideOffset, base) + logSideOffset
+	var (
+		base                float64
+		linSlope, linOffset [3]float64
+		logSlope, logOffset [3]float64
+	)
+	for c := 0; c < 3; c++ {
+		p := n.ChannelParams(c)
+		if c > 0 && p.Base != base {
+			return nil, fmt.Errorf("a Log with a different base on each channel has no OCIO v1 equivalent")
+		}
+		base = p.Base
+		linSlope[c], linOffset[c] = p.LinSideSlope, p.LinSideOffset
+		logSlope[c], logOffset[c] = p.LogSideSlope, p.LogSideOffset
+	}
+
+	if n.Style == lut.LogLinToLog {
+		return []Transform{
+			scaleOffsetTransform3(linSlope, linOffset),
+			logTx(base, TRANSFORM_DIR_FORWARD),
+			scaleOffsetTransform3(logSlope, logOffset),
+		}, nil
+	}
+	invert := func(slope, offset [3]float64) ([3]float64, [3]float64) {
+		for c := range slope {
+			slope[c], offset[c] = 1/slope[c], -offset[c]/slope[c]
+		}
+		return slope, offset
+	}
+	logSlope, logOffset = invert(logSlope, logOffset)
+	linSlope, linOffset = invert(linSlope, linOffset)
+	return []Transform{
+		scaleOffsetTransform3(logSlope, logOffset),
+		logTx(base, TRANSFORM_DIR_INVERSE),
+		scaleOffsetTransform3(linSlope, linOffset),
+	}, nil
+}
+
+// clfFileTransform writes a LUT1D or LUT3D as a .spi1d or .spi3d file,
+// named with path and a hash of its contents, and returns a FileTransform
+// for it. A file that did not already exist is appended to written.
+func clfFileTransform(l lut.LUT, path string, interp InterpType, written *[]string) (Transform, error) {
+	var (
+		buf bytes.Buffer
+		ext string
+		err error
+	)
+	switch l := l.(type) {
+	case *lut.LUT1D:
+		ext, err = ".spi1d", lut.WriteSpi1D(&buf, l)
+	case *lut.LUT3D:
+		ext, err = ".spi3d", lut.WriteSpi3D(&buf, l)
+	default:
+		err = fmt.Errorf("unsupported LUT type %T", l)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	abs, err := filepath.Abs(fmt.Sprintf("%s_%x%s", path, sum[:8], ext))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(abs); os.IsNotExist(err) {
+		if err = ioutil.WriteFile(abs, buf.Bytes(), 0666); err != nil {
+			return nil, err
+		}
+		*written = append(*written, abs)
+	} else if err != nil {
+		return nil, err
+	}
+	tx := NewFileTransform()
+	tx.SetSrc(abs)
+	tx.SetInterpolation(interp)
+	return tx, nil
+}
+
+// scaledTransforms wraps transforms with the scale from the input bit
+// depth to 32f, and from 32f to the output bit depth, if needed
+func scaledTransforms(transforms []Transform, inScale, outScale float64) []Transform {
+	if inScale != 1 {
+		transforms = append([]Transform{scaleOffsetTransform(inScale, 0)}, transforms...)
+	}
+	if outScale != 1 {
+		transforms = append(transforms, scaleOffsetTransform(1/outScale, 0))
+	}
+	return transforms
+}
+
+// scaleOffsetTransform returns a MatrixTransform that scales
+// and offsets the RGB channels
+func scaleOffsetTransform(scale, offset float64) *MatrixTransform {
+	return scaleOffsetTransform3([3]float64{scale, scale, scale}, [3]float64{offset, offset, offset})
+}
+
+// scaleOffsetTransform3 returns a MatrixTransform that scales
+// and offsets each of the RGB channels
+func scaleOffsetTransform3(scale, offset [3]float64) *MatrixTransform {
+	var (
+		m44     [16]float32
+		offset4 [4]float32
+	)
+	for c := 0; c < 3; c++ {
+		m44[c*5] = float32(scale[c])
+		offset4[c] = float32(offset[c])
+	}
+	m44[15] = 1
+	tx := NewMatrixTransform()
+	tx.SetValue(m44, offset4)
+	return tx
+}
+
+// destroyTransform frees a Transform created by clfNodeTransforms
+func destroyTransform(tx Transform) {
+	switch tx := tx.(type) {
+	case *MatrixTransform:
+		tx.Destroy()
+	case *LogTransform:
+		tx.Destroy()
+	case *ExponentTransform:
+		tx.Destroy()
+	case *FileTransform:
+		tx.Destroy()
+	}
+}
+
+// DefaultBakeCLFLutSize is the 3D LUT edge length used by
+// Processor.BakeCLF when lutSize is not set
+const DefaultBakeCLFLutSize = 33
+
+// BakeCLF bakes the Processor into a Common LUT Format ProcessList with
+// the given id, holding a single 3D LUT of lutSize^3 samples over
+// the [0, 1] input range, applied with tetrahedral interpolation.
+// A lutSize of 0 uses DefaultBakeCLFLutSize.
+func (p *Processor) BakeCLF(id string, lutSize int) (*lut.ProcessList, error) {
+	if lutSize == 0 {
+		lutSize = DefaultBakeCLFLutSize
+	}
+	if lutSize < 2 {
+		return nil, fmt.Errorf("3D LUT size must be at least 2, got %d", lutSize)
+	}
+	samples, err := p.sampleLut3D(lutSize, func(_ int, t float32) float32 { return t })
+	if err != nil {
+		return nil, err
+	}
+
+	node := &lut.LUT3DNode{
+		NodeHeader: lut.NodeHeader{
+			InBitDepth:  lut.BitDepth32f,
+			OutBitDepth: lut.BitDepth32f,
+		},
+		Interpolation: "tetrahedral",
+		Size:          lutSize,
+		Values:        make([][3]float64, lutSize*lutSize*lutSize),
+	}
+	for i := range node.Values {
+		node.Values[i] = [3]float64{float64(samples[i*3]), float64(samples[i*3+1]), float64(samples[i*3+2])}
+	}
+
+	pl := lut.NewProcessList(id)
+	pl.Nodes = append(pl.Nodes, node)
+	if cacheID, err := p.CpuCacheID(); err == nil {
+		pl.Descriptions = append(pl.Descriptions, "Baked from OCIO processor "+cacheID)
+	}
+	return pl, nil
+}
+
+/*
+CompareCLF returns the largest absolute difference, in any channel,
+between the Processor and a Common LUT Format ProcessList, over a grid
+of samples^3 input values spanning [0, 1]. It can be used to validate
+that a delivered CLF matches the transform of a Config.
+*/
+func (p *Processor) CompareCLF(pl *lut.ProcessList, samples int) (float32, error) {
+	if samples < 2 {
+		return 0, fmt.Errorf("number of samples must be at least 2, got %d", samples)
+	}
+	if err := pl.Validate(); err != nil {
+		return 0, err
+	}
+
+	pixels := make([][3]float32, 0, samples*samples*samples)
+	last := float32(samples - 1)
+	for b := 0; b < samples; b++ {
+		for g := 0; g < samples; g++ {
+			for r := 0; r < samples; r++ {
+				pixels = append(pixels, [3]float32{float32(r) / last, float32(g) / last, float32(b) / last})
+			}
+		}
+	}
+
+	expect := make([][3]float32, len(pixels))
+	copy(expect, pixels)
+	if err := p.ApplyRGBPixels(expect); err != nil {
+		return 0, err
+	}
+
+	var maxErr float32
+	for i, px := range pixels {
+		actual := pl.Eval([3]float64{float64(px[0]), float64(px[1]), float64(px[2])})
+		for c := range actual {
+			if d := float32(math.Abs(actual[c] - float64(expect[i][c]))); d > maxErr || d != d {
+				maxErr = d
+			}
+		}
+	}
+	return maxErr, nil
+}
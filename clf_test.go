@@ -0,0 +1,236 @@
+package ocio
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justinfx/opencolorigo/lut"
+)
+
+func newTestProcessList() *lut.ProcessList {
+	value := func(v float64) *float64 { return &v }
+	header := func(in, out lut.BitDepth) lut.NodeHeader {
+		return lut.NodeHeader{InBitDepth: in, OutBitDepth: out}
+	}
+
+	mtx := lut.NewMatrix()
+	mtx.M[1], mtx.M[4] = 0.1, 0.05
+	mtx.Offset = [4]float64{10, 20, 30, 0}
+	for i := range mtx.M {
+		mtx.M[i] *= 1023
+	}
+
+	lut1D := &lut.LUT1DNode{NodeHeader: header(lut.BitDepth10i, lut.BitDepth32f), Values: make([][3]float64, 17)}
+	for i := range lut1D.Values {
+		v := float64(i) / 16
+		lut1D.Values[i] = [3]float64{v * v, v, v * 0.8}
+	}
+
+	lut3D := &lut.LUT3DNode{NodeHeader: header(lut.BitDepth32f, lut.BitDepth12i), Interpolation: "tetrahedral", Size: 5}
+	lut3D.Values = lut.NewLUT3D(5).Values
+	for i, v := range lut3D.Values {
+		lut3D.Values[i] = [3]float64{v[1] * 4095, v[0] * 4095, (v[2]*0.5 + 0.25) * 4095}
+	}
+
+	pl := lut.NewProcessList("ocio test/clf")
+	pl.Nodes = []lut.ProcessNode{
+		&lut.RangeNode{
+			NodeHeader: header(lut.BitDepth32f, lut.BitDepth32f),
+			MinInValue: value(0.1), MaxInValue: value(0.9),
+			MinOutValue: value(0), MaxOutValue: value(1),
+		},
+		&lut.MatrixNode{NodeHeader: header(lut.BitDepth32f, lut.BitDepth10i), Matrix: *mtx},
+		lut1D,
+		&lut.ExponentNode{
+			NodeHeader: header(lut.BitDepth32f, lut.BitDepth32f),
+			Style:      lut.ExponentBasicFwd,
+			Params: []lut.ExponentParams{
+				{Channel: "R", Exponent: 2.2}, {Channel: "G", Exponent: 2.2}, {Channel: "B", Exponent: 1.8},
+			},
+		},
+		&lut.LogNode{
+			NodeHeader: header(lut.BitDepth32f, lut.BitDepth32f),
+			Style:      lut.LogLinToLog,
+			Params:     []lut.LogParams{{Base: 10, LogSideSlope: 0.5, LogSideOffset: 1, LinSideOffset: 0.01}},
+		},
+		lut3D,
+		&lut.RangeNode{
+			NodeHeader: header(lut.BitDepth12i, lut.BitDepth32f),
+			Style:      lut.RangeNoClamp,
+			MinInValue: value(0), MaxInValue: value(4095),
+			MinOutValue: value(0.05), MaxOutValue: value(0.95),
+		},
+	}
+	return pl
+}
+
+func TestCLFGroupTransform(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	dir, err := ioutil.TempDir("", "ocio_clf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	pl := newTestProcessList()
+	group, err := CLFGroupTransform(pl, dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer group.Destroy()
+
+	files, err := filepath.Glob(filepath.Join(dir, "ocio_test_clf_*"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(files) != 3 {
+		t.Errorf("expected 3 LUT files for the LUT1D, LUT3D and clamping Range, got %v", files)
+	}
+
+	proc, err := cfg.ProcessorTransform(group)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer proc.Destroy()
+
+	maxErr, err := proc.CompareCLF(pl, 9)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if maxErr > 1e-3 {
+		t.Errorf("expected the GroupTransform to match the ProcessList, got a max error of %v", maxErr)
+	}
+
+	if _, err = proc.CompareCLF(pl, 1); err == nil {
+		t.Error("expected an error for too few samples")
+	}
+
+	// OCIO caches a FileTransform by path, so a changed LUT is
+	// written to a new file, and an unchanged one is reused
+	for _, change := range []float64{0, 0.5} {
+		lut3D := pl.Nodes[5].(*lut.LUT3DNode)
+		lut3D.Values[0][0] += change * 4095
+		again, err := CLFGroupTransform(pl, dir)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		again.Destroy()
+	}
+	if files, _ = filepath.Glob(filepath.Join(dir, "ocio_test_clf_*")); len(files) != 4 {
+		t.Errorf("expected a new LUT file for the changed LUT3D only, got %v", files)
+	}
+}
+
+func TestCLFGroupTransformUnsupported(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ocio_clf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	value := func(v float64) *float64 { return &v }
+	nodes := []lut.ProcessNode{
+		&lut.LogNode{Style: lut.LogCameraLinToLog, Params: []lut.LogParams{{LinSideBreak: 0.01}}},
+		&lut.ExponentNode{Style: lut.ExponentMonCurveFwd, Params: []lut.ExponentParams{{Exponent: 2.4, Offset: 0.055}}},
+		&lut.RangeNode{MinInValue: value(0), MinOutValue: value(0)},
+		&lut.LUT1DNode{HalfDomain: true, Values: make([][3]float64, 65536)},
+	}
+	for _, n := range nodes {
+		pl := lut.NewProcessList("unsupported")
+		pl.Nodes = []lut.ProcessNode{n}
+		group, err := CLFGroupTransform(pl, dir)
+		if err == nil {
+			group.Destroy()
+			t.Errorf("%T: expected an error for a node without an OCIO v1 equivalent", n)
+		} else if !strings.Contains(err.Error(), "CLF node 0") {
+			t.Errorf("%T: expected the error to name the node, got %q", n, err)
+		}
+	}
+
+	if _, err = CLFGroupTransform(lut.NewProcessList(""), dir); err == nil {
+		t.Error("expected an error for an invalid ProcessList")
+	}
+
+	// The LUT files of earlier nodes are removed
+	pl := lut.NewProcessList("unsupported")
+	pl.Nodes = []lut.ProcessNode{
+		&lut.LUT1DNode{Values: [][3]float64{{0, 0, 0}, {1, 1, 1}}},
+		&lut.RangeNode{MinInValue: value(0), MinOutValue: value(0)},
+	}
+	if group, err := CLFGroupTransform(pl, dir); err == nil {
+		group.Destroy()
+		t.Error("expected an error for a Range that only clamps one side")
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Errorf("expected the LUT files to be removed, got %d files", len(files))
+	}
+}
+
+func TestProcessorBakeCLF(t *testing.T) {
+	proc := getMatrixProcessor(t, 0.5, 0.1)
+	defer proc.Destroy()
+
+	pl, err := proc.BakeCLF("baked", 0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = pl.Validate(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pl.Nodes) != 1 {
+		t.Fatalf("expected a single node, got %d", len(pl.Nodes))
+	}
+	node, ok := pl.Nodes[0].(*lut.LUT3DNode)
+	if !ok || node.Size != DefaultBakeCLFLutSize {
+		t.Fatalf("expected a LUT3D of size %d, got %#v", DefaultBakeCLFLutSize, pl.Nodes[0])
+	}
+
+	// Values are stored with red changing fastest
+	last := float32(node.Size - 1)
+	for _, rgb := range [][3]int{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {3, 7, 32}} {
+		expect, err := proc.ApplyRGB([3]float32{float32(rgb[0]) / last, float32(rgb[1]) / last, float32(rgb[2]) / last})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		actual := node.Values[rgb[0]+node.Size*(rgb[1]+node.Size*rgb[2])]
+		for c := range expect {
+			if float32(actual[c]) != expect[c] {
+				t.Errorf("lattice point %v: expected %v, got %v", rgb, expect, actual)
+				break
+			}
+		}
+	}
+
+	// The baked ProcessList survives a round trip through a .clf file
+	var buf bytes.Buffer
+	if err = lut.WriteCLF(&buf, pl); err != nil {
+		t.Fatal(err.Error())
+	}
+	read, err := lut.ReadCLF(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	maxErr, err := proc.CompareCLF(read, 16)
+	checkExact(t, "the baked CLF", maxErr, err)
+
+	other := getMatrixProcessor(t, 0.5, 0.2)
+	defer other.Destroy()
+	if maxErr, err = other.CompareCLF(pl, 4); err != nil {
+		t.Fatal(err.Error())
+	} else if maxErr < 0.09 {
+		t.Errorf("expected a max error of about 0.1 for a different processor, got %v", maxErr)
+	}
+
+	if _, err = proc.BakeCLF("baked", 1); err == nil {
+		t.Error("expected an error for a 3D LUT size of 1")
+	}
+}
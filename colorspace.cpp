@@ -5,6 +5,7 @@
 #include "ocio.h"
 #include "ocio_abi.h"
 #include "storage.h"
+#include "transform.h"
 
 namespace OCIO = OCIO_NAMESPACE;
 
@@ -107,4 +108,69 @@ extern "C" {
         END_CATCH_ERR
     }
 
+    bool ColorSpace_isData(ColorSpaceId p) {
+        bool ret = false;
+        BEGIN_CATCH_ERR
+        ret = ocigo::g_ColorSpace_map.get(p).get()->isData();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void ColorSpace_setIsData(ColorSpaceId p, bool isData) {
+        BEGIN_CATCH_ERR
+        ocigo::g_ColorSpace_map.get(p).get()->setIsData(isData);
+        END_CATCH_ERR
+    }
+
+    Allocation ColorSpace_getAllocation(ColorSpaceId p) {
+        Allocation ret = ALLOCATION_UNKNOWN;
+        BEGIN_CATCH_ERR
+        ret = (Allocation)ocigo::g_ColorSpace_map.get(p).get()->getAllocation();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void ColorSpace_setAllocation(ColorSpaceId p, Allocation allocation) {
+        BEGIN_CATCH_ERR
+        ocigo::g_ColorSpace_map.get(p).get()->setAllocation((OCIO::Allocation)allocation);
+        END_CATCH_ERR
+    }
+
+    int ColorSpace_getAllocationNumVars(ColorSpaceId p) {
+        int ret = 0;
+        BEGIN_CATCH_ERR
+        ret = ocigo::g_ColorSpace_map.get(p).get()->getAllocationNumVars();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void ColorSpace_getAllocationVars(ColorSpaceId p, float* vars) {
+        BEGIN_CATCH_ERR
+        ocigo::g_ColorSpace_map.get(p).get()->getAllocationVars(vars);
+        END_CATCH_ERR
+    }
+
+    void ColorSpace_setAllocationVars(ColorSpaceId p, int numvars, const float* vars) {
+        BEGIN_CATCH_ERR
+        ocigo::g_ColorSpace_map.get(p).get()->setAllocationVars(numvars, vars);
+        END_CATCH_ERR
+    }
+
+    TransformId ColorSpace_getTransform(ColorSpaceId p, ColorSpaceDirection dir) {
+        OCIO::TransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        OCIO::ConstTransformRcPtr tx = ocigo::g_ColorSpace_map.get(p).get()->getTransform((OCIO::ColorSpaceDirection)dir);
+        if (tx) { ptr = tx->createEditableCopy(); }
+        END_CATCH_ERR
+        if ( ptr == NULL) { return 0; }
+        return ocigo::g_Transform_map.add(ptr);
+    }
+
+    void ColorSpace_setTransform(ColorSpaceId p, TransformId tx, ColorSpaceDirection dir) {
+        OCIO::ConstTransformRcPtr tx_ptr = ocigo::g_Transform_map.get(tx);
+        BEGIN_CATCH_ERR
+        ocigo::g_ColorSpace_map.get(p).get()->setTransform(tx_ptr, (OCIO::ColorSpaceDirection)dir);
+        END_CATCH_ERR
+    }
+
 }
\ No newline at end of file
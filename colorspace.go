@@ -26,6 +26,14 @@ const (
 	BIT_DEPTH_F32     BitDepth = C.BIT_DEPTH_F32
 )
 
+type ColorSpaceDirection int
+
+const (
+	COLORSPACE_DIR_UNKNOWN        ColorSpaceDirection = C.COLORSPACE_DIR_UNKNOWN
+	COLORSPACE_DIR_TO_REFERENCE   ColorSpaceDirection = C.COLORSPACE_DIR_TO_REFERENCE
+	COLORSPACE_DIR_FROM_REFERENCE ColorSpaceDirection = C.COLORSPACE_DIR_FROM_REFERENCE
+)
+
 /*
 The ColorSpace is the state of an image with respect to colorimetry and color encoding.
 Transforming images between different ColorSpaces is the primary motivation for this library.
@@ -151,3 +159,93 @@ func (c *ColorSpace) SetBitDepth(bitDepth BitDepth) {
 	C.ColorSpace_setBitDepth(c.ptr, C.BitDepth(bitDepth))
 	runtime.KeepAlive(c)
 }
+
+/*
+Data
+
+ColorSpaces that are data are treated a bit special. Basically, any
+colorspace transforms you try to apply to them are ignored. (Think of
+applying a gamut mapping transform to an ID pass). Also, the
+DisplayTransform process obeys special ‘data min’ and ‘data max’ args.
+
+This is traditionally used for pixel data that represents non-color
+pixel data, such as normals, point positions, ID information, etc.
+*/
+
+func (c *ColorSpace) IsData() bool {
+	ret := bool(C.ColorSpace_isData(c.ptr))
+	runtime.KeepAlive(c)
+	return ret
+}
+
+func (c *ColorSpace) SetIsData(isData bool) {
+	C.ColorSpace_setIsData(c.ptr, C.bool(isData))
+	runtime.KeepAlive(c)
+}
+
+/*
+Allocation
+
+If this colorspace needs to be transferred to a limited dynamic range
+coding space (such as during display with a GPU path), use this allocation
+to maximize bit efficiency.
+*/
+
+func (c *ColorSpace) Allocation() Allocation {
+	ret := Allocation(C.ColorSpace_getAllocation(c.ptr))
+	runtime.KeepAlive(c)
+	return ret
+}
+
+func (c *ColorSpace) SetAllocation(allocation Allocation) {
+	C.ColorSpace_setAllocation(c.ptr, C.Allocation(allocation))
+	runtime.KeepAlive(c)
+}
+
+// AllocationVars returns the allocation variables, such as
+// the [min, max] range of the allocation
+func (c *ColorSpace) AllocationVars() []float32 {
+	num := int(C.ColorSpace_getAllocationNumVars(c.ptr))
+	vars := make([]float32, num)
+	if num > 0 {
+		C.ColorSpace_getAllocationVars(c.ptr, (*C.float)(&vars[0]))
+	}
+	runtime.KeepAlive(c)
+	return vars
+}
+
+// SetAllocationVars sets the allocation variables.
+// Passing an empty slice clears the variables.
+func (c *ColorSpace) SetAllocationVars(vars []float32) {
+	var ptr *C.float
+	if len(vars) > 0 {
+		ptr = (*C.float)(&vars[0])
+	}
+	C.ColorSpace_setAllocationVars(c.ptr, C.int(len(vars)), ptr)
+	runtime.KeepAlive(c)
+	runtime.KeepAlive(vars)
+}
+
+/*
+Transform
+*/
+
+// Transform returns the transform used to convert to (COLORSPACE_DIR_TO_REFERENCE)
+// or from (COLORSPACE_DIR_FROM_REFERENCE) the reference color space,
+// or nil if one is not set.
+// This returns a copy, so changing it does not change the ColorSpace.
+func (c *ColorSpace) Transform(dir ColorSpaceDirection) (Transform, error) {
+	tx, err := newTransform(C.ColorSpace_getTransform(c.ptr, C.ColorSpaceDirection(dir)))
+	runtime.KeepAlive(c)
+	return tx, err
+}
+
+// SetTransform sets the transform used to convert to (COLORSPACE_DIR_TO_REFERENCE)
+// or from (COLORSPACE_DIR_FROM_REFERENCE) the reference color space.
+// Passing a nil Transform clears it.
+// This stores a copy of the specified transform.
+func (c *ColorSpace) SetTransform(tx Transform, dir ColorSpaceDirection) {
+	C.ColorSpace_setTransform(c.ptr, transformHandleOrZero(tx), C.ColorSpaceDirection(dir))
+	runtime.KeepAlive(c)
+	runtime.KeepAlive(tx)
+}
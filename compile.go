@@ -0,0 +1,416 @@
+package ocio
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// DefaultCompileLutSize is the 3D LUT edge length used by
+// Processor.Compile when CompileOptions.LutSize is not set
+const DefaultCompileLutSize = 33
+
+// defaultAccuracySamples is the number of samples per axis used to
+// measure the accuracy of a CompiledProcessor, when not set
+const defaultAccuracySamples = 16
+
+// CompileOptions controls how Processor.Compile samples a Processor
+type CompileOptions struct {
+	// LutSize is the edge length of the sampled 3D LUT.
+	// Defaults to DefaultCompileLutSize.
+	LutSize int
+
+	// Interpolation is INTERP_LINEAR for trilinear, or INTERP_TETRAHEDRAL
+	// for tetrahedral interpolation of the 3D LUT. INTERP_UNKNOWN and
+	// INTERP_BEST use tetrahedral interpolation.
+	Interpolation InterpType
+
+	// ShaperAllocation and ShaperVars define a 1D shaper that maps input
+	// values to the 3D LUT, in the same way as an AllocationTransform.
+	//
+	// ALLOCATION_UNIFORM maps the range [min, max], given as vars, to the
+	// LUT. ALLOCATION_LG2 maps the range [min, max] in log2 stops, with an
+	// optional offset added before the log, which gives more samples to
+	// the shadows of scene-linear and HDR input. ALLOCATION_UNKNOWN is
+	// a uniform [0, 1] range. Input outside of the range is clamped.
+	ShaperAllocation Allocation
+	ShaperVars       []float32
+
+	// AccuracySamples is the number of samples, per axis, compared against
+	// the Processor to build the AccuracyReport. Defaults to 16.
+	// A negative value skips the accuracy check.
+	AccuracySamples int
+}
+
+// AccuracyReport compares a CompiledProcessor with the exact CPU path
+// of the Processor it was compiled from. Input values are sampled
+// between the 3D LUT lattice points, where interpolation error is
+// largest, over the range of the shaper.
+type AccuracyReport struct {
+	// Samples is the number of input values compared
+	Samples int
+	// MaxError is the largest absolute difference in any channel
+	MaxError float32
+	// MeanError is the mean absolute difference over all channels
+	MeanError float32
+	// MaxErrorInput is the input value with the largest error
+	MaxErrorInput [3]float32
+}
+
+func (r AccuracyReport) String() string {
+	return fmt.Sprintf("%d samples: max error %g at %v, mean error %g",
+		r.Samples, r.MaxError, r.MaxErrorInput, r.MeanError)
+}
+
+/*
+CompiledProcessor is a pure-Go approximation of a Processor, using a sampled
+3D LUT. It is safe for concurrent use, and avoids the cost of calling into
+OCIO for each image, which dominates when processing many small images.
+
+A 3D LUT is only exact for transforms that are linear between its lattice
+points, so check the AccuracyReport against a tolerance for the transform.
+*/
+type CompiledProcessor struct {
+	lut    []float32
+	size   int
+	interp InterpType
+	shaper lut1DShaper
+
+	// Accuracy compares the CompiledProcessor with the Processor.
+	// It is empty if CompileOptions.AccuracySamples was negative.
+	Accuracy AccuracyReport
+}
+
+// Compile samples the Processor into a 3D LUT, returning a
+// CompiledProcessor that applies it without calling into OCIO.
+func (p *Processor) Compile(opts CompileOptions) (*CompiledProcessor, error) {
+	if opts.LutSize == 0 {
+		opts.LutSize = DefaultCompileLutSize
+	}
+	if opts.LutSize < 2 {
+		return nil, fmt.Errorf("3D LUT size must be at least 2, got %d", opts.LutSize)
+	}
+
+	switch opts.Interpolation {
+	case INTERP_UNKNOWN, INTERP_BEST:
+		opts.Interpolation = INTERP_TETRAHEDRAL
+	case INTERP_LINEAR, INTERP_TETRAHEDRAL:
+	default:
+		return nil, fmt.Errorf("unsupported 3D LUT interpolation: %v", opts.Interpolation)
+	}
+
+	shaper, err := newLut1DShaper(opts.ShaperAllocation, opts.ShaperVars)
+	if err != nil {
+		return nil, err
+	}
+
+	lut, err := p.sampleLut3D(opts.LutSize, shaper.inverse)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &CompiledProcessor{
+		lut:    lut,
+		size:   opts.LutSize,
+		interp: opts.Interpolation,
+		shaper: shaper,
+	}
+
+	if opts.AccuracySamples == 0 {
+		opts.AccuracySamples = defaultAccuracySamples
+	}
+	if opts.AccuracySamples > 0 {
+		if c.Accuracy, err = c.accuracy(p, opts.AccuracySamples); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// LutSize returns the edge length of the 3D LUT
+func (c *CompiledProcessor) LutSize() int {
+	return c.size
+}
+
+// Interpolation returns the 3D LUT interpolation,
+// INTERP_LINEAR or INTERP_TETRAHEDRAL
+func (c *CompiledProcessor) Interpolation() InterpType {
+	return c.interp
+}
+
+// ApplyRGB applies the CompiledProcessor to a single RGB pixel
+func (c *CompiledProcessor) ApplyRGB(rgb [3]float32) [3]float32 {
+	for i := range rgb {
+		rgb[i] = c.shaper.forward(rgb[i])
+	}
+	if c.interp == INTERP_LINEAR {
+		return trilinearLut3D(c.lut, c.size, rgb)
+	}
+	return tetrahedralLut3D(c.lut, c.size, rgb)
+}
+
+// ApplyRGBA applies the CompiledProcessor to a single RGBA pixel.
+// Alpha is unchanged.
+func (c *CompiledProcessor) ApplyRGBA(rgba [4]float32) [4]float32 {
+	rgb := c.ApplyRGB([3]float32{rgba[0], rgba[1], rgba[2]})
+	return [4]float32{rgb[0], rgb[1], rgb[2], rgba[3]}
+}
+
+// Apply applies the CompiledProcessor, in place, to packed pixel data
+// with the given dimensions and number of channels. Channels after the
+// first three are unchanged. Rows are processed in parallel, using up
+// to runtime.GOMAXPROCS(0) goroutines.
+func (c *CompiledProcessor) Apply(data ColorData, width, height, numChannels int) error {
+	if err := checkBufferSize(len(data), width, height, numChannels); err != nil {
+		return err
+	}
+
+	rowSize := width * numChannels
+	applyRows := func(y0, y1 int) {
+		for i := y0 * rowSize; i < y1*rowSize; i += numChannels {
+			rgb := c.ApplyRGB([3]float32{data[i], data[i+1], data[i+2]})
+			data[i], data[i+1], data[i+2] = rgb[0], rgb[1], rgb[2]
+		}
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > height {
+		workers = height
+	}
+	if workers <= 1 {
+		applyRows(0, height)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	bandRows := (height + workers - 1) / workers
+	for y := 0; y < height; y += bandRows {
+		y1 := y + bandRows
+		if y1 > height {
+			y1 = height
+		}
+		wg.Add(1)
+		go func(y0, y1 int) {
+			defer wg.Done()
+			applyRows(y0, y1)
+		}(y, y1)
+	}
+	wg.Wait()
+	return nil
+}
+
+// accuracy compares the CompiledProcessor with the Processor at the
+// centers of samples^3 lattice cells, spread over the range of the shaper
+func (c *CompiledProcessor) accuracy(p *Processor, samples int) (AccuracyReport, error) {
+	cells := c.size - 1
+	pixels := make([][3]float32, 0, samples*samples*samples)
+	for b := 0; b < samples; b++ {
+		for g := 0; g < samples; g++ {
+			for r := 0; r < samples; r++ {
+				var px [3]float32
+				for i, idx := range [3]int{r, g, b} {
+					cell := idx * cells / samples
+					px[i] = c.shaper.inverse(i, (float32(cell)+0.5)/float32(cells))
+				}
+				pixels = append(pixels, px)
+			}
+		}
+	}
+
+	expect := make([][3]float32, len(pixels))
+	copy(expect, pixels)
+	if err := p.ApplyRGBPixels(expect); err != nil {
+		return AccuracyReport{}, err
+	}
+
+	report := AccuracyReport{Samples: len(pixels)}
+	var sum float64
+	for i, px := range pixels {
+		actual := c.ApplyRGB(px)
+		for ch := range actual {
+			d := float32(math.Abs(float64(actual[ch] - expect[i][ch])))
+			sum += float64(d)
+			if d > report.MaxError || d != d {
+				report.MaxError = d
+				report.MaxErrorInput = px
+			}
+		}
+	}
+	report.MeanError = float32(sum / float64(len(pixels)*3))
+	return report, nil
+}
+
+// lut1DShaper maps input values to the normalized [0, 1] range of
+// a 3D LUT, following the AllocationTransform math
+type lut1DShaper struct {
+	log2          bool
+	min, max      float32
+	offset        float32
+	rangeMin, inv float32
+}
+
+func newLut1DShaper(alloc Allocation, vars []float32) (lut1DShaper, error) {
+	s := lut1DShaper{min: 0, max: 1}
+	switch alloc {
+	case ALLOCATION_UNKNOWN, ALLOCATION_UNIFORM:
+		if len(vars) != 0 && len(vars) != 2 {
+			return s, fmt.Errorf("uniform shaper requires 2 vars [min, max], got %v", vars)
+		}
+	case ALLOCATION_LG2:
+		s.log2 = true
+		s.min, s.max = -10, 6
+		if len(vars) != 0 && len(vars) != 2 && len(vars) != 3 {
+			return s, fmt.Errorf("lg2 shaper requires 2 or 3 vars [min, max, offset], got %v", vars)
+		}
+		if len(vars) == 3 {
+			s.offset = vars[2]
+		}
+	default:
+		return s, fmt.Errorf("unsupported shaper allocation: %v", alloc)
+	}
+	if len(vars) >= 2 {
+		s.min, s.max = vars[0], vars[1]
+	}
+	if !(s.max > s.min) {
+		return s, fmt.Errorf("shaper max must be greater than min, got %v", vars)
+	}
+	s.inv = 1 / (s.max - s.min)
+	return s, nil
+}
+
+// forward maps an input value to [0, 1]
+func (s lut1DShaper) forward(v float32) float32 {
+	if s.log2 {
+		x := float64(v + s.offset)
+		if x < math.SmallestNonzeroFloat32 {
+			x = math.SmallestNonzeroFloat32
+		}
+		v = float32(math.Log2(x))
+	}
+	return clampUnit((v - s.min) * s.inv)
+}
+
+// inverse maps a normalized [0, 1] value to an input value.
+// The channel is ignored, since the shaper is the same for each.
+func (s lut1DShaper) inverse(channel int, t float32) float32 {
+	v := s.min + t*(s.max-s.min)
+	if s.log2 {
+		v = float32(math.Exp2(float64(v))) - s.offset
+	}
+	return v
+}
+
+// sampleLut3D applies the Processor to a size^3 lattice, returning
+// RGB values with red changing fastest. input maps a normalized
+// [0, 1] lattice coordinate of a channel to the input value.
+func (p *Processor) sampleLut3D(size int, input func(channel int, t float32) float32) ([]float32, error) {
+	pixels := make([][3]float32, 0, size*size*size)
+	last := float32(size - 1)
+	for b := 0; b < size; b++ {
+		for g := 0; g < size; g++ {
+			for r := 0; r < size; r++ {
+				var px [3]float32
+				for c, idx := range [3]int{r, g, b} {
+					px[c] = input(c, float32(idx)/last)
+				}
+				pixels = append(pixels, px)
+			}
+		}
+	}
+	if err := p.ApplyRGBPixels(pixels); err != nil {
+		return nil, err
+	}
+	lut := make([]float32, 0, len(pixels)*3)
+	for _, px := range pixels {
+		lut = append(lut, px[0], px[1], px[2])
+	}
+	return lut, nil
+}
+
+// lut3DCell finds the lattice cell containing a normalized [0, 1]
+// coordinate, returning the index of its lower corner along each
+// axis and the fractional position within it
+func lut3DCell(size int, rgb [3]float32) (idx [3]int, frac [3]float32) {
+	last := size - 1
+	for c, v := range rgb {
+		pos := v * float32(last)
+		i := int(pos)
+		if i >= last {
+			i = last - 1
+		}
+		idx[c] = i
+		frac[c] = pos - float32(i)
+	}
+	return idx, frac
+}
+
+// trilinearLut3D interpolates a 3D LUT, with red changing fastest,
+// at a normalized [0, 1] coordinate
+func trilinearLut3D(lut []float32, size int, rgb [3]float32) [3]float32 {
+	idx, frac := lut3DCell(size, rgb)
+	base := 3 * (idx[0] + size*(idx[1]+size*idx[2]))
+	dr, dg, db := 3, 3*size, 3*size*size
+
+	var out [3]float32
+	for c := 0; c < 3; c++ {
+		i := base + c
+		c000, c100 := lut[i], lut[i+dr]
+		c010, c110 := lut[i+dg], lut[i+dg+dr]
+		c001, c101 := lut[i+db], lut[i+db+dr]
+		c011, c111 := lut[i+db+dg], lut[i+db+dg+dr]
+
+		c00 := c000 + (c100-c000)*frac[0]
+		c10 := c010 + (c110-c010)*frac[0]
+		c01 := c001 + (c101-c001)*frac[0]
+		c11 := c011 + (c111-c011)*frac[0]
+		c0 := c00 + (c10-c00)*frac[1]
+		c1 := c01 + (c11-c01)*frac[1]
+		out[c] = c0 + (c1-c0)*frac[2]
+	}
+	return out
+}
+
+// tetrahedralLut3D interpolates a 3D LUT, with red changing fastest,
+// at a normalized [0, 1] coordinate, between the 4 corners of the
+// tetrahedron within the lattice cell that contains it
+func tetrahedralLut3D(lut []float32, size int, rgb [3]float32) [3]float32 {
+	idx, frac := lut3DCell(size, rgb)
+	base := 3 * (idx[0] + size*(idx[1]+size*idx[2]))
+	dr, dg, db := 3, 3*size, 3*size*size
+	fr, fg, fb := frac[0], frac[1], frac[2]
+
+	// Offsets of the two intermediate corners, and their weights,
+	// for the tetrahedron selected by the order of the fractions
+	var (
+		o1, o2         int
+		w0, w1, w2, w3 float32
+	)
+	switch {
+	case fr > fg && fg > fb:
+		o1, o2 = dr, dr+dg
+		w0, w1, w2, w3 = 1-fr, fr-fg, fg-fb, fb
+	case fr > fg && fr > fb:
+		o1, o2 = dr, dr+db
+		w0, w1, w2, w3 = 1-fr, fr-fb, fb-fg, fg
+	case fr > fg:
+		o1, o2 = db, db+dr
+		w0, w1, w2, w3 = 1-fb, fb-fr, fr-fg, fg
+	case fb > fg:
+		o1, o2 = db, db+dg
+		w0, w1, w2, w3 = 1-fb, fb-fg, fg-fr, fr
+	case fb > fr:
+		o1, o2 = dg, dg+db
+		w0, w1, w2, w3 = 1-fg, fg-fb, fb-fr, fr
+	default:
+		o1, o2 = dg, dg+dr
+		w0, w1, w2, w3 = 1-fg, fg-fr, fr-fb, fb
+	}
+
+	var out [3]float32
+	for c := 0; c < 3; c++ {
+		i := base + c
+		out[c] = w0*lut[i] + w1*lut[i+o1] + w2*lut[i+o2] + w3*lut[i+dr+dg+db]
+	}
+	return out
+}
@@ -0,0 +1,197 @@
+package ocio
+
+import "testing"
+
+// getCrosstalkProcessor returns a non-linear Processor with crosstalk
+// between the channels, for which 3D LUT interpolation is inexact
+func getCrosstalkProcessor(t *testing.T) *Processor {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	mtx := NewMatrixTransform()
+	defer mtx.Destroy()
+	mtx.SetMatrix([16]float32{
+		0.6, 0.3, 0.1, 0,
+		0.2, 0.7, 0.1, 0,
+		0.1, 0.2, 0.7, 0,
+		0, 0, 0, 1,
+	})
+	exp := NewExponentTransform()
+	defer exp.Destroy()
+	exp.SetValue([4]float32{2.2, 2.2, 2.2, 1})
+
+	group := NewGroupTransform()
+	defer group.Destroy()
+	group.Push(mtx)
+	group.Push(exp)
+
+	proc, err := cfg.ProcessorTransform(group)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return proc
+}
+
+func TestProcessorCompile(t *testing.T) {
+	proc := getCrosstalkProcessor(t)
+	defer proc.Destroy()
+
+	compiled := make(map[InterpType]*CompiledProcessor)
+	for _, interp := range []InterpType{INTERP_LINEAR, INTERP_TETRAHEDRAL} {
+		c, err := proc.Compile(CompileOptions{LutSize: 5, Interpolation: interp})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if c.LutSize() != 5 || c.Interpolation() != interp {
+			t.Errorf("expected LUT size 5 and interpolation %v, got %d and %v",
+				interp, c.LutSize(), c.Interpolation())
+		}
+		if c.Accuracy.Samples != 16*16*16 || c.Accuracy.MaxError <= 0 {
+			t.Errorf("interpolation %v: expected an inexact result over %d samples, got %v",
+				interp, 16*16*16, c.Accuracy)
+		}
+
+		// Lattice points are sampled exactly
+		in := [3]float32{0.25, 0.5, 1}
+		expect, err := proc.ApplyRGB(in)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		actual := c.ApplyRGB(in)
+		for i := range expect {
+			if d := actual[i] - expect[i]; d > 1e-5 || d < -1e-5 {
+				t.Errorf("interpolation %v: expected %v at a lattice point, got %v", interp, expect, actual)
+				break
+			}
+		}
+		compiled[interp] = c
+	}
+
+	// Tetrahedral interpolation of a gray value only uses the two lattice
+	// points on the diagonal of its cell, while trilinear uses all eight
+	lo, err := proc.ApplyRGB([3]float32{0.25, 0.25, 0.25})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	hi, err := proc.ApplyRGB([3]float32{0.5, 0.5, 0.5})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	gray := [3]float32{0.3, 0.3, 0.3}
+	tetrahedral := compiled[INTERP_TETRAHEDRAL].ApplyRGB(gray)
+	trilinear := compiled[INTERP_LINEAR].ApplyRGB(gray)
+	for i := range lo {
+		expect := lo[i]*0.8 + hi[i]*0.2
+		if d := tetrahedral[i] - expect; d > 1e-5 || d < -1e-5 {
+			t.Errorf("expected tetrahedral %v on the gray axis, got %v", expect, tetrahedral[i])
+		}
+		if d := trilinear[i] - expect; d < 1e-3 && d > -1e-3 {
+			t.Errorf("expected trilinear to differ from tetrahedral on the gray axis, got %v and %v",
+				trilinear[i], tetrahedral[i])
+		}
+	}
+}
+
+func TestProcessorCompileShaper(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	proc, err := cfg.ProcessorFromNames("lnf", "lg10")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer proc.Destroy()
+
+	uniform, err := proc.Compile(CompileOptions{
+		ShaperAllocation: ALLOCATION_UNIFORM,
+		ShaperVars:       []float32{0, 64},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	lg2, err := proc.Compile(CompileOptions{
+		ShaperAllocation: ALLOCATION_LG2,
+		ShaperVars:       []float32{-15, 6},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Logf("uniform shaper: %v", uniform.Accuracy)
+	t.Logf("lg2 shaper: %v", lg2.Accuracy)
+
+	if lg2.Accuracy.MaxError > 0.01 {
+		t.Errorf("expected an lg2 shaper to be accurate within 0.01, got %v", lg2.Accuracy)
+	}
+	if lg2.Accuracy.MaxError >= uniform.Accuracy.MaxError {
+		t.Errorf("expected an lg2 shaper to be more accurate than uniform for scene-linear input")
+	}
+
+	// Input is clamped to the range of the shaper
+	if actual, expect := lg2.ApplyRGB([3]float32{-1, -1, -1}), lg2.ApplyRGB([3]float32{}); actual != expect {
+		t.Errorf("expected negative input to be clamped, got %v and %v", actual, expect)
+	}
+}
+
+func TestCompiledProcessorApply(t *testing.T) {
+	proc := getMatrixProcessor(t, 2, -0.1)
+	defer proc.Destroy()
+
+	compiled, err := proc.Compile(CompileOptions{AccuracySamples: -1})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if compiled.Accuracy.Samples != 0 {
+		t.Errorf("expected the accuracy check to be skipped, got %v", compiled.Accuracy)
+	}
+
+	width, height, channels := 37, 23, 4
+	data := getImageData(width, height, channels)
+	orig := make(ColorData, len(data))
+	copy(orig, data)
+
+	if err = compiled.Apply(data, width, height, channels); err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 0; i < len(data); i += channels {
+		expect := compiled.ApplyRGBA([4]float32{orig[i], orig[i+1], orig[i+2], orig[i+3]})
+		actual := [4]float32{data[i], data[i+1], data[i+2], data[i+3]}
+		if actual != expect {
+			t.Fatalf("pixel %d: expected %v, got %v", i/channels, expect, actual)
+		}
+	}
+
+	if err = compiled.Apply(data, width, height+1, channels); err == nil {
+		t.Error("expected an error for a buffer that is too small")
+	}
+}
+
+func TestProcessorCompileOptions(t *testing.T) {
+	proc := getMatrixProcessor(t, 1, 0)
+	defer proc.Destroy()
+
+	for _, opts := range []CompileOptions{
+		{LutSize: 1},
+		{Interpolation: INTERP_NEAREST},
+		{ShaperAllocation: ALLOCATION_UNIFORM, ShaperVars: []float32{1}},
+		{ShaperAllocation: ALLOCATION_LG2, ShaperVars: []float32{6, -10}},
+	} {
+		if _, err := proc.Compile(opts); err == nil {
+			t.Errorf("expected an error for options %+v", opts)
+		}
+	}
+
+	compiled, err := proc.Compile(CompileOptions{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if compiled.LutSize() != DefaultCompileLutSize || compiled.Interpolation() != INTERP_TETRAHEDRAL {
+		t.Errorf("expected default LUT size and tetrahedral interpolation, got %d and %v",
+			compiled.LutSize(), compiled.Interpolation())
+	}
+}
@@ -9,7 +9,9 @@
 #include "ocio_abi.h"
 #include "storage.h"
 #include "colorspace.h"
+#include "config.h"
 #include "context.h"
+#include "look.h"
 #include "processor.h"
 #include "transform.h"
 
@@ -93,6 +95,13 @@ extern "C" {
         return cpy;
     }
 
+    // Create a new handle to the same underlying Config,
+    // with its own error context
+    Config* Config_share(Config* p) {
+        return NEW_HANDLE_CONTEXT(ocigo::g_Config_map.add(
+                ocigo::g_Config_map.get(p->handle)));
+    }
+
     void Config_sanityCheck(Config* p) {
         BEGIN_CATCH_CTX_ERR(p)
         ocigo::g_Config_map.get(p->handle).get()->sanityCheck();
@@ -156,6 +165,42 @@ extern "C" {
         return ret;
     }
 
+    int Config_getNumEnvironmentVars(Config* p) {
+        int ret = 0;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Config_map.get(p->handle).get()->getNumEnvironmentVars();
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    const char* Config_getEnvironmentVarNameByIndex(Config* p, int index) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Config_map.get(p->handle).get()->getEnvironmentVarNameByIndex(index);
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    const char* Config_getEnvironmentVarDefault(Config* p, const char* name) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Config_map.get(p->handle).get()->getEnvironmentVarDefault(name);
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Config_addEnvironmentVar(Config* p, const char* name, const char* defaultValue) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Config_map.get(p->handle).get()->addEnvironmentVar(name, defaultValue);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    void Config_clearEnvironmentVars(Config* p) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Config_map.get(p->handle).get()->clearEnvironmentVars();
+        END_CATCH_CTX_ERR(p)
+    }
+
     const char* Config_getWorkingDir(Config* p) {
         const char* ret = NULL;
         BEGIN_CATCH_CTX_ERR(p)
@@ -460,4 +505,58 @@ extern "C" {
         return ret;
     }
 
+    // Config Luma
+    void Config_getDefaultLumaCoefs(Config* p, float* rgb) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Config_map.get(p->handle).get()->getDefaultLumaCoefs(rgb);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    void Config_setDefaultLumaCoefs(Config* p, const float* rgb) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Config_map.get(p->handle).get()->setDefaultLumaCoefs(rgb);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    // Config Looks
+    LookId Config_getLook(Config* p, const char* name) {
+        OCIO::ConstLookRcPtr ptr;
+
+        BEGIN_CATCH_CTX_ERR(p)
+        ptr = ocigo::g_Config_map.get(p->handle).get()->getLook(name);
+        END_CATCH_CTX_ERR(p)
+
+        if ( ptr == NULL) { return 0; }
+        return ocigo::g_Look_map.add(OCIO_CONST_POINTER_CAST<OCIO::Look>(ptr));
+    }
+
+    int Config_getNumLooks(Config* p) {
+        int ret = 0;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Config_map.get(p->handle).get()->getNumLooks();
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    const char* Config_getLookNameByIndex(Config* p, int index) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        ret = ocigo::g_Config_map.get(p->handle).get()->getLookNameByIndex(index);
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Config_addLook(Config* p, LookId look) {
+        OCIO::ConstLookRcPtr look_ptr = ocigo::g_Look_map.get(look);
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Config_map.get(p->handle).get()->addLook(look_ptr);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    void Config_clearLooks(Config* p) {
+        BEGIN_CATCH_CTX_ERR(p)
+        ocigo::g_Config_map.get(p->handle).get()->clearLooks();
+        END_CATCH_CTX_ERR(p)
+    }
+
 }
\ No newline at end of file
@@ -8,9 +8,9 @@ package ocio
 import "C"
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"reflect"
 	"runtime"
 	"unsafe"
 )
@@ -52,7 +52,7 @@ func NewConfig() *Config {
 // initialized from the environment.
 func CurrentConfig() (*Config, error) {
 	c, err := C.GetCurrentConfig()
-	if err = getLastError((*C._HandleContext)(c), err); err != nil {
+	if err = getLastError("CurrentConfig", "Config", (*C._HandleContext)(c), err); err != nil {
 		return nil, err
 	}
 	return newConfig(c), nil
@@ -68,7 +68,7 @@ func SetCurrentConfig(config *Config) error {
 // Create a Config by checking the OCIO environment variable
 func ConfigCreateFromEnv() (*Config, error) {
 	c, err := C.Config_CreateFromEnv()
-	if err = getLastError((*C._HandleContext)(c), err); err != nil {
+	if err = getLastError("ConfigCreateFromEnv", "Config", (*C._HandleContext)(c), err); err != nil {
 		return nil, err
 	}
 	return newConfig(c), nil
@@ -80,7 +80,7 @@ func ConfigCreateFromFile(filename string) (*Config, error) {
 	defer C.free(unsafe.Pointer(c_str))
 
 	c, err := C.Config_CreateFromFile(c_str)
-	if err = getLastError((*C._HandleContext)(c), err); err != nil {
+	if err = getLastError("ConfigCreateFromFile", "Config", (*C._HandleContext)(c), err); err != nil {
 		return nil, err
 	}
 	return newConfig(c), nil
@@ -92,12 +92,62 @@ func ConfigCreateFromData(data string) (*Config, error) {
 	defer C.free(unsafe.Pointer(c_str))
 
 	c, err := C.Config_CreateFromData(c_str)
-	if err = getLastError((*C._HandleContext)(c), err); err != nil {
+	if err = getLastError("ConfigCreateFromData", "Config", (*C._HandleContext)(c), err); err != nil {
 		return nil, err
 	}
 	return newConfig(c), nil
 }
 
+// ConfigCreateFromEnvContext is like ConfigCreateFromEnv, but returns
+// ctx.Err() as soon as the context is cancelled. The OCIO call itself
+// cannot be interrupted, so it continues in the background and its
+// result is discarded.
+func ConfigCreateFromEnvContext(ctx context.Context) (*Config, error) {
+	return configWithContext(ctx, ConfigCreateFromEnv)
+}
+
+// ConfigCreateFromFileContext is like ConfigCreateFromFile, but returns
+// ctx.Err() as soon as the context is cancelled, which is useful when
+// reading from slow network storage. The OCIO call itself cannot be
+// interrupted, so it continues in the background and its result is
+// discarded.
+func ConfigCreateFromFileContext(ctx context.Context, filename string) (*Config, error) {
+	return configWithContext(ctx, func() (*Config, error) {
+		return ConfigCreateFromFile(filename)
+	})
+}
+
+// configWithContext runs a Config constructor in the background,
+// returning early if ctx is cancelled. A Config that is created
+// after cancellation is destroyed.
+func configWithContext(ctx context.Context, create func() (*Config, error)) (*Config, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		cfg *Config
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		cfg, err := create()
+		done <- result{cfg, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.cfg, res.err
+	case <-ctx.Done():
+		go func() {
+			if res := <-done; res.cfg != nil {
+				res.cfg.Destroy()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
 // Destroy immediately frees resources for this
 // instance instead of waiting for garbage collection
 // finalizer to run at some point later
@@ -105,15 +155,24 @@ func (c *Config) Destroy() {
 	deleteConfig(c)
 }
 
-func (c *Config) lastError(errno ...error) error {
+func (c *Config) lastError(op string, errno ...error) error {
 	if c == nil {
 		return nil
 	}
-	err := getLastError(c.ptr, errno...)
+	err := getLastError(op, "Config", c.ptr, errno...)
 	runtime.KeepAlive(c)
 	return err
 }
 
+// share returns a new Config handle to the same underlying
+// OCIO Config. Each handle has its own error context, so
+// shared handles can be used safely from separate goroutines.
+func (c *Config) share() *Config {
+	ret := newConfig(C.Config_share(c.ptr))
+	runtime.KeepAlive(c)
+	return ret
+}
+
 // Create a new editable copy of this Config
 func (c *Config) EditableCopy() *Config {
 	ret := newConfig(C.Config_createEditableCopy(c.ptr))
@@ -125,14 +184,14 @@ func (c *Config) EditableCopy() *Config {
 // The most common error occurs when references are made to colorspaces that do not exist.
 func (c *Config) SanityCheck() error {
 	_, err := C.Config_sanityCheck(c.ptr)
-	err = c.lastError(err)
+	err = c.lastError("Config.SanityCheck", err)
 	runtime.KeepAlive(c)
 	return err
 }
 
 func (c *Config) Serialize() (string, error) {
 	c_str, err := C.Config_serialize(c.ptr)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.Serialize", err); err != nil {
 		return "", err
 	}
 	defer C.free(unsafe.Pointer(c_str))
@@ -151,7 +210,7 @@ The current Context will be used.
 */
 func (c *Config) CacheID() (string, error) {
 	id, err := C.Config_getCacheID(c.ptr)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.CacheID", err); err != nil {
 		return "", err
 	}
 	runtime.KeepAlive(c)
@@ -174,7 +233,7 @@ func (c *Config) CacheIDWithContext(context *Context) (string, error) {
 	}
 
 	id, err := C.Config_getCacheIDWithContext(c.ptr, context.ptr)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.CacheIDWithContext", err); err != nil {
 		return "", err
 	}
 	runtime.KeepAlive(c)
@@ -184,7 +243,7 @@ func (c *Config) CacheIDWithContext(context *Context) (string, error) {
 
 func (c *Config) Description() (string, error) {
 	d, err := C.Config_getDescription(c.ptr)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.Description", err); err != nil {
 		return "", err
 	}
 	runtime.KeepAlive(c)
@@ -193,7 +252,7 @@ func (c *Config) Description() (string, error) {
 
 func (c *Config) IsStrictParsingEnabled() bool {
 	enabled, err := C.Config_isStrictParsingEnabled(c.ptr)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.IsStrictParsingEnabled", err); err != nil {
 		return false
 	}
 	runtime.KeepAlive(c)
@@ -202,7 +261,7 @@ func (c *Config) IsStrictParsingEnabled() bool {
 
 func (c *Config) SetStrictParsingEnabled(enabled bool) error {
 	_, err := C.Config_setStrictParsingEnabled(c.ptr, C.bool(enabled))
-	err = c.lastError(err)
+	err = c.lastError("Config.SetStrictParsingEnabled", err)
 	runtime.KeepAlive(c)
 	return err
 }
@@ -213,7 +272,7 @@ Config Resources
 
 func (c *Config) CurrentContext() (*Context, error) {
 	ptr, err := C.Config_getCurrentContext(c.ptr)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.CurrentContext", err); err != nil {
 		return nil, err
 	}
 	runtime.KeepAlive(c)
@@ -223,23 +282,88 @@ func (c *Config) CurrentContext() (*Context, error) {
 // Given a lut src name, where should we find it?
 func (c *Config) SearchPath() (string, error) {
 	path, err := C.Config_getSearchPath(c.ptr)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.SearchPath", err); err != nil {
 		return "", err
 	}
 	runtime.KeepAlive(c)
 	return C.GoString(path), nil
 }
 
+// SearchPaths returns the entries of the colon-delimited search path,
+// in the order they are searched. Entries may contain context variables,
+// and relative entries are relative to the WorkingDir.
+func (c *Config) SearchPaths() ([]string, error) {
+	path, err := c.SearchPath()
+	if err != nil {
+		return nil, err
+	}
+	return splitSearchPath(path), nil
+}
+
 // Given a lut src name, where should we find it?
 func (c *Config) WorkingDir() (string, error) {
 	dir, err := C.Config_getWorkingDir(c.ptr)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.WorkingDir", err); err != nil {
 		return "", err
 	}
 	runtime.KeepAlive(c)
 	return C.GoString(dir), nil
 }
 
+/*
+Config Environment
+*/
+
+/*
+Environment returns the context variables declared in the "environment"
+section of the Config, mapped to their default values. If the Config does
+not declare an environment, all environment variables are loaded and
+this map is empty.
+*/
+func (c *Config) Environment() (map[string]string, error) {
+	num, err := C.Config_getNumEnvironmentVars(c.ptr)
+	if err = c.lastError("Config.Environment", err); err != nil {
+		return nil, err
+	}
+	env := make(map[string]string, int(num))
+	for i := 0; i < int(num); i++ {
+		c_name, err := C.Config_getEnvironmentVarNameByIndex(c.ptr, C.int(i))
+		if err = c.lastError("Config.Environment", err); err != nil {
+			return nil, err
+		}
+		c_val, err := C.Config_getEnvironmentVarDefault(c.ptr, c_name)
+		if err = c.lastError("Config.Environment", err); err != nil {
+			return nil, err
+		}
+		env[C.GoString(c_name)] = C.GoString(c_val)
+	}
+	runtime.KeepAlive(c)
+	return env, nil
+}
+
+// AddEnvironmentVar declares a context variable, and
+// its default value, in an editable Config
+func (c *Config) AddEnvironmentVar(name, defaultValue string) error {
+	c_name := C.CString(name)
+	c_val := C.CString(defaultValue)
+	defer C.free(unsafe.Pointer(c_name))
+	defer C.free(unsafe.Pointer(c_val))
+
+	_, err := C.Config_addEnvironmentVar(c.ptr, c_name, c_val)
+	err = c.lastError("Config.AddEnvironmentVar", err)
+	runtime.KeepAlive(c)
+	return err
+}
+
+// ClearEnvironmentVars removes all of the declared
+// context variables from an editable Config
+func (c *Config) ClearEnvironmentVars() error {
+	_, err := C.Config_clearEnvironmentVars(c.ptr)
+	err = c.lastError("Config.ClearEnvironmentVars", err)
+	runtime.KeepAlive(c)
+	return err
+}
+
 /*
 Config Processors
 */
@@ -261,6 +385,10 @@ For example, if the inputColorspace and the outputColorSpace are members of the
 same family, no conversion will be applied, even though strictly speaking
 quantization should be added
 
+Processor is a wrapper around the type-safe ProcessorFromNames,
+ProcessorFromColorSpaces, ProcessorCtxFromNames and ProcessorCtxFromColorSpaces,
+which should be preferred in new code since argument errors are caught
+at compile time.
 */
 func (c *Config) Processor(args ...interface{}) (*Processor, error) {
 	count := len(args)
@@ -268,81 +396,242 @@ func (c *Config) Processor(args ...interface{}) (*Processor, error) {
 		return nil, fmt.Errorf("Requires either 2 or 3 parameters; Got %d", count)
 	}
 
-	var (
-		err  error
-		proc C.ProcessorId
-	)
-
-	bad_str := "Error creating processor with src colorspace %v / dst colorspace %v"
-
+	var ct *Context
 	if count == 3 {
-
-		ct, ok := args[0].(*Context)
-		if !ok {
+		var ok bool
+		if ct, ok = args[0].(*Context); !ok {
 			return nil, errors.New("1st argument is not a Context*")
 		}
+		if ct == nil {
+			return nil, errors.New("Context is nil")
+		}
+		args = args[1:]
+	}
 
-		a1 := reflect.ValueOf(args[1])
-		a2 := reflect.ValueOf(args[2])
-
-		if a1.Kind() == reflect.String && a2.Kind() == reflect.String {
-			c_a1 := C.CString(a1.String())
-			c_a2 := C.CString(a2.String())
-			defer C.free(unsafe.Pointer(c_a1))
-			defer C.free(unsafe.Pointer(c_a2))
-
-			proc, err = C.Config_getProcessor_CT_S_S(c.ptr, ct.ptr, c_a1, c_a2)
-			if err = c.lastError(err); err != nil {
-				err = fmt.Errorf("%s: %s", fmt.Sprintf(bad_str, a1, a2), err)
+	switch src := args[0].(type) {
+	case string:
+		if dst, ok := args[1].(string); ok {
+			if ct != nil {
+				return c.ProcessorCtxFromNames(ct, src, dst)
 			}
-			return newProcessor(proc), err
+			return c.ProcessorFromNames(src, dst)
 		}
-
-		if a1.Kind() == reflect.Ptr && a2.Kind() == reflect.Ptr {
-			if aPtr1, ok := args[1].(*ColorSpace); ok {
-				if aPtr2, ok := args[2].(*ColorSpace); ok {
-					proc, err = C.Config_getProcessor_CT_CS_CS(c.ptr, ct.ptr, aPtr1.ptr, aPtr2.ptr)
-					if err = c.lastError(err); err != nil {
-						err = fmt.Errorf("%s: %s", fmt.Sprintf(bad_str, a1, a2), err)
-					}
-					return newProcessor(proc), err
-				}
+	case *ColorSpace:
+		if dst, ok := args[1].(*ColorSpace); ok {
+			if ct != nil {
+				return c.ProcessorCtxFromColorSpaces(ct, src, dst)
 			}
+			return c.ProcessorFromColorSpaces(src, dst)
 		}
+	}
 
-	} else if count == 2 {
+	return nil, fmt.Errorf("Wrong argument types: src %T / dst %T; "+
+		"expected both string or both *ColorSpace", args[0], args[1])
+}
 
-		a1 := reflect.ValueOf(args[0])
-		a2 := reflect.ValueOf(args[1])
+const badProcessorArgs = "Error creating processor with src colorspace %v / dst colorspace %v"
 
-		if a1.Kind() == reflect.String && a2.Kind() == reflect.String {
-			c_a1 := C.CString(a1.String())
-			c_a2 := C.CString(a2.String())
-			defer C.free(unsafe.Pointer(c_a1))
-			defer C.free(unsafe.Pointer(c_a2))
+// ProcessorFromNames returns a Processor converting from the src to the
+// dst colorspace, using the current Config context. Names can be a
+// colorspace name, a role name, or a combination of both.
+func (c *Config) ProcessorFromNames(src, dst string) (*Processor, error) {
+	c_src := C.CString(src)
+	c_dst := C.CString(dst)
+	defer C.free(unsafe.Pointer(c_src))
+	defer C.free(unsafe.Pointer(c_dst))
 
-			proc, err = C.Config_getProcessor_S_S(c.ptr, c_a1, c_a2)
-			if err = c.lastError(err); err != nil {
-				err = fmt.Errorf("%s: %s", fmt.Sprintf(bad_str, a1, a2), err)
-			}
-			return newProcessor(proc), err
+	ptr, err := C.Config_getProcessor_S_S(c.ptr, c_src, c_dst)
+	runtime.KeepAlive(c)
+	if err = c.lastError("Config.ProcessorFromNames", err); err != nil {
+		return nil, fmt.Errorf("%s: %w", fmt.Sprintf(badProcessorArgs, src, dst), err)
+	}
+	return newProcessor(ptr), nil
+}
+
+// ProcessorCtxFromNames is like ProcessorFromNames, but uses a specific
+// Context instead of the current Config context.
+func (c *Config) ProcessorCtxFromNames(ctx *Context, src, dst string) (*Processor, error) {
+	if ctx == nil {
+		return nil, errors.New("Context is nil")
+	}
+	c_src := C.CString(src)
+	c_dst := C.CString(dst)
+	defer C.free(unsafe.Pointer(c_src))
+	defer C.free(unsafe.Pointer(c_dst))
+
+	ptr, err := C.Config_getProcessor_CT_S_S(c.ptr, ctx.ptr, c_src, c_dst)
+	runtime.KeepAlive(c)
+	runtime.KeepAlive(ctx)
+	if err = c.lastError("Config.ProcessorCtxFromNames", err); err != nil {
+		return nil, fmt.Errorf("%s: %w", fmt.Sprintf(badProcessorArgs, src, dst), err)
+	}
+	return newProcessor(ptr), nil
+}
+
+// ProcessorFromColorSpaces returns a Processor converting from the src
+// to the dst ColorSpace, using the current Config context.
+func (c *Config) ProcessorFromColorSpaces(src, dst *ColorSpace) (*Processor, error) {
+	if src == nil || dst == nil {
+		return nil, errors.New("src and dst ColorSpace must not be nil")
+	}
+	ptr, err := C.Config_getProcessor_CS_CS(c.ptr, src.ptr, dst.ptr)
+	runtime.KeepAlive(c)
+	runtime.KeepAlive(src)
+	runtime.KeepAlive(dst)
+	if err = c.lastError("Config.ProcessorFromColorSpaces", err); err != nil {
+		return nil, fmt.Errorf("%s: %w", fmt.Sprintf(badProcessorArgs, src, dst), err)
+	}
+	return newProcessor(ptr), nil
+}
+
+// ProcessorCtxFromColorSpaces is like ProcessorFromColorSpaces, but uses
+// a specific Context instead of the current Config context.
+func (c *Config) ProcessorCtxFromColorSpaces(ctx *Context, src, dst *ColorSpace) (*Processor, error) {
+	if ctx == nil {
+		return nil, errors.New("Context is nil")
+	}
+	if src == nil || dst == nil {
+		return nil, errors.New("src and dst ColorSpace must not be nil")
+	}
+	ptr, err := C.Config_getProcessor_CT_CS_CS(c.ptr, ctx.ptr, src.ptr, dst.ptr)
+	runtime.KeepAlive(c)
+	runtime.KeepAlive(ctx)
+	runtime.KeepAlive(src)
+	runtime.KeepAlive(dst)
+	if err = c.lastError("Config.ProcessorCtxFromColorSpaces", err); err != nil {
+		return nil, fmt.Errorf("%s: %w", fmt.Sprintf(badProcessorArgs, src, dst), err)
+	}
+	return newProcessor(ptr), nil
+}
+
+// A ProcessorOption configures the Processor built by ProcessorWithOptions
+type ProcessorOption func(*processorOptions)
+
+type processorOptions struct {
+	ctx *Context
+
+	// Number of conversions that have been set,
+	// of which exactly one is required
+	numSources int
+
+	srcName, dstName string
+	srcCS, dstCS     *ColorSpace
+	tx               Transform
+	dir              TransformDirection
+}
+
+// WithContext builds the Processor using a specific Context
+// instead of the current Config context. A nil Context is ignored.
+func WithContext(ctx *Context) ProcessorOption {
+	return func(o *processorOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithNames converts from the src to the dst colorspace (or role) name
+func WithNames(src, dst string) ProcessorOption {
+	return func(o *processorOptions) {
+		o.numSources++
+		o.srcName, o.dstName = src, dst
+	}
+}
+
+// WithColorSpaces converts from the src to the dst ColorSpace
+func WithColorSpaces(src, dst *ColorSpace) ProcessorOption {
+	return func(o *processorOptions) {
+		o.numSources++
+		o.srcCS, o.dstCS = src, dst
+	}
+}
+
+// WithTransform applies a Transform in the given direction
+func WithTransform(tx Transform, dir TransformDirection) ProcessorOption {
+	return func(o *processorOptions) {
+		o.numSources++
+		o.tx, o.dir = tx, dir
+	}
+}
+
+/*
+ProcessorWithOptions returns a Processor configured by options.
+Exactly one of WithNames, WithColorSpaces or WithTransform must be given,
+and WithContext optionally selects a specific Context:
+
+	config.ProcessorWithOptions(ocio.WithNames("lnf", "srgb8"), ocio.WithContext(ctx))
+*/
+func (c *Config) ProcessorWithOptions(opts ...ProcessorOption) (*Processor, error) {
+	var o processorOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.numSources != 1 {
+		return nil, fmt.Errorf("Requires exactly one of WithNames, WithColorSpaces "+
+			"or WithTransform; Got %d", o.numSources)
+	}
+
+	switch {
+	case o.tx != nil:
+		if o.ctx != nil {
+			return c.ProcessorCtxTransformDir(o.ctx, o.tx, o.dir)
 		}
+		return c.ProcessorTransformDir(o.tx, o.dir)
 
-		if a1.Kind() == reflect.Ptr && a2.Kind() == reflect.Ptr {
-			if aPtr1, ok := args[0].(*ColorSpace); ok {
-				if aPtr2, ok := args[1].(*ColorSpace); ok {
-					proc, err = C.Config_getProcessor_CS_CS(c.ptr, aPtr1.ptr, aPtr2.ptr)
-					if err = c.lastError(err); err != nil {
-						err = fmt.Errorf("%s: %s", fmt.Sprintf(bad_str, a1, a2), err)
-					}
-					return newProcessor(proc), err
-				}
-			}
+	case o.srcCS != nil || o.dstCS != nil:
+		if o.ctx != nil {
+			return c.ProcessorCtxFromColorSpaces(o.ctx, o.srcCS, o.dstCS)
 		}
+		return c.ProcessorFromColorSpaces(o.srcCS, o.dstCS)
+
+	case o.srcName != "" || o.dstName != "":
+		if o.ctx != nil {
+			return c.ProcessorCtxFromNames(o.ctx, o.srcName, o.dstName)
+		}
+		return c.ProcessorFromNames(o.srcName, o.dstName)
 	}
 
-	runtime.KeepAlive(c)
-	return nil, fmt.Errorf("Wrong argument types: %v", args)
+	return nil, errors.New("Transform, ColorSpaces or names must not be empty")
+}
+
+/*
+ProcessorContext is like Processor, accepting the same arguments, but returns
+ctx.Err() as soon as the Go context is cancelled. Building a Processor may
+read LUT files from slow storage. The OCIO call itself cannot be interrupted,
+so it continues in the background and its result is discarded.
+
+Note that ctx is a Go context.Context used for cancellation. An OCIO *Context
+may still be passed as the first of args.
+*/
+func (c *Config) ProcessorContext(ctx context.Context, args ...interface{}) (*Processor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Use a separate handle, with its own error state,
+	// since the call may outlive this function
+	shared := c.share()
+
+	type result struct {
+		proc *Processor
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		proc, err := shared.Processor(args...)
+		shared.Destroy()
+		done <- result{proc, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.proc, res.err
+	case <-ctx.Done():
+		go func() {
+			if res := <-done; res.proc != nil {
+				res.proc.Destroy()
+			}
+		}()
+		return nil, ctx.Err()
+	}
 }
 
 /*
@@ -353,7 +642,7 @@ Not often needed, but will allow for the re-use of atomic OCIO functionality
 */
 func (c *Config) ProcessorTransform(tx Transform) (*Processor, error) {
 	ptr, err := C.Config_getProcessor_TX(c.ptr, tx.transformHandle())
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.ProcessorTransform", err); err != nil {
 		return nil, err
 	}
 	proc := newProcessor(ptr)
@@ -372,7 +661,7 @@ Not often needed, but will allow for the re-use of atomic OCIO functionality
 func (c *Config) ProcessorTransformDir(tx Transform, dir TransformDirection) (*Processor, error) {
 	ptr, err := C.Config_getProcessor_TX_D(
 		c.ptr, tx.transformHandle(), C.TransformDirection(dir))
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.ProcessorTransformDir", err); err != nil {
 		return nil, err
 	}
 	proc := newProcessor(ptr)
@@ -394,7 +683,7 @@ func (c *Config) ProcessorCtxTransformDir(
 	ptr, err := C.Config_getProcessor_CT_TX_D(
 		c.ptr, ctx.ptr, tx.transformHandle(), C.TransformDirection(dir))
 
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.ProcessorCtxTransformDir", err); err != nil {
 		return nil, err
 	}
 
@@ -416,8 +705,8 @@ func (c *Config) ColorSpace(name string) (*ColorSpace, error) {
 	defer C.free(unsafe.Pointer(c_str))
 
 	cs, err := C.Config_getColorSpace(c.ptr, c_str)
-	if err = c.lastError(err); err != nil {
-		err = fmt.Errorf("%q is not a valid ColorSpace: %v", name, err)
+	if err = c.lastError("Config.ColorSpace", err); err != nil {
+		err = fmt.Errorf("%q is not a valid ColorSpace: %w", name, err)
 		return nil, err
 	}
 	if cs == 0 {
@@ -439,7 +728,7 @@ func (c *Config) NumColorSpaces() int {
 // This will null if an invalid index is specified
 func (c *Config) ColorSpaceNameByIndex(index int) (string, error) {
 	name, err := C.Config_getColorSpaceNameByIndex(c.ptr, C.int(index))
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.ColorSpaceNameByIndex", err); err != nil {
 		return "", err
 	}
 	runtime.KeepAlive(c)
@@ -452,7 +741,7 @@ func (c *Config) IndexForColorSpace(name string) (int, error) {
 	defer C.free(unsafe.Pointer(c_str))
 
 	idx, err := C.Config_getIndexForColorSpace(c.ptr, c_str)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.IndexForColorSpace", err); err != nil {
 		return -1, err
 	}
 	runtime.KeepAlive(c)
@@ -463,14 +752,14 @@ func (c *Config) IndexForColorSpace(name string) (int, error) {
 // This stores a copy of the specified color space.
 func (c *Config) AddColorSpace(cs *ColorSpace) error {
 	_, err := C.Config_addColorSpace(c.ptr, cs.ptr)
-	err = c.lastError(err)
+	err = c.lastError("Config.AddColorSpace", err)
 	runtime.KeepAlive(c)
 	return err
 }
 
 func (c *Config) ClearColorSpaces() error {
 	_, err := C.Config_clearColorSpaces(c.ptr)
-	err = c.lastError(err)
+	err = c.lastError("Config.ClearColorSpaces", err)
 	runtime.KeepAlive(c)
 	return err
 }
@@ -488,7 +777,7 @@ func (c *Config) ParseColorSpaceFromString(str string) (string, error) {
 	defer C.free(unsafe.Pointer(c_str))
 
 	name, err := C.Config_parseColorSpaceFromString(c.ptr, c_str)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.ParseColorSpaceFromString", err); err != nil {
 		return "", err
 	}
 	runtime.KeepAlive(c)
@@ -514,14 +803,14 @@ func (c *Config) SetRole(role, colorSpaceName string) error {
 	}
 
 	_, err := C.Config_setRole(c.ptr, c_role, c_space)
-	err = c.lastError(err)
+	err = c.lastError("Config.SetRole", err)
 	runtime.KeepAlive(c)
 	return err
 }
 
 func (c *Config) NumRoles() int {
 	num, err := C.Config_getNumRoles(c.ptr)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.NumRoles", err); err != nil {
 		return 0
 	}
 	runtime.KeepAlive(c)
@@ -534,7 +823,7 @@ func (c *Config) HasRole(role string) bool {
 	defer C.free(unsafe.Pointer(c_str))
 
 	has, err := C.Config_hasRole(c.ptr, c_str)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.HasRole", err); err != nil {
 		return false
 	}
 	runtime.KeepAlive(c)
@@ -545,7 +834,7 @@ func (c *Config) HasRole(role string) bool {
 // ‘compositing_log’. Return empty string if index is out of range.
 func (c *Config) RoleName(index int) (string, error) {
 	name, err := C.Config_getRoleName(c.ptr, C.int(index))
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Config.RoleName", err); err != nil {
 		return "", err
 	}
 	runtime.KeepAlive(c)
@@ -638,7 +927,7 @@ func (c *Config) AddDisplay(display, view, colorSpace, looks string) error {
 	defer C.free(unsafe.Pointer(c_looks))
 
 	_, err := C.Config_addDisplay(c.ptr, c_disp, c_view, c_cs, c_looks)
-	err = c.lastError(err)
+	err = c.lastError("Config.AddDisplay", err)
 	runtime.KeepAlive(c)
 	return err
 }
@@ -653,7 +942,7 @@ func (c *Config) SetActiveDisplays(displays string) error {
 	c_disp := C.CString(displays)
 	defer C.free(unsafe.Pointer(c_disp))
 	_, err := C.Config_setActiveDisplays(c.ptr, c_disp)
-	err = c.lastError(err)
+	err = c.lastError("Config.SetActiveDisplays", err)
 	runtime.KeepAlive(c)
 	return err
 }
@@ -669,7 +958,7 @@ func (c *Config) SetActiveViews(views string) error {
 	c_view := C.CString(views)
 	defer C.free(unsafe.Pointer(c_view))
 	_, err := C.Config_setActiveViews(c.ptr, c_view)
-	err = c.lastError(err)
+	err = c.lastError("Config.SetActiveViews", err)
 	runtime.KeepAlive(c)
 	return err
 }
@@ -679,3 +968,85 @@ func (c *Config) ActiveViews() string {
 	runtime.KeepAlive(c)
 	return ret
 }
+
+/*
+
+Config Luma
+
+*/
+
+// DefaultLumaCoefs returns the default red, green and blue
+// coefficients used to compute luma, such as for exposure scopes.
+func (c *Config) DefaultLumaCoefs() [3]float32 {
+	var rgb [3]float32
+	C.Config_getDefaultLumaCoefs(c.ptr, (*C.float)(&rgb[0]))
+	runtime.KeepAlive(c)
+	return rgb
+}
+
+// SetDefaultLumaCoefs sets the default red, green and blue
+// coefficients used to compute luma. This requires an editable Config.
+func (c *Config) SetDefaultLumaCoefs(rgb [3]float32) error {
+	_, err := C.Config_setDefaultLumaCoefs(c.ptr, (*C.float)(&rgb[0]))
+	err = c.lastError("Config.SetDefaultLumaCoefs", err)
+	runtime.KeepAlive(c)
+	return err
+}
+
+/*
+
+Config Looks
+
+*/
+
+// This will return an error if the specified look name is not found.
+func (c *Config) Look(name string) (*Look, error) {
+	c_str := C.CString(name)
+	defer C.free(unsafe.Pointer(c_str))
+
+	look, err := C.Config_getLook(c.ptr, c_str)
+	if err = c.lastError("Config.Look", err); err != nil {
+		err = fmt.Errorf("%q is not a valid Look: %w", name, err)
+		return nil, err
+	}
+	if look == 0 {
+		return nil, fmt.Errorf("%q is not a valid Look", name)
+	}
+	runtime.KeepAlive(c)
+	return newLook(look), nil
+}
+
+func (c *Config) NumLooks() int {
+	num, err := C.Config_getNumLooks(c.ptr)
+	if err = c.lastError("Config.NumLooks", err); err != nil {
+		return 0
+	}
+	runtime.KeepAlive(c)
+	return int(num)
+}
+
+func (c *Config) LookNameByIndex(index int) (string, error) {
+	name, err := C.Config_getLookNameByIndex(c.ptr, C.int(index))
+	if err = c.lastError("Config.LookNameByIndex", err); err != nil {
+		return "", err
+	}
+	runtime.KeepAlive(c)
+	return C.GoString(name), nil
+}
+
+// If another look is already registered with the same name, this will overwrite it.
+// This stores a copy of the specified look.
+func (c *Config) AddLook(look *Look) error {
+	_, err := C.Config_addLook(c.ptr, look.ptr)
+	err = c.lastError("Config.AddLook", err)
+	runtime.KeepAlive(c)
+	runtime.KeepAlive(look)
+	return err
+}
+
+func (c *Config) ClearLooks() error {
+	_, err := C.Config_clearLooks(c.ptr)
+	err = c.lastError("Config.ClearLooks", err)
+	runtime.KeepAlive(c)
+	return err
+}
@@ -0,0 +1,13 @@
+#ifndef _OPENCOLORIGO_CONFIG_H
+#define _OPENCOLORIGO_CONFIG_H
+
+#include "storage.h"
+#include <OpenColorIO/OpenColorIO.h>
+
+namespace ocigo {
+
+extern IndexMap<OCIO_NAMESPACE::ConfigRcPtr> g_Config_map;
+
+} // ocigo
+
+#endif //_OPENCOLORIGO_CONFIG_H
@@ -7,7 +7,9 @@ package ocio
 import "C"
 
 import (
+	"path/filepath"
 	"runtime"
+	"strings"
 	"unsafe"
 )
 
@@ -38,11 +40,11 @@ func NewContext() *Context {
 	return newContext(C.Context_Create())
 }
 
-func (c *Context) lastError(errno ...error) error {
+func (c *Context) lastError(op string, errno ...error) error {
 	if c == nil {
 		return nil
 	}
-	err := getLastError(c.ptr, errno...)
+	err := getLastError(op, "Context", c.ptr, errno...)
 	runtime.KeepAlive(c)
 	return err
 }
@@ -63,7 +65,7 @@ func (c *Context) EditableCopy() *Context {
 
 func (c *Context) CacheID() (string, error) {
 	id, err := C.Context_getCacheID(c.ptr)
-	if err = c.lastError(err); err != nil {
+	if err = c.lastError("Context.CacheID", err); err != nil {
 		return "", err
 	}
 	runtime.KeepAlive(c)
@@ -83,6 +85,34 @@ func (c *Context) SearchPath() string {
 	return ret
 }
 
+// SearchPaths returns the entries of the search path, in the order they
+// are searched, with context variables resolved and relative entries
+// made absolute to the WorkingDir.
+func (c *Context) SearchPaths() []string {
+	paths := splitSearchPath(c.SearchPath())
+	wd := c.WorkingDir()
+	for i, path := range paths {
+		path = c.ResolveStringVar(path)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(wd, path)
+		}
+		paths[i] = path
+	}
+	return paths
+}
+
+// splitSearchPath splits a colon-delimited search path
+// into its non-empty entries
+func splitSearchPath(path string) []string {
+	var paths []string
+	for _, p := range strings.Split(path, ":") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
 func (c *Context) SetWorkingDir(dirname string) {
 	c_str := C.CString(dirname)
 	defer C.free(unsafe.Pointer(c_str))
@@ -144,14 +174,40 @@ func (c *Context) ResolveStringVar(val string) string {
 // Do a file lookup.
 // Evaluate all variables (as needed). Also, walk the full search
 // path until the file is found.
-// If the filename cannot be found, an error will be returned.
+// If the filename cannot be found, a *FileLocationError will be
+// returned, listing the candidate paths built from the search path.
+// These are computed by this package to help debug the lookup, and
+// are not reported by OCIO, so may differ from the paths it tried.
 func (c *Context) ResolveFileLocation(filename string) (string, error) {
 	c_name := C.CString(filename)
 	defer C.free(unsafe.Pointer(c_name))
 	val, err := C.Context_resolveFileLocation(c.ptr, c_name)
-	if err = c.lastError(err); err != nil {
-		return "", err
+	if err = c.lastError("Context.ResolveFileLocation", err); err != nil {
+		return "", &FileLocationError{
+			Filename:   filename,
+			Candidates: c.candidatePaths(filename),
+			Err:        err,
+		}
 	}
 	runtime.KeepAlive(c)
 	return C.GoString(val), nil
 }
+
+// candidatePaths returns the paths a filename is expected to be
+// found at, by joining it to each entry of the search path in order,
+// as OCIO does. OCIO does not report the paths it actually tried.
+func (c *Context) candidatePaths(filename string) []string {
+	filename = c.ResolveStringVar(filename)
+	if filepath.IsAbs(filename) {
+		return []string{filename}
+	}
+	paths := c.SearchPaths()
+	if len(paths) == 0 {
+		// An empty search path is relative to the working dir
+		return []string{filepath.Join(c.WorkingDir(), filename)}
+	}
+	for i, dir := range paths {
+		paths[i] = filepath.Join(dir, filename)
+	}
+	return paths
+}
@@ -0,0 +1,81 @@
+package ocio
+
+import "math"
+
+// Half is an IEEE 754 half-precision (binary16) floating point value,
+// stored as its raw bits. This is the BIT_DEPTH_F16 pixel format
+// used by OpenEXR and GPU textures.
+type Half uint16
+
+// NewHalf converts a float32 to the nearest Half value,
+// rounding half to even. Values too large for a Half
+// become +/-Inf, and NaN is preserved.
+func NewHalf(f float32) Half {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int(bits>>23) & 0xff
+	mant := bits & 0x7fffff
+
+	// Inf / NaN
+	if exp == 0xff {
+		if mant == 0 {
+			return Half(sign | 0x7c00)
+		}
+		return Half(sign | 0x7e00 | uint16(mant>>13))
+	}
+
+	e := exp - 127 + 15
+	switch {
+	case e >= 0x1f:
+		// Overflow
+		return Half(sign | 0x7c00)
+
+	case e <= 0:
+		// Subnormal, or underflow to zero
+		if e < -10 {
+			return Half(sign)
+		}
+		m := mant | 0x800000
+		shift := uint32(126 - exp)
+		h := m >> shift
+		rem := m & (1<<shift - 1)
+		halfway := uint32(1) << (shift - 1)
+		if rem > halfway || (rem == halfway && h&1 == 1) {
+			h++
+		}
+		return Half(sign | uint16(h))
+	}
+
+	h := uint32(e)<<10 | mant>>13
+	rem := mant & 0x1fff
+	if rem > 0x1000 || (rem == 0x1000 && h&1 == 1) {
+		// A carry into the exponent is correct,
+		// and rounds up to Inf at the top of the range
+		h++
+	}
+	return Half(sign | uint16(h))
+}
+
+// Float32 converts the Half to a float32, which is exact
+func (h Half) Float32() float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+
+	switch exp {
+	case 0:
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		// Subnormal
+		f := float32(mant) / (1 << 24)
+		if sign != 0 {
+			f = -f
+		}
+		return f
+	case 0x1f:
+		// Inf / NaN
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	}
+	return math.Float32frombits(sign | (exp+112)<<23 | mant<<13)
+}
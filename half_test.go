@@ -0,0 +1,55 @@
+package ocio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHalfRoundTrip(t *testing.T) {
+	for i := 0; i <= 0xffff; i++ {
+		h := Half(i)
+		f := h.Float32()
+		if f != f {
+			if h&0x7c00 != 0x7c00 || h&0x3ff == 0 {
+				t.Fatalf("%#04x: unexpected NaN", i)
+			}
+			if back := NewHalf(f); back.Float32() == back.Float32() {
+				t.Fatalf("%#04x: expected NaN to be preserved, got %#04x", i, uint16(back))
+			}
+			continue
+		}
+		if back := NewHalf(f); back != h {
+			t.Fatalf("%#04x: round trip through %v gave %#04x", i, f, uint16(back))
+		}
+	}
+}
+
+func TestNewHalf(t *testing.T) {
+	tests := []struct {
+		in     float32
+		expect Half
+	}{
+		{0, 0x0000},
+		{float32(math.Copysign(0, -1)), 0x8000},
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{0.1, 0x2e66},
+		{65504, 0x7bff},
+		{65519, 0x7bff},
+		{65520, 0x7c00},
+		{1e10, 0x7c00},
+		{float32(math.Inf(-1)), 0xfc00},
+		// Smallest subnormal, and ties to even around it
+		{float32(math.Ldexp(1, -24)), 0x0001},
+		{float32(math.Ldexp(1, -25)), 0x0000},
+		{float32(math.Ldexp(3, -25)), 0x0002},
+		{1e-10, 0x0000},
+		// Largest subnormal rounding up into the normal range
+		{float32(math.Ldexp(1023.75, -24)), 0x0400},
+	}
+	for _, test := range tests {
+		if actual := NewHalf(test.in); actual != test.expect {
+			t.Errorf("NewHalf(%v): expected %#04x, got %#04x", test.in, uint16(test.expect), uint16(actual))
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package ocio
+
+import "testing"
+
+// getMatrixProcessor returns a Processor for a
+// diagonal matrix with the given scale and offset
+func getMatrixProcessor(t *testing.T, scale, offset float32) *Processor {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	mtx := NewMatrixTransform()
+	defer mtx.Destroy()
+	mtx.SetValue(
+		[16]float32{
+			scale, 0, 0, 0,
+			0, scale, 0, 0,
+			0, 0, scale, 0,
+			0, 0, 0, 1,
+		},
+		[4]float32{offset, offset, offset, 0},
+	)
+
+	proc, err := cfg.ProcessorTransform(mtx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return proc
+}
+
+// checkExact fails the test unless the max error of a 3D LUT
+// approximation of a getMatrixProcessor Processor is ~0, since
+// interpolating a linear function is exact
+func checkExact(t *testing.T, what string, maxErr float32, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if maxErr > 1e-5 {
+		t.Errorf("expected %s to match the matrix Processor exactly, got a max error of %v", what, maxErr)
+	}
+}
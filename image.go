@@ -0,0 +1,276 @@
+package ocio
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// ImageOptions controls how a Go image.Image is converted
+// to and from ColorData when processed by ApplyImageOptions
+type ImageOptions struct {
+	// Unpremultiply divides the color channels of premultiplied
+	// images (such as *image.RGBA) by alpha before processing, and
+	// multiplies them again afterwards. Images with straight alpha
+	// (such as *image.NRGBA) are always processed as-is.
+	Unpremultiply bool
+}
+
+// ApplyImage applies the Processor to a copy of a Go image,
+// using the default ImageOptions.
+// See ApplyImageOptions.
+func ApplyImage(p *Processor, img image.Image) (draw.Image, error) {
+	return ApplyImageOptions(p, img, ImageOptions{})
+}
+
+// ApplyImageOptions applies the Processor to a copy of a Go image,
+// returning the new image. The source image is not modified.
+//
+// Integer pixel values are normalized to the [0, 1] range before
+// processing, and are clamped back to the integer range afterwards.
+//
+// The returned image has the same type as the source for *image.RGBA,
+// *image.RGBA64, *image.NRGBA and *image.NRGBA64. Since a color
+// transform may introduce chroma, *image.Gray returns an *image.RGBA
+// and *image.Gray16 returns an *image.RGBA64. An 8-bit *image.YCbCr
+// returns an *image.RGBA. Any other image type is converted to an
+// *image.RGBA64.
+func ApplyImageOptions(p *Processor, img image.Image, opts ImageOptions) (draw.Image, error) {
+	b := img.Bounds()
+	if b.Empty() {
+		return nil, errors.New("cannot apply a Processor to an empty image")
+	}
+
+	data, premultiplied := imageToColorData(img)
+	unpremultiply := premultiplied && opts.Unpremultiply
+	if unpremultiply {
+		unpremultiplyColorData(data)
+	}
+
+	desc := NewPackedImageDesc(data, b.Dx(), b.Dy(), 4)
+	defer desc.Destroy()
+	if err := p.Apply(desc); err != nil {
+		return nil, err
+	}
+
+	if unpremultiply {
+		premultiplyColorData(data)
+	}
+
+	out := newProcessedImage(img)
+	colorDataToImage(data, out)
+	return out, nil
+}
+
+// newProcessedImage allocates the destination image type
+// for a processed copy of img
+func newProcessedImage(img image.Image) draw.Image {
+	b := img.Bounds()
+	switch img.(type) {
+	case *image.RGBA, *image.Gray, *image.YCbCr:
+		return image.NewRGBA(b)
+	case *image.NRGBA:
+		return image.NewNRGBA(b)
+	case *image.NRGBA64:
+		return image.NewNRGBA64(b)
+	default:
+		return image.NewRGBA64(b)
+	}
+}
+
+// imageToColorData converts an image into packed RGBA float data,
+// normalized to [0, 1]. It reports whether the color values
+// are premultiplied by alpha.
+func imageToColorData(img image.Image) (data ColorData, premultiplied bool) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	data = make(ColorData, w*h*4)
+
+	const max8 = 1.0 / 0xff
+	const max16 = 1.0 / 0xffff
+
+	i := 0
+	switch src := img.(type) {
+	case *image.RGBA:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			pix := src.Pix[src.PixOffset(b.Min.X, y):]
+			for x := 0; x < w*4; x++ {
+				data[i] = float32(pix[x]) * max8
+				i++
+			}
+		}
+		return data, true
+
+	case *image.NRGBA:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			pix := src.Pix[src.PixOffset(b.Min.X, y):]
+			for x := 0; x < w*4; x++ {
+				data[i] = float32(pix[x]) * max8
+				i++
+			}
+		}
+		return data, false
+
+	case *image.RGBA64:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			pix := src.Pix[src.PixOffset(b.Min.X, y):]
+			for x := 0; x < w*4; x++ {
+				data[i] = float32(uint16(pix[x*2])<<8|uint16(pix[x*2+1])) * max16
+				i++
+			}
+		}
+		return data, true
+
+	case *image.NRGBA64:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			pix := src.Pix[src.PixOffset(b.Min.X, y):]
+			for x := 0; x < w*4; x++ {
+				data[i] = float32(uint16(pix[x*2])<<8|uint16(pix[x*2+1])) * max16
+				i++
+			}
+		}
+		return data, false
+
+	case *image.Gray:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			pix := src.Pix[src.PixOffset(b.Min.X, y):]
+			for x := 0; x < w; x++ {
+				v := float32(pix[x]) * max8
+				data[i], data[i+1], data[i+2], data[i+3] = v, v, v, 1
+				i += 4
+			}
+		}
+		return data, false
+
+	case *image.Gray16:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			pix := src.Pix[src.PixOffset(b.Min.X, y):]
+			for x := 0; x < w; x++ {
+				v := float32(uint16(pix[x*2])<<8|uint16(pix[x*2+1])) * max16
+				data[i], data[i+1], data[i+2], data[i+3] = v, v, v, 1
+				i += 4
+			}
+		}
+		return data, false
+	}
+
+	// Generic path. color.Color.RGBA() returns
+	// alpha-premultiplied 16-bit values.
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := img.At(x, y).RGBA()
+			data[i] = float32(r) * max16
+			data[i+1] = float32(g) * max16
+			data[i+2] = float32(bl) * max16
+			data[i+3] = float32(a) * max16
+			i += 4
+		}
+	}
+	return data, true
+}
+
+// colorDataToImage writes packed RGBA float data into an image
+// allocated by newProcessedImage. Values are clamped to [0, 1],
+// and color is clamped to alpha for premultiplied image types.
+func colorDataToImage(data ColorData, img draw.Image) {
+	b := img.Bounds()
+	w := b.Dx()
+
+	i := 0
+	switch dst := img.(type) {
+	case *image.RGBA:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			pix := dst.Pix[dst.PixOffset(b.Min.X, y):]
+			for x := 0; x < w; x++ {
+				a := clampUnit(data[i+3])
+				pix[x*4] = to8(clampAlpha(data[i], a))
+				pix[x*4+1] = to8(clampAlpha(data[i+1], a))
+				pix[x*4+2] = to8(clampAlpha(data[i+2], a))
+				pix[x*4+3] = to8(a)
+				i += 4
+			}
+		}
+
+	case *image.NRGBA:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			pix := dst.Pix[dst.PixOffset(b.Min.X, y):]
+			for x := 0; x < w*4; x++ {
+				pix[x] = to8(clampUnit(data[i]))
+				i++
+			}
+		}
+
+	case *image.RGBA64:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				a := clampUnit(data[i+3])
+				dst.SetRGBA64(x, y, color.RGBA64{
+					R: to16(clampAlpha(data[i], a)),
+					G: to16(clampAlpha(data[i+1], a)),
+					B: to16(clampAlpha(data[i+2], a)),
+					A: to16(a),
+				})
+				i += 4
+			}
+		}
+
+	case *image.NRGBA64:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				dst.SetNRGBA64(x, y, color.NRGBA64{
+					R: to16(clampUnit(data[i])),
+					G: to16(clampUnit(data[i+1])),
+					B: to16(clampUnit(data[i+2])),
+					A: to16(clampUnit(data[i+3])),
+				})
+				i += 4
+			}
+		}
+	}
+}
+
+func unpremultiplyColorData(data ColorData) {
+	for i := 0; i+3 < len(data); i += 4 {
+		if a := data[i+3]; a > 0 {
+			data[i] /= a
+			data[i+1] /= a
+			data[i+2] /= a
+		}
+	}
+}
+
+func premultiplyColorData(data ColorData) {
+	for i := 0; i+3 < len(data); i += 4 {
+		a := data[i+3]
+		data[i] *= a
+		data[i+1] *= a
+		data[i+2] *= a
+	}
+}
+
+func clampUnit(v float32) float32 {
+	if v < 0 || v != v {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+func clampAlpha(v, a float32) float32 {
+	v = clampUnit(v)
+	if v > a {
+		return a
+	}
+	return v
+}
+
+func to8(v float32) uint8 {
+	return uint8(v*0xff + 0.5)
+}
+
+func to16(v float32) uint16 {
+	return uint16(v*0xffff + 0.5)
+}
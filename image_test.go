@@ -0,0 +1,126 @@
+package ocio
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestApplyImageIdentity(t *testing.T) {
+	proc := getMatrixProcessor(t, 1, 0)
+	defer proc.Destroy()
+
+	rect := image.Rect(2, 3, 10, 7)
+	rgba := image.NewRGBA(rect)
+	nrgba := image.NewNRGBA(rect)
+	rgba64 := image.NewRGBA64(rect)
+	nrgba64 := image.NewNRGBA64(rect)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			v := uint8(x*20 + y)
+			rgba.SetRGBA(x, y, color.RGBA{v / 2, v / 3, v / 4, v / 2})
+			nrgba.SetNRGBA(x, y, color.NRGBA{v, v / 2, v / 3, 200})
+			rgba64.SetRGBA64(x, y, color.RGBA64{uint16(v) << 7, uint16(v) << 6, 0, uint16(v) << 8})
+			nrgba64.SetNRGBA64(x, y, color.NRGBA64{uint16(v) << 8, 1234, 65535, 40000})
+		}
+	}
+
+	for _, src := range []image.Image{rgba, nrgba, rgba64, nrgba64} {
+		out, err := ApplyImage(proc, src)
+		if err != nil {
+			t.Fatalf("%T: %v", src, err)
+		}
+		if outType, srcType := fmt.Sprintf("%T", out), fmt.Sprintf("%T", src); outType != srcType {
+			t.Errorf("expected output type %s, got %s", srcType, outType)
+		}
+		if out.Bounds() != rect {
+			t.Errorf("%T: expected bounds %v, got %v", src, rect, out.Bounds())
+		}
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				if actual, expect := out.At(x, y), src.At(x, y); actual != expect {
+					t.Fatalf("%T (%d, %d): expected %v, got %v", src, x, y, expect, actual)
+				}
+			}
+		}
+	}
+}
+
+func TestApplyImageScale(t *testing.T) {
+	proc := getMatrixProcessor(t, 0.5, 0)
+	defer proc.Destroy()
+
+	rgba := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	rgba.SetRGBA(1, 1, color.RGBA{200, 100, 50, 255})
+	out, err := ApplyImage(proc, rgba)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expect := color.RGBA{100, 50, 25, 255}
+	if actual := out.(*image.RGBA).RGBAAt(1, 1); actual != expect {
+		t.Errorf("expected %v, got %v", expect, actual)
+	}
+	if rgba.RGBAAt(1, 1) != (color.RGBA{200, 100, 50, 255}) {
+		t.Error("expected source image to be unmodified")
+	}
+
+	gray := image.NewGray(image.Rect(0, 0, 1, 1))
+	gray.SetGray(0, 0, color.Gray{Y: 200})
+	out, err = ApplyImage(proc, gray)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	rgbaOut, ok := out.(*image.RGBA)
+	if !ok {
+		t.Fatalf("expected *image.RGBA for a Gray source, got %T", out)
+	}
+	if actual, expect := rgbaOut.RGBAAt(0, 0), (color.RGBA{100, 100, 100, 255}); actual != expect {
+		t.Errorf("expected %v, got %v", expect, actual)
+	}
+
+	gray16 := image.NewGray16(image.Rect(0, 0, 1, 1))
+	gray16.SetGray16(0, 0, color.Gray16{Y: 0xffff})
+	out, err = ApplyImage(proc, gray16)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, ok := out.(*image.RGBA64); !ok {
+		t.Fatalf("expected *image.RGBA64 for a Gray16 source, got %T", out)
+	}
+}
+
+func TestApplyImageUnpremultiply(t *testing.T) {
+	proc := getMatrixProcessor(t, 1, 0.2)
+	defer proc.Destroy()
+
+	rgba := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	rgba.SetRGBA(0, 0, color.RGBA{64, 64, 64, 128})
+
+	out, err := ApplyImage(proc, rgba)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	// 64/255 + 0.2
+	if actual := out.(*image.RGBA).RGBAAt(0, 0); actual.R != 115 || actual.A != 128 {
+		t.Errorf("expected premultiplied result R=115 A=128, got %v", actual)
+	}
+
+	out, err = ApplyImageOptions(proc, rgba, ImageOptions{Unpremultiply: true})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	// ((64/128) + 0.2) * 128/255
+	if actual := out.(*image.RGBA).RGBAAt(0, 0); actual.R != 90 || actual.A != 128 {
+		t.Errorf("expected unpremultiplied result R=90 A=128, got %v", actual)
+	}
+}
+
+func TestApplyImageEmpty(t *testing.T) {
+	proc := getMatrixProcessor(t, 1, 0)
+	defer proc.Destroy()
+
+	if _, err := ApplyImage(proc, image.NewRGBA(image.Rectangle{})); err == nil {
+		t.Error("expected an error for an empty image")
+	}
+}
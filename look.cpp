@@ -0,0 +1,115 @@
+#include <OpenColorIO/OpenColorIO.h>
+
+#include "ocio.h"
+#include "ocio_abi.h"
+#include "storage.h"
+#include "transform.h"
+
+namespace OCIO = OCIO_NAMESPACE;
+
+namespace ocigo {
+
+IndexMap<OCIO::LookRcPtr> g_Look_map;
+
+}
+
+extern "C" {
+
+    void deleteLook(LookId p) {
+        ocigo::g_Look_map.remove(p);
+    }
+
+    LookId Look_Create() {
+        OCIO::LookRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = OCIO::Look::Create();
+        END_CATCH_ERR
+        return ocigo::g_Look_map.add(ptr);
+    }
+
+    LookId Look_createEditableCopy(LookId p) {
+        OCIO::LookRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = ocigo::g_Look_map.get(p).get()->createEditableCopy();
+        END_CATCH_ERR
+        if ( ptr == NULL) { return 0; }
+        return ocigo::g_Look_map.add(ptr);
+    }
+
+    const char* Look_getName(LookId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = ocigo::g_Look_map.get(p).get()->getName();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void Look_setName(LookId p, const char* name) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Look_map.get(p).get()->setName(name);
+        END_CATCH_ERR
+    }
+
+    const char* Look_getProcessSpace(LookId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = ocigo::g_Look_map.get(p).get()->getProcessSpace();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void Look_setProcessSpace(LookId p, const char* processSpace) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Look_map.get(p).get()->setProcessSpace(processSpace);
+        END_CATCH_ERR
+    }
+
+    TransformId Look_getTransform(LookId p) {
+        OCIO::TransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        OCIO::ConstTransformRcPtr tx = ocigo::g_Look_map.get(p).get()->getTransform();
+        if (tx) { ptr = tx->createEditableCopy(); }
+        END_CATCH_ERR
+        if ( ptr == NULL) { return 0; }
+        return ocigo::g_Transform_map.add(ptr);
+    }
+
+    void Look_setTransform(LookId p, TransformId tx) {
+        OCIO::ConstTransformRcPtr tx_ptr = ocigo::g_Transform_map.get(tx);
+        BEGIN_CATCH_ERR
+        ocigo::g_Look_map.get(p).get()->setTransform(tx_ptr);
+        END_CATCH_ERR
+    }
+
+    TransformId Look_getInverseTransform(LookId p) {
+        OCIO::TransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        OCIO::ConstTransformRcPtr tx = ocigo::g_Look_map.get(p).get()->getInverseTransform();
+        if (tx) { ptr = tx->createEditableCopy(); }
+        END_CATCH_ERR
+        if ( ptr == NULL) { return 0; }
+        return ocigo::g_Transform_map.add(ptr);
+    }
+
+    void Look_setInverseTransform(LookId p, TransformId tx) {
+        OCIO::ConstTransformRcPtr tx_ptr = ocigo::g_Transform_map.get(tx);
+        BEGIN_CATCH_ERR
+        ocigo::g_Look_map.get(p).get()->setInverseTransform(tx_ptr);
+        END_CATCH_ERR
+    }
+
+    const char* Look_getDescription(LookId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = ocigo::g_Look_map.get(p).get()->getDescription();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void Look_setDescription(LookId p, const char* description) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Look_map.get(p).get()->setDescription(description);
+        END_CATCH_ERR
+    }
+
+}
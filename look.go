@@ -0,0 +1,143 @@
+package ocio
+
+// #include "stdlib.h"
+//
+// #include "ocio.h"
+//
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+/*
+The Look is an ‘artistic’ image modification, in a specified image state.
+The processSpace defines the ColorSpace the image is required to be in,
+for the math to apply correctly.
+*/
+type Look struct {
+	ptr C.LookId
+}
+
+func newLook(p C.LookId) *Look {
+	look := &Look{p}
+	runtime.SetFinalizer(look, deleteLook)
+	return look
+}
+
+func deleteLook(l *Look) {
+	if l == nil {
+		return
+	}
+	if l.ptr != 0 {
+		runtime.SetFinalizer(l, nil)
+		C.deleteLook(l.ptr)
+		l.ptr = 0
+	}
+	runtime.KeepAlive(l)
+}
+
+// Create a new empty Look
+func NewLook() *Look {
+	return newLook(C.Look_Create())
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (l *Look) Destroy() {
+	deleteLook(l)
+}
+
+func (l *Look) String() string {
+	name := ""
+	if l.ptr != 0 {
+		name = l.Name()
+	}
+	return fmt.Sprintf("Look: %q", name)
+}
+
+// Create a new editable copy of this Look
+func (l *Look) EditableCopy() *Look {
+	ret := newLook(C.Look_createEditableCopy(l.ptr))
+	runtime.KeepAlive(l)
+	return ret
+}
+
+func (l *Look) Name() string {
+	ret := C.GoString(C.Look_getName(l.ptr))
+	runtime.KeepAlive(l)
+	return ret
+}
+
+func (l *Look) SetName(name string) {
+	c_str := C.CString(name)
+	defer C.free(unsafe.Pointer(c_str))
+	C.Look_setName(l.ptr, c_str)
+	runtime.KeepAlive(l)
+}
+
+// ProcessSpace returns the name of the ColorSpace the image
+// is required to be in when the Look is applied.
+func (l *Look) ProcessSpace() string {
+	ret := C.GoString(C.Look_getProcessSpace(l.ptr))
+	runtime.KeepAlive(l)
+	return ret
+}
+
+func (l *Look) SetProcessSpace(processSpace string) {
+	c_str := C.CString(processSpace)
+	defer C.free(unsafe.Pointer(c_str))
+	C.Look_setProcessSpace(l.ptr, c_str)
+	runtime.KeepAlive(l)
+}
+
+// Transform returns the transform applied in the forward direction,
+// or nil if one is not set.
+// This returns a copy, so changing it does not change the Look.
+func (l *Look) Transform() (Transform, error) {
+	tx, err := newTransform(C.Look_getTransform(l.ptr))
+	runtime.KeepAlive(l)
+	return tx, err
+}
+
+// SetTransform sets the transform applied in the forward direction.
+// Passing nil clears the transform.
+// This stores a copy of the specified transform.
+func (l *Look) SetTransform(tx Transform) {
+	C.Look_setTransform(l.ptr, transformHandleOrZero(tx))
+	runtime.KeepAlive(l)
+	runtime.KeepAlive(tx)
+}
+
+// InverseTransform returns the transform applied in the inverse direction,
+// or nil if one is not set.
+// This returns a copy, so changing it does not change the Look.
+func (l *Look) InverseTransform() (Transform, error) {
+	tx, err := newTransform(C.Look_getInverseTransform(l.ptr))
+	runtime.KeepAlive(l)
+	return tx, err
+}
+
+// SetInverseTransform sets the transform applied in the inverse direction.
+// Passing nil clears the transform.
+func (l *Look) SetInverseTransform(tx Transform) {
+	C.Look_setInverseTransform(l.ptr, transformHandleOrZero(tx))
+	runtime.KeepAlive(l)
+	runtime.KeepAlive(tx)
+}
+
+func (l *Look) Description() string {
+	ret := C.GoString(C.Look_getDescription(l.ptr))
+	runtime.KeepAlive(l)
+	return ret
+}
+
+func (l *Look) SetDescription(description string) {
+	c_str := C.CString(description)
+	defer C.free(unsafe.Pointer(c_str))
+	C.Look_setDescription(l.ptr, c_str)
+	runtime.KeepAlive(l)
+}
@@ -0,0 +1,13 @@
+#ifndef _OPENCOLORIGO_LOOK_H
+#define _OPENCOLORIGO_LOOK_H
+
+#include "storage.h"
+#include <OpenColorIO/OpenColorIO.h>
+
+namespace ocigo {
+
+extern IndexMap<OCIO_NAMESPACE::LookRcPtr> g_Look_map;
+
+} // ocigo
+
+#endif //_OPENCOLORIGO_LOOK_H
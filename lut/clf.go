@@ -0,0 +1,601 @@
+package lut
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CLFVersion is the compCLFversion written by NewProcessList
+const CLFVersion = "3"
+
+// ProcessList is an Academy / ASC Common LUT Format (CLF) file: an
+// ordered list of nodes applied to RGB values. It supports the Matrix,
+// LUT1D, LUT3D, Range, Log and Exponent nodes.
+type ProcessList struct {
+	ID               string
+	Name             string
+	CompCLFVersion   string
+	InverseOf        string
+	Descriptions     []string
+	InputDescriptor  string
+	OutputDescriptor string
+
+	// Info holds the raw XML content of the Info element
+	Info string
+
+	Nodes []ProcessNode
+}
+
+// NewProcessList returns an empty ProcessList with the given id
+func NewProcessList(id string) *ProcessList {
+	return &ProcessList{ID: id, CompCLFVersion: CLFVersion}
+}
+
+// Eval applies each node of the ProcessList to an RGB value
+func (p *ProcessList) Eval(rgb [3]float64) [3]float64 {
+	for _, n := range p.Nodes {
+		rgb = n.Eval(rgb)
+	}
+	return rgb
+}
+
+// Validate checks that each node can be evaluated, and that
+// the bit depths of consecutive nodes match
+func (p *ProcessList) Validate() error {
+	if p.ID == "" {
+		return errors.New("ProcessList needs an id")
+	}
+	if len(p.Nodes) == 0 {
+		return errors.New("ProcessList needs at least 1 node")
+	}
+	var prev BitDepth
+	for i, n := range p.Nodes {
+		if err := n.Validate(); err != nil {
+			return fmt.Errorf("node %d: %w", i, err)
+		}
+		h := n.Header()
+		if i > 0 && bitDepthOrDefault(h.InBitDepth) != bitDepthOrDefault(prev) {
+			return fmt.Errorf("node %d: inBitDepth %s does not match the previous outBitDepth %s",
+				i, bitDepthOrDefault(h.InBitDepth), bitDepthOrDefault(prev))
+		}
+		prev = h.OutBitDepth
+	}
+	return nil
+}
+
+// ToLUT3D returns a LUT3D with the given edge size,
+// evaluating the ProcessList over the domain [0, 1]
+func (p *ProcessList) ToLUT3D(size int) *LUT3D {
+	return sampleLUT3D(p, p.Name, size, [3]float64{}, [3]float64{1, 1, 1})
+}
+
+// Append adds nodes to the ProcessList that apply a *Matrix, *LUT1D,
+// *LUT3D or the nodes of another *ProcessList. A domain other than
+// [0, 1] is added as a Range node, and a Shaper as a LUT1D node,
+// which needs the Shaper to have evenly spaced inputs over the
+// same range on each channel.
+func (p *ProcessList) Append(l LUT) error {
+	switch l := l.(type) {
+	case *Matrix:
+		p.Nodes = append(p.Nodes, &MatrixNode{NodeHeader: floatHeader(""), Matrix: *l})
+	case *LUT1D:
+		if err := l.Validate(); err != nil {
+			return err
+		}
+		if err := p.appendShaper(l.Shaper, l.DomainMin, l.DomainMax); err != nil {
+			return err
+		}
+		values := make([][3]float64, len(l.Values))
+		copy(values, l.Values)
+		p.Nodes = append(p.Nodes, &LUT1DNode{
+			NodeHeader:    floatHeader(l.Title),
+			Interpolation: "linear",
+			Values:        values,
+		})
+	case *LUT3D:
+		if err := l.Validate(); err != nil {
+			return err
+		}
+		if err := p.appendShaper(l.Shaper, l.DomainMin, l.DomainMax); err != nil {
+			return err
+		}
+		values := make([][3]float64, len(l.Values))
+		copy(values, l.Values)
+		p.Nodes = append(p.Nodes, &LUT3DNode{
+			NodeHeader:    floatHeader(l.Title),
+			Interpolation: "trilinear",
+			Size:          l.Size,
+			Values:        values,
+		})
+	case *ProcessList:
+		p.Nodes = append(p.Nodes, l.Nodes...)
+	default:
+		return fmt.Errorf("unsupported LUT type %T", l)
+	}
+	return nil
+}
+
+// appendShaper adds the nodes for the Shaper and domain of a LUT
+func (p *ProcessList) appendShaper(s *Shaper, lo, hi [3]float64) error {
+	if !uniformDomain(lo, hi) {
+		return errors.New("CLF does not support a domain that is different on each channel")
+	}
+	if s != nil {
+		lut1D, ok := s.lut1D()
+		if !ok {
+			return errors.New("CLF only supports a shaper with evenly spaced inputs over the same range on each channel")
+		}
+		if err := p.Append(lut1D); err != nil {
+			return err
+		}
+	}
+	if lo[0] == 0 && hi[0] == 1 {
+		return nil
+	}
+	zero, one := 0.0, 1.0
+	p.Nodes = append(p.Nodes, &RangeNode{
+		NodeHeader:  floatHeader(""),
+		Style:       RangeClamp,
+		MinInValue:  &lo[0],
+		MaxInValue:  &hi[0],
+		MinOutValue: &zero,
+		MaxOutValue: &one,
+	})
+	return nil
+}
+
+func floatHeader(name string) NodeHeader {
+	return NodeHeader{Name: name, InBitDepth: BitDepth32f, OutBitDepth: BitDepth32f}
+}
+
+func bitDepthOrDefault(b BitDepth) BitDepth {
+	if b == "" {
+		return BitDepth32f
+	}
+	return b
+}
+
+/*
+
+CLF XML
+
+*/
+
+type clfProcessListXML struct {
+	XMLName          xml.Name     `xml:"ProcessList"`
+	ID               string       `xml:"id,attr"`
+	Name             string       `xml:"name,attr,omitempty"`
+	CompCLFVersion   string       `xml:"compCLFversion,attr,omitempty"`
+	InverseOf        string       `xml:"inverseOf,attr,omitempty"`
+	Descriptions     []string     `xml:"Description"`
+	InputDescriptor  string       `xml:"InputDescriptor,omitempty"`
+	OutputDescriptor string       `xml:"OutputDescriptor,omitempty"`
+	Info             *clfInfoXML  `xml:"Info"`
+	Nodes            []clfNodeXML `xml:",any"`
+}
+
+type clfInfoXML struct {
+	Inner string `xml:",innerxml"`
+}
+
+type clfNodeXML struct {
+	XMLName       xml.Name
+	ID            string   `xml:"id,attr,omitempty"`
+	Name          string   `xml:"name,attr,omitempty"`
+	InBitDepth    BitDepth `xml:"inBitDepth,attr"`
+	OutBitDepth   BitDepth `xml:"outBitDepth,attr"`
+	Style         string   `xml:"style,attr,omitempty"`
+	Interpolation string   `xml:"interpolation,attr,omitempty"`
+	HalfDomain    bool     `xml:"halfDomain,attr,omitempty"`
+	RawHalfs      bool     `xml:"rawHalfs,attr,omitempty"`
+	Descriptions  []string `xml:"Description"`
+
+	Array          *clfArrayXML           `xml:"Array"`
+	MinInValue     *float64               `xml:"minInValue"`
+	MaxInValue     *float64               `xml:"maxInValue"`
+	MinOutValue    *float64               `xml:"minOutValue"`
+	MaxOutValue    *float64               `xml:"maxOutValue"`
+	LogParams      []clfLogParamsXML      `xml:"LogParams"`
+	ExponentParams []clfExponentParamsXML `xml:"ExponentParams"`
+}
+
+// clfArrayXML holds the values of an Array as raw XML, since
+// encoding/xml would escape the newlines between rows
+type clfArrayXML struct {
+	Dim  string `xml:"dim,attr"`
+	Data string `xml:",innerxml"`
+}
+
+type clfLogParamsXML struct {
+	Channel       string   `xml:"channel,attr,omitempty"`
+	Base          float64  `xml:"base,attr,omitempty"`
+	LogSideSlope  float64  `xml:"logSideSlope,attr,omitempty"`
+	LogSideOffset float64  `xml:"logSideOffset,attr,omitempty"`
+	LinSideSlope  float64  `xml:"linSideSlope,attr,omitempty"`
+	LinSideOffset float64  `xml:"linSideOffset,attr,omitempty"`
+	LinSideBreak  *float64 `xml:"linSideBreak,attr"`
+	LinearSlope   float64  `xml:"linearSlope,attr,omitempty"`
+}
+
+type clfExponentParamsXML struct {
+	Channel  string   `xml:"channel,attr,omitempty"`
+	Exponent float64  `xml:"exponent,attr"`
+	Offset   *float64 `xml:"offset,attr"`
+}
+
+// ReadCLF reads an Academy / ASC Common LUT Format (.clf) file
+func ReadCLF(r io.Reader) (*ProcessList, error) {
+	var doc clfProcessListXML
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("clf: %w", err)
+	}
+
+	p := &ProcessList{
+		ID:               doc.ID,
+		Name:             doc.Name,
+		CompCLFVersion:   doc.CompCLFVersion,
+		InverseOf:        doc.InverseOf,
+		Descriptions:     trimAll(doc.Descriptions),
+		InputDescriptor:  strings.TrimSpace(doc.InputDescriptor),
+		OutputDescriptor: strings.TrimSpace(doc.OutputDescriptor),
+	}
+	if doc.Info != nil {
+		p.Info = doc.Info.Inner
+	}
+	for i, x := range doc.Nodes {
+		n, err := x.node()
+		if err != nil {
+			return nil, fmt.Errorf("clf: node %d (%s): %w", i, x.XMLName.Local, err)
+		}
+		p.Nodes = append(p.Nodes, n)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("clf: %w", err)
+	}
+	return p, nil
+}
+
+// node converts the XML of a node to a ProcessNode
+func (x *clfNodeXML) node() (ProcessNode, error) {
+	h := NodeHeader{
+		ID:           x.ID,
+		Name:         x.Name,
+		InBitDepth:   x.InBitDepth,
+		OutBitDepth:  x.OutBitDepth,
+		Descriptions: trimAll(x.Descriptions),
+	}
+
+	switch x.XMLName.Local {
+	case "Matrix":
+		values, dim, err := x.array()
+		if err != nil {
+			return nil, err
+		}
+		// CLF v2 has a third dimension of 3
+		if len(dim) == 3 && dim[2] == 3 {
+			dim = dim[:2]
+		}
+		if len(dim) != 2 || dim[0] != 3 || (dim[1] != 3 && dim[1] != 4) {
+			return nil, fmt.Errorf("unsupported Matrix dim %v; must be 3 3 or 3 4", dim)
+		}
+		n := &MatrixNode{NodeHeader: h, Matrix: *NewMatrix()}
+		for row := 0; row < 3; row++ {
+			copy(n.Matrix.M[row*4:row*4+3], values[row*dim[1]:row*dim[1]+3])
+			if dim[1] == 4 {
+				n.Matrix.Offset[row] = values[row*4+3]
+			}
+		}
+		return n, nil
+
+	case "LUT1D":
+		values, dim, err := x.array()
+		if err != nil {
+			return nil, err
+		}
+		if len(dim) != 2 || (dim[1] != 1 && dim[1] != 3) {
+			return nil, fmt.Errorf("unsupported LUT1D dim %v; must be N 1 or N 3", dim)
+		}
+		n := &LUT1DNode{
+			NodeHeader:    h,
+			Interpolation: x.Interpolation,
+			HalfDomain:    x.HalfDomain,
+			RawHalfs:      x.RawHalfs,
+			Values:        make([][3]float64, dim[0]),
+		}
+		for i := range n.Values {
+			for c := 0; c < 3; c++ {
+				v := values[i*dim[1]+c%dim[1]]
+				if n.RawHalfs {
+					if v != math.Trunc(v) || v < 0 || v > 0xffff {
+						return nil, fmt.Errorf("invalid rawHalfs value %v", v)
+					}
+					v = halfToFloat(uint16(v))
+				}
+				n.Values[i][c] = v
+			}
+		}
+		return n, n.Validate()
+
+	case "LUT3D":
+		values, dim, err := x.array()
+		if err != nil {
+			return nil, err
+		}
+		if len(dim) != 4 || dim[3] != 3 || dim[1] != dim[0] || dim[2] != dim[0] {
+			return nil, fmt.Errorf("unsupported LUT3D dim %v; must be N N N 3", dim)
+		}
+		n := &LUT3DNode{
+			NodeHeader:    h,
+			Interpolation: x.Interpolation,
+			Size:          dim[0],
+			Values:        make([][3]float64, dim[0]*dim[0]*dim[0]),
+		}
+		// Blue changes fastest in the file
+		size, i := n.Size, 0
+		for r := 0; r < size; r++ {
+			for g := 0; g < size; g++ {
+				for b := 0; b < size; b++ {
+					copy(n.Values[r+size*(g+size*b)][:], values[i*3:i*3+3])
+					i++
+				}
+			}
+		}
+		return n, n.Validate()
+
+	case "Range":
+		n := &RangeNode{
+			NodeHeader:  h,
+			Style:       RangeStyle(x.Style),
+			MinInValue:  x.MinInValue,
+			MaxInValue:  x.MaxInValue,
+			MinOutValue: x.MinOutValue,
+			MaxOutValue: x.MaxOutValue,
+		}
+		return n, n.Validate()
+
+	case "Log":
+		n := &LogNode{NodeHeader: h, Style: LogStyle(x.Style)}
+		for _, px := range x.LogParams {
+			p := LogParams{
+				Channel:       px.Channel,
+				Base:          px.Base,
+				LogSideSlope:  px.LogSideSlope,
+				LogSideOffset: px.LogSideOffset,
+				LinSideSlope:  px.LinSideSlope,
+				LinSideOffset: px.LinSideOffset,
+				LinearSlope:   px.LinearSlope,
+			}
+			if px.LinSideBreak != nil {
+				p.LinSideBreak = *px.LinSideBreak
+			} else if n.Style == LogCameraLinToLog || n.Style == LogCameraLogToLin {
+				return nil, fmt.Errorf("Log style %s needs linSideBreak", n.Style)
+			}
+			n.Params = append(n.Params, p)
+		}
+		return n, n.Validate()
+
+	case "Exponent":
+		n := &ExponentNode{NodeHeader: h, Style: ExponentStyle(x.Style)}
+		for _, px := range x.ExponentParams {
+			p := ExponentParams{Channel: px.Channel, Exponent: px.Exponent}
+			if px.Offset != nil {
+				p.Offset = *px.Offset
+			}
+			n.Params = append(n.Params, p)
+		}
+		return n, n.Validate()
+	}
+	return nil, fmt.Errorf("unsupported node %s", x.XMLName.Local)
+}
+
+// array parses the values and dimensions of the Array element
+func (x *clfNodeXML) array() ([]float64, []int, error) {
+	if x.Array == nil {
+		return nil, nil, errors.New("missing Array")
+	}
+	var dim []int
+	count := 1
+	for _, f := range strings.Fields(x.Array.Dim) {
+		d, err := strconv.Atoi(f)
+		if err != nil || d < 1 {
+			return nil, nil, fmt.Errorf("invalid Array dim %q", x.Array.Dim)
+		}
+		dim = append(dim, d)
+		count *= d
+	}
+	if len(dim) == 0 {
+		return nil, nil, errors.New("missing Array dim")
+	}
+
+	fields := strings.Fields(x.Array.Data)
+	// A CLF v2 Matrix dim of 3 3 3 has 9 values
+	if len(dim) == 3 && x.XMLName.Local == "Matrix" {
+		count /= dim[2]
+	}
+	if len(fields) != count {
+		return nil, nil, fmt.Errorf("Array of dim %v needs %d values, got %d", dim, count, len(fields))
+	}
+	values := make([]float64, count)
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid Array value %q", f)
+		}
+		values[i] = v
+	}
+	return values, dim, nil
+}
+
+// WriteCLF writes a ProcessList as an Academy / ASC
+// Common LUT Format (.clf) file
+func WriteCLF(w io.Writer, p *ProcessList) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("clf: %w", err)
+	}
+
+	doc := clfProcessListXML{
+		ID:               p.ID,
+		Name:             p.Name,
+		CompCLFVersion:   p.CompCLFVersion,
+		InverseOf:        p.InverseOf,
+		Descriptions:     p.Descriptions,
+		InputDescriptor:  p.InputDescriptor,
+		OutputDescriptor: p.OutputDescriptor,
+	}
+	if p.Info != "" {
+		doc.Info = &clfInfoXML{Inner: p.Info}
+	}
+	for i, n := range p.Nodes {
+		x, err := nodeXML(n)
+		if err != nil {
+			return fmt.Errorf("clf: node %d: %w", i, err)
+		}
+		doc.Nodes = append(doc.Nodes, x)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "    ")
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("clf: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// nodeXML converts a ProcessNode to the XML of a node
+func nodeXML(n ProcessNode) (clfNodeXML, error) {
+	h := n.Header()
+	x := clfNodeXML{
+		ID:           h.ID,
+		Name:         h.Name,
+		InBitDepth:   bitDepthOrDefault(h.InBitDepth),
+		OutBitDepth:  bitDepthOrDefault(h.OutBitDepth),
+		Descriptions: h.Descriptions,
+	}
+
+	switch n := n.(type) {
+	case *MatrixNode:
+		x.XMLName.Local = "Matrix"
+		m := n.Matrix
+		if m.Offset == [4]float64{} {
+			x.Array = arrayXML("3 3", 3, m.M[0], m.M[1], m.M[2], m.M[4], m.M[5], m.M[6], m.M[8], m.M[9], m.M[10])
+		} else {
+			x.Array = arrayXML("3 4", 4,
+				m.M[0], m.M[1], m.M[2], m.Offset[0],
+				m.M[4], m.M[5], m.M[6], m.Offset[1],
+				m.M[8], m.M[9], m.M[10], m.Offset[2])
+		}
+
+	case *LUT1DNode:
+		x.XMLName.Local = "LUT1D"
+		x.Interpolation = n.Interpolation
+		x.HalfDomain = n.HalfDomain
+		x.RawHalfs = n.RawHalfs
+		components := 1
+		for _, v := range n.Values {
+			if v[0] != v[1] || v[0] != v[2] {
+				components = 3
+				break
+			}
+		}
+		values := make([]float64, 0, len(n.Values)*components)
+		for _, v := range n.Values {
+			for _, f := range v[:components] {
+				if n.RawHalfs {
+					f = float64(floatToHalf(f))
+				}
+				values = append(values, f)
+			}
+		}
+		x.Array = arrayXML(fmt.Sprintf("%d %d", len(n.Values), components), components, values...)
+
+	case *LUT3DNode:
+		x.XMLName.Local = "LUT3D"
+		x.Interpolation = n.Interpolation
+		size := n.Size
+		values := make([]float64, 0, len(n.Values)*3)
+		for r := 0; r < size; r++ {
+			for g := 0; g < size; g++ {
+				for b := 0; b < size; b++ {
+					values = append(values, n.Values[r+size*(g+size*b)][:]...)
+				}
+			}
+		}
+		x.Array = arrayXML(fmt.Sprintf("%d %d %d 3", size, size, size), 3, values...)
+
+	case *RangeNode:
+		x.XMLName.Local = "Range"
+		x.Style = string(n.Style)
+		x.MinInValue, x.MaxInValue = n.MinInValue, n.MaxInValue
+		x.MinOutValue, x.MaxOutValue = n.MinOutValue, n.MaxOutValue
+
+	case *LogNode:
+		x.XMLName.Local = "Log"
+		x.Style = string(n.Style)
+		camera := n.Style == LogCameraLinToLog || n.Style == LogCameraLogToLin
+		for _, p := range n.Params {
+			px := clfLogParamsXML{
+				Channel:       p.Channel,
+				Base:          p.Base,
+				LogSideSlope:  p.LogSideSlope,
+				LogSideOffset: p.LogSideOffset,
+				LinSideSlope:  p.LinSideSlope,
+				LinSideOffset: p.LinSideOffset,
+				LinearSlope:   p.LinearSlope,
+			}
+			if camera {
+				linSideBreak := p.LinSideBreak
+				px.LinSideBreak = &linSideBreak
+			}
+			x.LogParams = append(x.LogParams, px)
+		}
+
+	case *ExponentNode:
+		x.XMLName.Local = "Exponent"
+		x.Style = string(n.Style)
+		for _, p := range n.Params {
+			px := clfExponentParamsXML{Channel: p.Channel, Exponent: p.Exponent}
+			if strings.HasPrefix(x.Style, "monCurve") {
+				offset := p.Offset
+				px.Offset = &offset
+			}
+			x.ExponentParams = append(x.ExponentParams, px)
+		}
+
+	default:
+		return x, fmt.Errorf("unsupported node type %T", n)
+	}
+	return x, nil
+}
+
+// arrayXML formats values as the Array of a node, with a line per row
+// indented within the Array element
+func arrayXML(dim string, perRow int, values ...float64) *clfArrayXML {
+	const indent = "            "
+	var b strings.Builder
+	for i, v := range values {
+		if i%perRow == 0 {
+			b.WriteString("\n" + indent)
+		} else {
+			b.WriteString(" ")
+		}
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	b.WriteString("\n" + indent[:8])
+	return &clfArrayXML{Dim: dim, Data: b.String()}
+}
+
+func trimAll(s []string) []string {
+	for i := range s {
+		s[i] = strings.TrimSpace(s[i])
+	}
+	return s
+}
@@ -0,0 +1,328 @@
+package lut
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testCLF = `<?xml version="1.0" encoding="UTF-8"?>
+<ProcessList id="test-1" name="ingest test" compCLFversion="3">
+    <Description>Every supported node</Description>
+    <InputDescriptor>10-bit log</InputDescriptor>
+    <OutputDescriptor>display</OutputDescriptor>
+    <Info><Vendor>example</Vendor></Info>
+    <Range inBitDepth="10i" outBitDepth="32f">
+        <minInValue>0</minInValue>
+        <maxInValue>1023</maxInValue>
+        <minOutValue>0</minOutValue>
+        <maxOutValue>1</maxOutValue>
+    </Range>
+    <Log inBitDepth="32f" outBitDepth="32f" style="logToLin">
+        <LogParams base="10" logSideSlope="0.5" logSideOffset="0.5" linSideSlope="1" linSideOffset="0"/>
+    </Log>
+    <Matrix id="m1" inBitDepth="32f" outBitDepth="32f">
+        <Description>scale and offset</Description>
+        <Array dim="3 4">
+            0.5 0 0 0.1
+            0 0.5 0 0.1
+            0 0 0.5 0.1
+        </Array>
+    </Matrix>
+    <Exponent inBitDepth="32f" outBitDepth="32f" style="monCurveRev">
+        <ExponentParams exponent="2.4" offset="0.055"/>
+    </Exponent>
+    <LUT1D inBitDepth="32f" outBitDepth="32f" interpolation="linear">
+        <Array dim="3 1">
+            0 0.25 1
+        </Array>
+    </LUT1D>
+    <LUT3D inBitDepth="32f" outBitDepth="12i" interpolation="tetrahedral">
+        <Array dim="2 2 2 3">
+               0    0    0
+               0    0 4095
+               0 4095    0
+               0 4095 4095
+            4095    0    0
+            4095    0 4095
+            4095 4095    0
+            4095 4095 4095
+        </Array>
+    </LUT3D>
+</ProcessList>
+`
+
+func TestReadCLF(t *testing.T) {
+	p, err := ReadCLF(strings.NewReader(testCLF))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if p.ID != "test-1" || p.Name != "ingest test" || p.CompCLFVersion != "3" {
+		t.Errorf("unexpected ProcessList attributes %q, %q, %q", p.ID, p.Name, p.CompCLFVersion)
+	}
+	if p.InputDescriptor != "10-bit log" || p.OutputDescriptor != "display" {
+		t.Errorf("unexpected descriptors %q and %q", p.InputDescriptor, p.OutputDescriptor)
+	}
+	if p.Info != "<Vendor>example</Vendor>" {
+		t.Errorf("expected the raw Info, got %q", p.Info)
+	}
+	if len(p.Nodes) != 6 {
+		t.Fatalf("expected 6 nodes, got %d", len(p.Nodes))
+	}
+
+	m, ok := p.Nodes[2].(*MatrixNode)
+	if !ok {
+		t.Fatalf("expected a MatrixNode, got %T", p.Nodes[2])
+	}
+	if m.ID != "m1" || len(m.Descriptions) != 1 || m.Descriptions[0] != "scale and offset" {
+		t.Errorf("unexpected Matrix header %+v", m.NodeHeader)
+	}
+	if m.Matrix.M[0] != 0.5 || m.Matrix.Offset != [4]float64{0.1, 0.1, 0.1, 0} {
+		t.Errorf("unexpected Matrix %+v", m.Matrix)
+	}
+
+	// Code value 1023 is 1.0, which the Log maps to 10^((1-0.5)/0.5),
+	// and the Matrix to 5.1. The Exponent and LUT1D then clamp to 1.
+	expect := [3]float64{1, 1, 1}
+	if actual := p.Eval([3]float64{1, 1, 1}); !nearlyEqual(actual, expect, 1e-9) {
+		t.Errorf("expected %v, got %v", expect, actual)
+	}
+
+	// Step through the nodes for an input that stays within range
+	rgb := [3]float64{0.5, 0.5, 0.5}
+	rgb = p.Nodes[0].Eval(rgb)
+	rgb = p.Nodes[1].Eval(rgb)
+	if expect := [3]float64{1, 1, 1}; !nearlyEqual(rgb, expect, 1e-9) {
+		t.Errorf("expected the Log to map 0.5 to 1, got %v", rgb)
+	}
+	rgb = p.Nodes[2].Eval(rgb)
+	rgb = p.Nodes[3].Eval(rgb)
+	if expect := (1.055*math.Pow(0.6, 1/2.4) - 0.055); math.Abs(rgb[0]-expect) > 1e-9 {
+		t.Errorf("expected the Exponent to give %v, got %v", expect, rgb)
+	}
+}
+
+func TestWriteCLF(t *testing.T) {
+	p, err := ReadCLF(strings.NewReader(testCLF))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var buf bytes.Buffer
+	if err = WriteCLF(&buf, p); err != nil {
+		t.Fatal(err.Error())
+	}
+	roundTrip, err := ReadCLF(&buf)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(p, roundTrip) {
+		t.Errorf("expected the ProcessList to be unchanged after writing:\n%s", buf.String())
+	}
+
+	p.Nodes[2].Header().OutBitDepth = BitDepth10i
+	if err = WriteCLF(&buf, p); err == nil {
+		t.Error("expected an error for mismatched bit depths")
+	}
+}
+
+func TestProcessListAppend(t *testing.T) {
+	p := NewProcessList("converted")
+	for _, name := range []string{"lg10.spi1d", "p3_to_xyz16.spimtx", "spi_ocio_srgb_test.spi3d"} {
+		if err := p.Append(readTestLUT(t, name)); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	gnf := readTestLUT(t, "gnf.spi1d")
+	if err := p.Append(gnf); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(p.Nodes) != 5 {
+		t.Fatalf("expected a Range node for the gnf domain, got %d nodes", len(p.Nodes))
+	}
+	if _, ok := p.Nodes[3].(*RangeNode); !ok {
+		t.Errorf("expected a RangeNode, got %T", p.Nodes[3])
+	}
+
+	dir, err := ioutil.TempDir("", "lut")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "converted.clf")
+	if err = WriteFile(path, p); err != nil {
+		t.Fatal(err.Error())
+	}
+	l, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, in := range [][3]float64{{0, 0, 0}, {0.2, 0.5, 0.8}, {1, 0.1, 0.4}} {
+		expect := gnf.Eval(readTestLUT(t, "spi_ocio_srgb_test.spi3d").Eval(
+			readTestLUT(t, "p3_to_xyz16.spimtx").Eval(readTestLUT(t, "lg10.spi1d").Eval(in))))
+		if actual := l.Eval(in); !nearlyEqual(actual, expect, 1e-12) {
+			t.Errorf("%v: expected %v, got %v", in, expect, actual)
+		}
+	}
+
+	// Any other LUT is written as a ProcessList
+	path = filepath.Join(dir, "matrix.clf")
+	if err = WriteFile(path, readTestLUT(t, "hdOffset.spimtx")); err != nil {
+		t.Fatal(err.Error())
+	}
+	if l, err = ReadFile(path); err != nil {
+		t.Fatal(err.Error())
+	}
+	if p := l.(*ProcessList); p.ID != "matrix" || len(p.Nodes) != 1 {
+		t.Errorf("expected a ProcessList with id matrix and 1 node, got %q with %d", p.ID, len(p.Nodes))
+	}
+
+	lut3D := p.ToLUT3D(5)
+	for _, idx := range [][3]int{{0, 0, 0}, {1, 2, 3}, {4, 4, 4}} {
+		in := [3]float64{float64(idx[0]) / 4, float64(idx[1]) / 4, float64(idx[2]) / 4}
+		if actual, expect := lut3D.Values[lut3D.Index(idx[0], idx[1], idx[2])], p.Eval(in); actual != expect {
+			t.Errorf("%v: expected the baked LUT to match %v, got %v", in, expect, actual)
+		}
+	}
+}
+
+func TestCLFLogExponent(t *testing.T) {
+	camera := LogParams{
+		Base:          10,
+		LogSideSlope:  0.25,
+		LogSideOffset: 0.6,
+		LinSideSlope:  0.9,
+		LinSideOffset: 0.01,
+		LinSideBreak:  0.02,
+	}
+	for _, tc := range []struct {
+		fwd, rev ProcessNode
+	}{
+		{
+			&LogNode{Style: LogCameraLinToLog, Params: []LogParams{camera}},
+			&LogNode{Style: LogCameraLogToLin, Params: []LogParams{camera}},
+		},
+		{
+			&LogNode{Style: LogLog2},
+			&LogNode{Style: LogAntiLog2},
+		},
+		{
+			&ExponentNode{Style: ExponentMonCurveMirrorFwd, Params: []ExponentParams{{Exponent: 2.4, Offset: 0.055}}},
+			&ExponentNode{Style: ExponentMonCurveMirrorRev, Params: []ExponentParams{{Exponent: 2.4, Offset: 0.055}}},
+		},
+		{
+			&ExponentNode{Style: ExponentBasicMirrorFwd, Params: []ExponentParams{
+				{Channel: "R", Exponent: 2}, {Channel: "G", Exponent: 2.2}, {Channel: "B", Exponent: 2.6},
+			}},
+			&ExponentNode{Style: ExponentBasicMirrorRev, Params: []ExponentParams{
+				{Channel: "R", Exponent: 2}, {Channel: "G", Exponent: 2.2}, {Channel: "B", Exponent: 2.6},
+			}},
+		},
+	} {
+		for _, n := range []ProcessNode{tc.fwd, tc.rev} {
+			if err := n.Validate(); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+		for _, in := range [][3]float64{{0.001, 0.01, 0.02}, {0.18, 0.5, 1}, {2, 4, 8}} {
+			if _, ok := tc.fwd.(*LogNode); !ok {
+				in[0] = -in[0]
+			}
+			if actual := tc.rev.Eval(tc.fwd.Eval(in)); !nearlyEqual(actual, in, 1e-9) {
+				t.Errorf("%T %v: expected the reverse style to invert %v, got %v", tc.fwd, tc.fwd, in, actual)
+			}
+		}
+	}
+
+	// The camera styles are continuous at the break
+	n := &LogNode{Style: LogCameraLinToLog, Params: []LogParams{camera}}
+	below := n.Eval([3]float64{0.02 - 1e-9})
+	above := n.Eval([3]float64{0.02 + 1e-9})
+	if math.Abs(below[0]-above[0]) > 1e-8 {
+		t.Errorf("expected a continuous curve at the break, got %v and %v", below[0], above[0])
+	}
+}
+
+func TestCLFHalf(t *testing.T) {
+	for h := 0; h < 0x10000; h++ {
+		v := halfToFloat(uint16(h))
+		if v != v {
+			continue
+		}
+		if actual := floatToHalf(v); actual != uint16(h) {
+			t.Fatalf("expected %#04x for %v, got %#04x", h, v, actual)
+		}
+	}
+	if actual := floatToHalf(1 + 1.0/4096); actual != 0x3c00 {
+		t.Errorf("expected a value to round to the nearest half, got %#04x", actual)
+	}
+
+	// A halfDomain LUT1D of the input values is an identity
+	n := &LUT1DNode{HalfDomain: true, RawHalfs: true, Values: make([][3]float64, 0x10000)}
+	for h := range n.Values {
+		v := halfToFloat(uint16(h))
+		if v != v || math.IsInf(v, 0) {
+			v = 0
+		}
+		n.Values[h] = [3]float64{v, v, v}
+	}
+	if err := n.Validate(); err != nil {
+		t.Fatal(err.Error())
+	}
+	in := [3]float64{-3.3, 0.18, 1000.5}
+	if actual := n.Eval(in); !nearlyEqual(actual, in, 1e-12) {
+		t.Errorf("expected %v, got %v", in, actual)
+	}
+	if actual := n.Eval([3]float64{1e6}); actual[0] != 65504 {
+		t.Errorf("expected input to be clamped to the largest half, got %v", actual)
+	}
+
+	p := NewProcessList("half")
+	n.InBitDepth, n.OutBitDepth = BitDepth16f, BitDepth16f
+	p.Nodes = append(p.Nodes, n)
+	var buf bytes.Buffer
+	if err := WriteCLF(&buf, p); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !strings.Contains(buf.String(), `halfDomain="true" rawHalfs="true"`) ||
+		!strings.Contains(buf.String(), " 15360\n") {
+		t.Error("expected the values to be written as half-float bit patterns")
+	}
+	roundTrip, err := ReadCLF(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(p, roundTrip) {
+		t.Error("expected the halfDomain LUT1D to be unchanged after writing")
+	}
+}
+
+func TestReadCLFErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name, nodes string
+	}{
+		{"unsupported", `<ASC_CDL inBitDepth="32f" outBitDepth="32f"/>`},
+		{"matrix dim", `<Matrix inBitDepth="32f" outBitDepth="32f"><Array dim="2 2">1 0 0 1</Array></Matrix>`},
+		{"array count", `<LUT1D inBitDepth="32f" outBitDepth="32f"><Array dim="3 1">0 1</Array></LUT1D>`},
+		{"bit depth", `<LUT1D inBitDepth="32i" outBitDepth="32f"><Array dim="2 1">0 1</Array></LUT1D>`},
+		{"range", `<Range inBitDepth="32f" outBitDepth="32f"><minInValue>0</minInValue></Range>`},
+		{"log style", `<Log inBitDepth="32f" outBitDepth="32f" style="ln"/>`},
+		{"log break", `<Log inBitDepth="32f" outBitDepth="32f" style="cameraLinToLog"><LogParams base="10"/></Log>`},
+		{"exponent", `<Exponent inBitDepth="32f" outBitDepth="32f" style="basicFwd"/>`},
+		{"exponent channels", `<Exponent inBitDepth="32f" outBitDepth="32f" style="basicFwd">` +
+			`<ExponentParams channel="R" exponent="2"/><ExponentParams channel="R" exponent="2"/></Exponent>`},
+		{"mismatch", `<Log inBitDepth="32f" outBitDepth="16f" style="log2"/><Log inBitDepth="32f" outBitDepth="32f" style="antiLog2"/>`},
+		{"empty", ``},
+	} {
+		data := `<ProcessList id="x" compCLFversion="3">` + tc.nodes + `</ProcessList>`
+		if _, err := ReadCLF(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
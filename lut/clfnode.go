@@ -0,0 +1,576 @@
+package lut
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// BitDepth is the bit depth of the input or output values of a
+// ProcessNode, which sets the scale of the values in the CLF file
+type BitDepth string
+
+const (
+	BitDepth8i  BitDepth = "8i"
+	BitDepth10i BitDepth = "10i"
+	BitDepth12i BitDepth = "12i"
+	BitDepth16i BitDepth = "16i"
+	BitDepth16f BitDepth = "16f"
+	BitDepth32f BitDepth = "32f"
+)
+
+// Scale returns the value that represents 1.0 at the bit depth,
+// such as 1023 for 10i. An empty BitDepth is treated as 32f.
+func (b BitDepth) Scale() float64 {
+	switch b {
+	case BitDepth8i:
+		return 255
+	case BitDepth10i:
+		return 1023
+	case BitDepth12i:
+		return 4095
+	case BitDepth16i:
+		return 65535
+	}
+	return 1
+}
+
+func (b BitDepth) validate() error {
+	switch b {
+	case "", BitDepth8i, BitDepth10i, BitDepth12i, BitDepth16i, BitDepth16f, BitDepth32f:
+		return nil
+	}
+	return fmt.Errorf("unknown bit depth %q", string(b))
+}
+
+// ProcessNode is a node of a ProcessList: a *MatrixNode, *LUT1DNode,
+// *LUT3DNode, *RangeNode, *LogNode or *ExponentNode. Eval applies the
+// node to normalized values, where 1.0 is the Scale of the bit depth.
+type ProcessNode interface {
+	LUT
+
+	// Header returns the attributes common to all nodes
+	Header() *NodeHeader
+
+	// Validate checks that the node can be evaluated
+	Validate() error
+}
+
+// NodeHeader holds the attributes common to all ProcessNode types
+type NodeHeader struct {
+	ID           string
+	Name         string
+	InBitDepth   BitDepth
+	OutBitDepth  BitDepth
+	Descriptions []string
+}
+
+// Header returns the NodeHeader
+func (h *NodeHeader) Header() *NodeHeader {
+	return h
+}
+
+func (h *NodeHeader) validate() error {
+	if err := h.InBitDepth.validate(); err != nil {
+		return err
+	}
+	return h.OutBitDepth.validate()
+}
+
+// MatrixNode applies a Matrix. The coefficients and offsets
+// are in the scale of the bit depths.
+type MatrixNode struct {
+	NodeHeader
+	Matrix Matrix
+}
+
+// Eval applies the MatrixNode to an RGB value
+func (n *MatrixNode) Eval(rgb [3]float64) [3]float64 {
+	rgb = scaleRGB(rgb, n.InBitDepth.Scale())
+	return scaleRGB(n.Matrix.Eval(rgb), 1/n.OutBitDepth.Scale())
+}
+
+// Validate checks that the MatrixNode can be evaluated
+func (n *MatrixNode) Validate() error {
+	return n.NodeHeader.validate()
+}
+
+// LUT1DNode is a 1D LUT over the normalized input range, with values
+// in the scale of OutBitDepth. With HalfDomain, it has an entry for
+// each of the 65536 half-float values, indexed by their bit pattern.
+// With RawHalfs, the values are written as half-float bit patterns.
+type LUT1DNode struct {
+	NodeHeader
+	Interpolation string
+	HalfDomain    bool
+	RawHalfs      bool
+	Values        [][3]float64
+}
+
+// Eval applies the LUT1DNode to an RGB value, with linear interpolation
+func (n *LUT1DNode) Eval(rgb [3]float64) [3]float64 {
+	scale := n.OutBitDepth.Scale()
+	last := len(n.Values) - 1
+	for c, v := range rgb {
+		var (
+			i    int
+			frac float64
+		)
+		if n.HalfDomain {
+			i, frac = halfDomainIndex(v * n.InBitDepth.Scale())
+		} else {
+			pos := clamp01(v) * float64(last)
+			i = int(pos)
+			if i >= last {
+				i = last - 1
+			}
+			frac = pos - float64(i)
+		}
+		rgb[c] = (n.Values[i][c] + (n.Values[i+1][c]-n.Values[i][c])*frac) / scale
+	}
+	return rgb
+}
+
+// Validate checks that the LUT1DNode can be evaluated
+func (n *LUT1DNode) Validate() error {
+	if err := n.NodeHeader.validate(); err != nil {
+		return err
+	}
+	if n.HalfDomain && len(n.Values) != 65536 {
+		return fmt.Errorf("LUT1D with halfDomain needs 65536 entries, got %d", len(n.Values))
+	}
+	if len(n.Values) < 2 {
+		return fmt.Errorf("LUT1D needs at least 2 entries, got %d", len(n.Values))
+	}
+	return nil
+}
+
+// LUT3DNode is a 3D LUT over the normalized input range, with values
+// in the scale of OutBitDepth. As with LUT3D, red changes fastest
+// in Values. Interpolation is "trilinear" or "tetrahedral".
+type LUT3DNode struct {
+	NodeHeader
+	Interpolation string
+	Size          int
+	Values        [][3]float64
+}
+
+// Eval applies the LUT3DNode to an RGB value
+func (n *LUT3DNode) Eval(rgb [3]float64) [3]float64 {
+	for c, v := range rgb {
+		rgb[c] = clamp01(v)
+	}
+	rgb = interpolate3D(n.Values, n.Size, rgb, n.Interpolation == "tetrahedral")
+	return scaleRGB(rgb, 1/n.OutBitDepth.Scale())
+}
+
+// Validate checks that the LUT3DNode can be evaluated
+func (n *LUT3DNode) Validate() error {
+	if err := n.NodeHeader.validate(); err != nil {
+		return err
+	}
+	switch n.Interpolation {
+	case "", "trilinear", "tetrahedral":
+	default:
+		return fmt.Errorf("unknown LUT3D interpolation %q", n.Interpolation)
+	}
+	if n.Size < 2 || len(n.Values) != n.Size*n.Size*n.Size {
+		return fmt.Errorf("LUT3D of size %d has %d entries", n.Size, len(n.Values))
+	}
+	return nil
+}
+
+// RangeStyle is the style of a RangeNode
+type RangeStyle string
+
+const (
+	RangeClamp   RangeStyle = "clamp"
+	RangeNoClamp RangeStyle = "noClamp"
+)
+
+// RangeNode linearly maps the input range to the output range, clamping
+// to the output range unless the Style is RangeNoClamp. With only the
+// minimum or maximum values, the input is offset and clamped on one
+// side. The values are in the scale of the bit depths.
+type RangeNode struct {
+	NodeHeader
+	Style       RangeStyle
+	MinInValue  *float64
+	MaxInValue  *float64
+	MinOutValue *float64
+	MaxOutValue *float64
+}
+
+// Eval applies the RangeNode to an RGB value
+func (n *RangeNode) Eval(rgb [3]float64) [3]float64 {
+	inScale, outScale := n.InBitDepth.Scale(), n.OutBitDepth.Scale()
+	clamp := n.Style != RangeNoClamp
+	hasMin, hasMax := n.MinInValue != nil, n.MaxInValue != nil
+	for c, v := range rgb {
+		switch {
+		case hasMin && hasMax:
+			minIn, maxIn := *n.MinInValue/inScale, *n.MaxInValue/inScale
+			minOut, maxOut := *n.MinOutValue/outScale, *n.MaxOutValue/outScale
+			v = minOut + (v-minIn)*(maxOut-minOut)/(maxIn-minIn)
+			if clamp {
+				v = math.Max(minOut, math.Min(maxOut, v))
+			}
+		case hasMin:
+			minOut := *n.MinOutValue / outScale
+			v += minOut - *n.MinInValue/inScale
+			if clamp {
+				v = math.Max(minOut, v)
+			}
+		case hasMax:
+			maxOut := *n.MaxOutValue / outScale
+			v += maxOut - *n.MaxInValue/inScale
+			if clamp {
+				v = math.Min(maxOut, v)
+			}
+		}
+		rgb[c] = v
+	}
+	return rgb
+}
+
+// Validate checks that the RangeNode can be evaluated
+func (n *RangeNode) Validate() error {
+	if err := n.NodeHeader.validate(); err != nil {
+		return err
+	}
+	switch n.Style {
+	case "", RangeClamp, RangeNoClamp:
+	default:
+		return fmt.Errorf("unknown Range style %q", n.Style)
+	}
+	if (n.MinInValue == nil) != (n.MinOutValue == nil) || (n.MaxInValue == nil) != (n.MaxOutValue == nil) {
+		return errors.New("Range needs both the in and out values of the minimum or maximum")
+	}
+	if n.MinInValue == nil && n.MaxInValue == nil {
+		return errors.New("Range needs minimum or maximum values")
+	}
+	if n.Style == RangeNoClamp && (n.MinInValue == nil || n.MaxInValue == nil) {
+		return errors.New("Range with style noClamp needs minimum and maximum values")
+	}
+	if n.MinInValue != nil && n.MaxInValue != nil && !(*n.MaxInValue > *n.MinInValue) {
+		return fmt.Errorf("Range maxInValue %v must be greater than minInValue %v", *n.MaxInValue, *n.MinInValue)
+	}
+	return nil
+}
+
+// LogStyle is the style of a LogNode
+type LogStyle string
+
+const (
+	LogLog10          LogStyle = "log10"
+	LogAntiLog10      LogStyle = "antiLog10"
+	LogLog2           LogStyle = "log2"
+	LogAntiLog2       LogStyle = "antiLog2"
+	LogLinToLog       LogStyle = "linToLog"
+	LogLogToLin       LogStyle = "logToLin"
+	LogCameraLinToLog LogStyle = "cameraLinToLog"
+	LogCameraLogToLin LogStyle = "cameraLogToLin"
+)
+
+// LogParams are the parameters of a LogNode for a Channel of "R", "G"
+// or "B", or all channels if empty. A zero Base defaults to 2, zero
+// slopes default to 1, and a zero LinearSlope is computed to match the
+// log curve at LinSideBreak, which only applies to the camera styles.
+type LogParams struct {
+	Channel       string
+	Base          float64
+	LogSideSlope  float64
+	LogSideOffset float64
+	LinSideSlope  float64
+	LinSideOffset float64
+	LinSideBreak  float64
+	LinearSlope   float64
+}
+
+// withDefaults returns the LogParams with the zero values replaced
+func (p LogParams) withDefaults() LogParams {
+	if p.Base == 0 {
+		p.Base = 2
+	}
+	if p.LogSideSlope == 0 {
+		p.LogSideSlope = 1
+	}
+	if p.LinSideSlope == 0 {
+		p.LinSideSlope = 1
+	}
+	if p.LinearSlope == 0 {
+		p.LinearSlope = p.LogSideSlope * p.LinSideSlope /
+			((p.LinSideSlope*p.LinSideBreak + p.LinSideOffset) * math.Log(p.Base))
+	}
+	return p
+}
+
+func (p LogParams) linToLog(v float64) float64 {
+	return p.LogSideSlope*math.Log(math.Max(minLogInput, p.LinSideSlope*v+p.LinSideOffset))/math.Log(p.Base) +
+		p.LogSideOffset
+}
+
+func (p LogParams) logToLin(v float64) float64 {
+	return (math.Pow(p.Base, (v-p.LogSideOffset)/p.LogSideSlope) - p.LinSideOffset) / p.LinSideSlope
+}
+
+// minLogInput is the smallest normal float32, to which
+// log input is clamped, as in OpenColorIO
+const minLogInput = 0x1p-126
+
+// LogNode applies a logarithmic or anti-logarithmic curve
+type LogNode struct {
+	NodeHeader
+	Style  LogStyle
+	Params []LogParams
+}
+
+// Eval applies the LogNode to an RGB value
+func (n *LogNode) Eval(rgb [3]float64) [3]float64 {
+	rgb = scaleRGB(rgb, n.InBitDepth.Scale())
+	for c, v := range rgb {
+		p := n.ChannelParams(c)
+		switch n.Style {
+		case LogLog10:
+			v = math.Log10(math.Max(minLogInput, v))
+		case LogAntiLog10:
+			v = math.Pow(10, v)
+		case LogLog2:
+			v = math.Log2(math.Max(minLogInput, v))
+		case LogAntiLog2:
+			v = math.Exp2(v)
+		case LogLinToLog:
+			v = p.linToLog(v)
+		case LogLogToLin:
+			v = p.logToLin(v)
+		case LogCameraLinToLog:
+			if v <= p.LinSideBreak {
+				v = p.LinearSlope*(v-p.LinSideBreak) + p.linToLog(p.LinSideBreak)
+			} else {
+				v = p.linToLog(v)
+			}
+		case LogCameraLogToLin:
+			if logBreak := p.linToLog(p.LinSideBreak); v <= logBreak {
+				v = (v-logBreak)/p.LinearSlope + p.LinSideBreak
+			} else {
+				v = p.logToLin(v)
+			}
+		}
+		rgb[c] = v
+	}
+	return scaleRGB(rgb, 1/n.OutBitDepth.Scale())
+}
+
+// ChannelParams returns the LogParams that apply to a channel
+// index of 0, 1 or 2, with the zero values replaced by their defaults
+func (n *LogNode) ChannelParams(c int) LogParams {
+	var all LogParams
+	for _, p := range n.Params {
+		if p.Channel == channelNames[c] {
+			return p.withDefaults()
+		} else if p.Channel == "" {
+			all = p
+		}
+	}
+	return all.withDefaults()
+}
+
+// Validate checks that the LogNode can be evaluated
+func (n *LogNode) Validate() error {
+	if err := n.NodeHeader.validate(); err != nil {
+		return err
+	}
+	switch n.Style {
+	case LogLog10, LogAntiLog10, LogLog2, LogAntiLog2:
+		return nil
+	case LogLinToLog, LogLogToLin, LogCameraLinToLog, LogCameraLogToLin:
+	default:
+		return fmt.Errorf("unknown Log style %q", n.Style)
+	}
+	if err := validateChannels(len(n.Params), func(i int) string { return n.Params[i].Channel }); err != nil {
+		return fmt.Errorf("LogParams %v", err)
+	}
+	for _, p := range n.Params {
+		if p.Base < 0 || p.Base == 1 {
+			return fmt.Errorf("invalid Log base %v", p.Base)
+		}
+	}
+	return nil
+}
+
+// ExponentStyle is the style of an ExponentNode
+type ExponentStyle string
+
+const (
+	ExponentBasicFwd          ExponentStyle = "basicFwd"
+	ExponentBasicRev          ExponentStyle = "basicRev"
+	ExponentBasicMirrorFwd    ExponentStyle = "basicMirrorFwd"
+	ExponentBasicMirrorRev    ExponentStyle = "basicMirrorRev"
+	ExponentBasicPassThruFwd  ExponentStyle = "basicPassThruFwd"
+	ExponentBasicPassThruRev  ExponentStyle = "basicPassThruRev"
+	ExponentMonCurveFwd       ExponentStyle = "monCurveFwd"
+	ExponentMonCurveRev       ExponentStyle = "monCurveRev"
+	ExponentMonCurveMirrorFwd ExponentStyle = "monCurveMirrorFwd"
+	ExponentMonCurveMirrorRev ExponentStyle = "monCurveMirrorRev"
+)
+
+// ExponentParams are the parameters of an ExponentNode for a Channel
+// of "R", "G" or "B", or all channels if empty. Offset only applies
+// to the monCurve styles.
+type ExponentParams struct {
+	Channel  string
+	Exponent float64
+	Offset   float64
+}
+
+// ExponentNode applies a power function
+type ExponentNode struct {
+	NodeHeader
+	Style  ExponentStyle
+	Params []ExponentParams
+}
+
+// Eval applies the ExponentNode to an RGB value
+func (n *ExponentNode) Eval(rgb [3]float64) [3]float64 {
+	rgb = scaleRGB(rgb, n.InBitDepth.Scale())
+	for c, v := range rgb {
+		p := n.ChannelParams(c)
+		g, offset := p.Exponent, p.Offset
+		sign := 1.0
+		if v < 0 {
+			switch n.Style {
+			case ExponentBasicMirrorFwd, ExponentBasicMirrorRev, ExponentMonCurveMirrorFwd, ExponentMonCurveMirrorRev:
+				sign, v = -1, -v
+			case ExponentBasicPassThruFwd, ExponentBasicPassThruRev:
+				continue
+			case ExponentBasicFwd, ExponentBasicRev:
+				v = 0
+			}
+		}
+
+		switch n.Style {
+		case ExponentBasicFwd, ExponentBasicMirrorFwd, ExponentBasicPassThruFwd:
+			v = math.Pow(v, g)
+		case ExponentBasicRev, ExponentBasicMirrorRev, ExponentBasicPassThruRev:
+			v = math.Pow(v, 1/g)
+		case ExponentMonCurveFwd, ExponentMonCurveMirrorFwd:
+			if offset == 0 {
+				v = math.Pow(math.Max(0, v), g)
+				break
+			}
+			breakPnt := offset / (g - 1)
+			if v >= breakPnt {
+				v = math.Pow((v+offset)/(1+offset), g)
+			} else {
+				v *= monCurveSlope(g, offset)
+			}
+		case ExponentMonCurveRev, ExponentMonCurveMirrorRev:
+			if offset == 0 {
+				v = math.Pow(math.Max(0, v), 1/g)
+				break
+			}
+			breakPnt := math.Pow(offset*g/((g-1)*(1+offset)), g)
+			if v >= breakPnt {
+				v = (1+offset)*math.Pow(v, 1/g) - offset
+			} else {
+				v /= monCurveSlope(g, offset)
+			}
+		}
+		rgb[c] = sign * v
+	}
+	return scaleRGB(rgb, 1/n.OutBitDepth.Scale())
+}
+
+// monCurveSlope returns the slope of the linear segment of a monCurve
+func monCurveSlope(g, offset float64) float64 {
+	return (g - 1) / offset * math.Pow(offset*g/((g-1)*(1+offset)), g)
+}
+
+// ChannelParams returns the ExponentParams that apply
+// to a channel index of 0, 1 or 2
+func (n *ExponentNode) ChannelParams(c int) ExponentParams {
+	all := ExponentParams{Exponent: 1}
+	for _, p := range n.Params {
+		if p.Channel == channelNames[c] {
+			return p
+		} else if p.Channel == "" {
+			all = p
+		}
+	}
+	return all
+}
+
+// Validate checks that the ExponentNode can be evaluated
+func (n *ExponentNode) Validate() error {
+	if err := n.NodeHeader.validate(); err != nil {
+		return err
+	}
+	monCurve := false
+	switch n.Style {
+	case ExponentBasicFwd, ExponentBasicRev, ExponentBasicMirrorFwd, ExponentBasicMirrorRev,
+		ExponentBasicPassThruFwd, ExponentBasicPassThruRev:
+	case ExponentMonCurveFwd, ExponentMonCurveRev, ExponentMonCurveMirrorFwd, ExponentMonCurveMirrorRev:
+		monCurve = true
+	default:
+		return fmt.Errorf("unknown Exponent style %q", n.Style)
+	}
+	if len(n.Params) == 0 {
+		return errors.New("Exponent needs ExponentParams")
+	}
+	if err := validateChannels(len(n.Params), func(i int) string { return n.Params[i].Channel }); err != nil {
+		return fmt.Errorf("ExponentParams %v", err)
+	}
+	for _, p := range n.Params {
+		if !(p.Exponent > 0) {
+			return fmt.Errorf("Exponent exponent must be positive, got %v", p.Exponent)
+		}
+		if monCurve && (p.Offset < 0 || (p.Offset > 0 && p.Exponent <= 1)) {
+			return fmt.Errorf("Exponent monCurve needs an offset >= 0 and an exponent > 1, got %v and %v",
+				p.Offset, p.Exponent)
+		}
+	}
+	return nil
+}
+
+var channelNames = [3]string{"R", "G", "B"}
+
+// validateChannels checks that a list of params has a single entry for
+// all channels, or an entry for each of the R, G and B channels
+func validateChannels(n int, channel func(i int) string) error {
+	if n == 1 && channel(0) == "" {
+		return nil
+	}
+	seen := map[string]bool{}
+	for i := 0; i < n; i++ {
+		ch := channel(i)
+		if ch != "R" && ch != "G" && ch != "B" {
+			return fmt.Errorf("must be for a single channel or for each of R, G and B; got channel %q", ch)
+		}
+		if seen[ch] {
+			return fmt.Errorf("has channel %s more than once", ch)
+		}
+		seen[ch] = true
+	}
+	if n != 0 && len(seen) != 3 {
+		return errors.New("must be for a single channel or for each of R, G and B")
+	}
+	return nil
+}
+
+func scaleRGB(rgb [3]float64, scale float64) [3]float64 {
+	if scale != 1 {
+		for c := range rgb {
+			rgb[c] *= scale
+		}
+	}
+	return rgb
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 || v != v {
+		return 0
+	} else if v > 1 {
+		return 1
+	}
+	return v
+}
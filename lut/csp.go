@@ -0,0 +1,181 @@
+package lut
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadCSP reads a Rising Sun Research .csp file, returning a *LUT1D or
+// *LUT3D. A prelut that linearly maps a range to [0, 1] is read as the
+// domain of the LUT, and any other prelut as its Shaper. The metadata
+// block is read as the Title.
+func ReadCSP(r io.Reader) (LUT, error) {
+	lr := newLineReader(r, "csp")
+
+	fields, err := lr.next()
+	if err != nil || fields[0] != "CSPLUTV100" {
+		return nil, lr.errorf("missing CSPLUTV100 header")
+	}
+	if fields, err = lr.next(); err != nil || len(fields) != 1 || (fields[0] != "1D" && fields[0] != "3D") {
+		return nil, lr.errorf("expected 1D or 3D")
+	}
+	is3D := fields[0] == "3D"
+
+	if fields, err = lr.next(); err != nil {
+		return nil, lr.errorf("unexpected end of file")
+	}
+	var metadata []string
+	if fields[0] == "BEGIN" && len(fields) == 2 && fields[1] == "METADATA" {
+		for {
+			if !lr.scanner.Scan() {
+				return nil, lr.errorf("missing END METADATA")
+			}
+			lr.line++
+			line := strings.TrimSpace(lr.scanner.Text())
+			if line == "END METADATA" {
+				break
+			}
+			metadata = append(metadata, line)
+		}
+		if fields, err = lr.next(); err != nil {
+			return nil, lr.errorf("unexpected end of file")
+		}
+	}
+
+	var (
+		prelut   Shaper
+		identity = true
+		lo, hi   [3]float64
+	)
+	for c := range prelut {
+		if c > 0 {
+			if fields, err = lr.next(); err != nil {
+				return nil, lr.errorf("unexpected end of file")
+			}
+		}
+		if len(fields) != 1 {
+			return nil, lr.errorf("expected the number of prelut points")
+		}
+		n, err := strconv.Atoi(fields[0])
+		if err != nil || n < 2 {
+			return nil, lr.errorf("invalid number of prelut points %q", fields[0])
+		}
+		if prelut[c].In, err = lr.nextFloats(n); err != nil {
+			return nil, err
+		}
+		if prelut[c].Out, err = lr.nextFloats(n); err != nil {
+			return nil, err
+		}
+		if err = prelut[c].validate(); err != nil {
+			return nil, lr.errorf("%v", err)
+		}
+		lo[c], hi[c] = prelut[c].In[0], prelut[c].In[n-1]
+		if n != 2 || prelut[c].Out[0] != 0 || prelut[c].Out[1] != 1 {
+			identity = false
+		}
+	}
+	if !identity {
+		lo, hi = [3]float64{}, [3]float64{1, 1, 1}
+	}
+
+	if fields, err = lr.next(); err != nil {
+		return nil, lr.errorf("missing LUT size")
+	}
+	sizes, err := lr.ints(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	if is3D {
+		if len(sizes) != 3 || sizes[0] < 2 || sizes[1] != sizes[0] || sizes[2] != sizes[0] {
+			return nil, lr.errorf("unsupported LUT size %v; must be equal on each axis", sizes)
+		}
+		count = sizes[0] * sizes[0] * sizes[0]
+	} else {
+		if len(sizes) != 1 || sizes[0] < 2 {
+			return nil, lr.errorf("invalid LUT size %v", sizes)
+		}
+		count = sizes[0]
+	}
+
+	values := make([][3]float64, count)
+	for i := range values {
+		v, err := lr.nextFloats(3)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = [3]float64{v[0], v[1], v[2]}
+	}
+
+	var shaper *Shaper
+	if !identity {
+		shaper = &prelut
+	}
+	title := strings.Join(metadata, "\n")
+	if !is3D {
+		return &LUT1D{Title: title, DomainMin: lo, DomainMax: hi, Shaper: shaper, Values: values}, nil
+	}
+	return &LUT3D{Title: title, DomainMin: lo, DomainMax: hi, Shaper: shaper, Size: sizes[0], Values: values}, nil
+}
+
+// WriteCSP writes a *LUT1D or *LUT3D as a .csp file. The domain and
+// Shaper of the LUT are written as the prelut, and the Title as the
+// metadata block.
+func WriteCSP(w io.Writer, l LUT) error {
+	var (
+		kind, title string
+		lo, hi      [3]float64
+		shaper      *Shaper
+		values      [][3]float64
+		size        string
+	)
+	switch l := l.(type) {
+	case *LUT1D:
+		if err := l.Validate(); err != nil {
+			return fmt.Errorf("csp: %w", err)
+		}
+		kind, title, lo, hi, shaper, values = "1D", l.Title, l.DomainMin, l.DomainMax, l.Shaper, l.Values
+		size = strconv.Itoa(l.Size())
+	case *LUT3D:
+		if err := l.Validate(); err != nil {
+			return fmt.Errorf("csp: %w", err)
+		}
+		kind, title, lo, hi, shaper, values = "3D", l.Title, l.DomainMin, l.DomainMax, l.Shaper, l.Values
+		size = fmt.Sprintf("%d %d %d", l.Size, l.Size, l.Size)
+	default:
+		return fmt.Errorf("csp: unsupported LUT type %T; convert it with ToLUT3D", l)
+	}
+	if strings.Contains(title, "END METADATA") {
+		return errors.New("csp: title cannot contain END METADATA")
+	}
+
+	lw := newLUTWriter(w)
+	lw.printf("CSPLUTV100\n%s\n\n", kind)
+	if title != "" {
+		lw.printf("BEGIN METADATA\n%s\nEND METADATA\n\n", title)
+	}
+
+	// The prelut maps input to [0, 1] across the domain
+	for c := 0; c < 3; c++ {
+		curve := Curve{In: []float64{lo[c], hi[c]}, Out: []float64{0, 1}}
+		if shaper != nil {
+			curve = Curve{In: shaper[c].In, Out: make([]float64, len(shaper[c].Out))}
+			for i, v := range shaper[c].Out {
+				curve.Out[i] = (v - lo[c]) / (hi[c] - lo[c])
+			}
+		}
+		lw.printf("%d\n", len(curve.In))
+		lw.floats(curve.In...)
+		lw.floats(curve.Out...)
+	}
+
+	lw.printf("\n%s\n", size)
+	for _, v := range values {
+		lw.floats(v[:]...)
+	}
+	return lw.flush()
+}
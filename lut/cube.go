@@ -0,0 +1,279 @@
+package lut
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadCube reads an Adobe or Resolve .cube file, returning a *LUT1D or
+// *LUT3D. A Resolve file holding both a 1D and a 3D LUT is read as a
+// LUT3D, with the 1D LUT as its Shaper. Its LUT_1D_INPUT_RANGE and
+// LUT_3D_INPUT_RANGE default to [0, 1].
+func ReadCube(r io.Reader) (LUT, error) {
+	lr := newLineReader(r, "cube")
+	lr.comment = "#"
+
+	var (
+		title                  string
+		size1D, size3D         int
+		domainMin              = [3]float64{0, 0, 0}
+		domainMax              = [3]float64{1, 1, 1}
+		range1D, range3D       [2]float64
+		hasRange1D, hasRange3D bool
+		hasDomain              bool
+		values                 [][3]float64
+	)
+	for {
+		fields, err := lr.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		keyword := fields[0]
+		args := fields[1:]
+		switch keyword {
+		case "TITLE":
+			line := strings.TrimSpace(strings.Join(args, " "))
+			title = strings.Trim(line, `"`)
+			continue
+		case "LUT_1D_SIZE", "LUT_3D_SIZE":
+			if len(args) != 1 {
+				return nil, lr.errorf("expected %s to have 1 value", keyword)
+			}
+			size, err := strconv.Atoi(args[0])
+			if err != nil || size < 2 {
+				return nil, lr.errorf("invalid %s %q", keyword, args[0])
+			}
+			if keyword == "LUT_1D_SIZE" {
+				size1D = size
+			} else {
+				size3D = size
+			}
+			continue
+		case "DOMAIN_MIN", "DOMAIN_MAX":
+			if len(args) != 3 {
+				return nil, lr.errorf("expected %s to have 3 values", keyword)
+			}
+			v, err := lr.floats(args)
+			if err != nil {
+				return nil, err
+			}
+			if keyword == "DOMAIN_MIN" {
+				copy(domainMin[:], v)
+			} else {
+				copy(domainMax[:], v)
+			}
+			hasDomain = true
+			continue
+		case "LUT_1D_INPUT_RANGE", "LUT_3D_INPUT_RANGE":
+			if len(args) != 2 {
+				return nil, lr.errorf("expected %s to have 2 values", keyword)
+			}
+			v, err := lr.floats(args)
+			if err != nil {
+				return nil, err
+			}
+			if keyword == "LUT_1D_INPUT_RANGE" {
+				range1D, hasRange1D = [2]float64{v[0], v[1]}, true
+			} else {
+				range3D, hasRange3D = [2]float64{v[0], v[1]}, true
+			}
+			continue
+		}
+
+		if len(fields) != 3 {
+			return nil, lr.errorf("unknown keyword %q", keyword)
+		}
+		v, err := lr.floats(fields)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, [3]float64{v[0], v[1], v[2]})
+	}
+
+	if size1D == 0 && size3D == 0 {
+		return nil, errors.New("cube: missing LUT_1D_SIZE or LUT_3D_SIZE")
+	}
+	expect := size1D + size3D*size3D*size3D
+	if len(values) != expect {
+		return nil, fmt.Errorf("cube: expected %d entries, got %d", expect, len(values))
+	}
+
+	var lut1D *LUT1D
+	if size1D > 0 {
+		lut1D = &LUT1D{
+			Title:     title,
+			DomainMin: domainMin,
+			DomainMax: domainMax,
+			Values:    values[:size1D],
+		}
+		if hasRange1D {
+			lut1D.DomainMin = [3]float64{range1D[0], range1D[0], range1D[0]}
+			lut1D.DomainMax = [3]float64{range1D[1], range1D[1], range1D[1]}
+		}
+		if size3D == 0 {
+			if err := lut1D.Validate(); err != nil {
+				return nil, fmt.Errorf("cube: %w", err)
+			}
+			return lut1D, nil
+		}
+	}
+
+	lut3D := &LUT3D{
+		Title:     title,
+		DomainMin: domainMin,
+		DomainMax: domainMax,
+		Size:      size3D,
+		Values:    values[size1D:],
+	}
+	if hasRange3D {
+		lut3D.DomainMin = [3]float64{range3D[0], range3D[0], range3D[0]}
+		lut3D.DomainMax = [3]float64{range3D[1], range3D[1], range3D[1]}
+	}
+	if lut1D != nil {
+		// The input ranges default to [0, 1], while DOMAIN_MIN
+		// and DOMAIN_MAX are ambiguous with both LUTs
+		if hasDomain {
+			return nil, errors.New("cube: a file with a 1D and 3D LUT must use LUT_1D_INPUT_RANGE and LUT_3D_INPUT_RANGE instead of DOMAIN_MIN and DOMAIN_MAX")
+		}
+		if err := lut1D.Validate(); err != nil {
+			return nil, fmt.Errorf("cube: %w", err)
+		}
+		lut3D.Shaper = lut1D.shaper()
+	}
+	if err := lut3D.Validate(); err != nil {
+		return nil, fmt.Errorf("cube: %w", err)
+	}
+	return lut3D, nil
+}
+
+// WriteCube writes a *LUT1D or *LUT3D as a .cube file. A LUT3D with a
+// Shaper is written in the Resolve format, as a 1D and a 3D LUT, which
+// needs the Shaper to have evenly spaced inputs over the same range on
+// each channel.
+func WriteCube(w io.Writer, l LUT) error {
+	var (
+		lut1D *LUT1D
+		lut3D *LUT3D
+	)
+	switch l := l.(type) {
+	case *LUT1D:
+		if l.Shaper != nil {
+			return errors.New("cube: format does not support a 1D LUT with a shaper; use Resample")
+		}
+		lut1D = l
+	case *LUT3D:
+		lut3D = l
+		if l.Shaper != nil {
+			var ok bool
+			if lut1D, ok = l.Shaper.lut1D(); !ok {
+				return errors.New("cube: format only supports a shaper with evenly spaced inputs over the same range on each channel")
+			}
+			if !uniformDomain(l.DomainMin, l.DomainMax) {
+				return errors.New("cube: format does not support a 3D domain that is different on each channel with a shaper")
+			}
+		}
+	default:
+		return fmt.Errorf("cube: unsupported LUT type %T; convert it with ToLUT3D", l)
+	}
+	if lut1D != nil {
+		if err := lut1D.Validate(); err != nil {
+			return fmt.Errorf("cube: %w", err)
+		}
+	}
+	if lut3D != nil {
+		if err := lut3D.Validate(); err != nil {
+			return fmt.Errorf("cube: %w", err)
+		}
+	}
+
+	title := ""
+	if lut3D != nil {
+		title = lut3D.Title
+	} else {
+		title = lut1D.Title
+	}
+
+	lw := newLUTWriter(w)
+	if title != "" {
+		lw.printf("TITLE %q\n", title)
+	}
+	switch {
+	case lut1D != nil && lut3D != nil:
+		lw.printf("LUT_1D_SIZE %d\n", lut1D.Size())
+		lw.printf("LUT_1D_INPUT_RANGE %s %s\n", formatFloat(lut1D.DomainMin[0]), formatFloat(lut1D.DomainMax[0]))
+		lw.printf("LUT_3D_SIZE %d\n", lut3D.Size)
+		lw.printf("LUT_3D_INPUT_RANGE %s %s\n", formatFloat(lut3D.DomainMin[0]), formatFloat(lut3D.DomainMax[0]))
+	case lut1D != nil:
+		lw.printf("LUT_1D_SIZE %d\n", lut1D.Size())
+		writeCubeDomain(lw, lut1D.DomainMin, lut1D.DomainMax)
+	default:
+		lw.printf("LUT_3D_SIZE %d\n", lut3D.Size)
+		writeCubeDomain(lw, lut3D.DomainMin, lut3D.DomainMax)
+	}
+	if lut1D != nil {
+		for _, v := range lut1D.Values {
+			lw.floats(v[:]...)
+		}
+	}
+	if lut3D != nil {
+		for _, v := range lut3D.Values {
+			lw.floats(v[:]...)
+		}
+	}
+	return lw.flush()
+}
+
+func writeCubeDomain(lw *lutWriter, lo, hi [3]float64) {
+	if lo == [3]float64{} && hi == [3]float64{1, 1, 1} {
+		return
+	}
+	lw.printf("DOMAIN_MIN ")
+	lw.floats(lo[:]...)
+	lw.printf("DOMAIN_MAX ")
+	lw.floats(hi[:]...)
+}
+
+// shaper returns the LUT1D as a Shaper, with a point for each entry
+func (l *LUT1D) shaper() *Shaper {
+	var s Shaper
+	for c := range s {
+		s[c] = Curve{In: make([]float64, len(l.Values)), Out: make([]float64, len(l.Values))}
+		for i, v := range l.Values {
+			s[c].In[i] = lattice(i, len(l.Values), l.DomainMin, l.DomainMax)[c]
+			s[c].Out[i] = v[c]
+		}
+	}
+	return &s
+}
+
+// lut1D returns the Shaper as a LUT1D, if its inputs are evenly
+// spaced over the same range on each channel
+func (s *Shaper) lut1D() (*LUT1D, bool) {
+	n := len(s[0].In)
+	lo, hi := s[0].In[0], s[0].In[n-1]
+	l := &LUT1D{
+		DomainMin: [3]float64{lo, lo, lo},
+		DomainMax: [3]float64{hi, hi, hi},
+		Values:    make([][3]float64, n),
+	}
+	tolerance := 1e-9 * (hi - lo)
+	for c := range s {
+		if len(s[c].In) != n || len(s[c].Out) != n {
+			return nil, false
+		}
+		for i, in := range s[c].In {
+			if d := in - lattice(i, n, l.DomainMin, l.DomainMax)[c]; d > tolerance || d < -tolerance {
+				return nil, false
+			}
+			l.Values[i][c] = s[c].Out[i]
+		}
+	}
+	return l, true
+}
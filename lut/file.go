@@ -0,0 +1,114 @@
+package lut
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadFile reads a LUT file, choosing the format from the
+// file extension: .spi1d, .spi3d, .spimtx, .3dl, .cube, .csp or .clf.
+// A .3dl file is read with Read3DL, which infers the bit depth.
+func ReadFile(path string) (LUT, error) {
+	read, _, err := formatFor(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	l, err := read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// WriteFile writes a LUT file, choosing the format from the file
+// extension: .spi1d, .spi3d, .spimtx, .3dl, .cube, .csp or .clf. A .3dl
+// file is written with values of Default3DLOutputBitDepth, and a LUT
+// other than a ProcessList is appended to a new ProcessList for a .clf
+// file. Otherwise the LUT must be of a type supported by the format,
+// which can be converted with methods such as ToLUT3D.
+func WriteFile(path string, l LUT) error {
+	_, write, err := formatFor(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = write(f, l); err != nil {
+		f.Close()
+		os.Remove(path)
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return f.Close()
+}
+
+func formatFor(path string) (func(io.Reader) (LUT, error), func(io.Writer, LUT) error, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".spi1d":
+		return func(r io.Reader) (LUT, error) { return ReadSpi1D(r) },
+			func(w io.Writer, l LUT) error {
+				l1, ok := l.(*LUT1D)
+				if !ok {
+					return fmt.Errorf("spi1d: unsupported LUT type %T", l)
+				}
+				return WriteSpi1D(w, l1)
+			}, nil
+	case ".spi3d":
+		return func(r io.Reader) (LUT, error) { return ReadSpi3D(r) },
+			func(w io.Writer, l LUT) error {
+				l3, ok := l.(*LUT3D)
+				if !ok {
+					return fmt.Errorf("spi3d: unsupported LUT type %T; convert it with ToLUT3D", l)
+				}
+				return WriteSpi3D(w, l3)
+			}, nil
+	case ".spimtx":
+		return func(r io.Reader) (LUT, error) { return ReadSpiMtx(r) },
+			func(w io.Writer, l LUT) error {
+				m, ok := l.(*Matrix)
+				if !ok {
+					return fmt.Errorf("spimtx: unsupported LUT type %T", l)
+				}
+				return WriteSpiMtx(w, m)
+			}, nil
+	case ".3dl":
+		return func(r io.Reader) (LUT, error) { return Read3DL(r) },
+			func(w io.Writer, l LUT) error {
+				l3, ok := l.(*LUT3D)
+				if !ok {
+					return fmt.Errorf("3dl: unsupported LUT type %T; convert it with ToLUT3D", l)
+				}
+				return Write3DL(w, l3, Default3DLOutputBitDepth)
+			}, nil
+	case ".cube":
+		return ReadCube, WriteCube, nil
+	case ".csp":
+		return ReadCSP, WriteCSP, nil
+	case ".clf":
+		return func(r io.Reader) (LUT, error) { return ReadCLF(r) },
+			func(w io.Writer, l LUT) error {
+				p, ok := l.(*ProcessList)
+				if !ok {
+					p = NewProcessList(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+					if err := p.Append(l); err != nil {
+						return fmt.Errorf("clf: %w", err)
+					}
+				}
+				return WriteCLF(w, p)
+			}, nil
+	default:
+		return nil, nil, fmt.Errorf("%s: unsupported LUT file extension %q", path, ext)
+	}
+}
@@ -0,0 +1,362 @@
+package lut
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testLutDir = "../testdata/spi-vfx/luts"
+
+func readTestLUT(t *testing.T, name string) LUT {
+	l, err := ReadFile(filepath.Join(testLutDir, name))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return l
+}
+
+func TestReadFile(t *testing.T) {
+	lg10, ok := readTestLUT(t, "lg10.spi1d").(*LUT1D)
+	if !ok {
+		t.Fatal("expected a 1D LUT")
+	}
+	if lg10.Size() != 2048 || lg10.DomainMin != [3]float64{} || lg10.DomainMax != [3]float64{1, 1, 1} {
+		t.Errorf("expected 2048 entries over [0, 1], got %d over %v to %v",
+			lg10.Size(), lg10.DomainMin, lg10.DomainMax)
+	}
+	if first := lg10.Values[0]; first != [3]float64{3.0517578125e-05, 3.0517578125e-05, 3.0517578125e-05} {
+		t.Errorf("unexpected first entry %v", first)
+	}
+	if last := lg10.Values[2047]; last != [3]float64{64, 64, 64} {
+		t.Errorf("unexpected last entry %v", last)
+	}
+
+	gnf := readTestLUT(t, "gnf.spi1d").(*LUT1D)
+	if gnf.DomainMin[0] != -0.25 || gnf.DomainMax[0] != 3.055 {
+		t.Errorf("expected a domain of [-0.25, 3.055], got %v to %v", gnf.DomainMin, gnf.DomainMax)
+	}
+
+	offset, ok := readTestLUT(t, "hdOffset.spimtx").(*Matrix)
+	if !ok {
+		t.Fatal("expected a Matrix")
+	}
+	if expect := 4100.0 / 65535; offset.Offset[0] != expect || !offset.IsDiagonal() {
+		t.Errorf("expected a diagonal matrix with offset %v, got %+v", expect, offset)
+	}
+
+	srgb, ok := readTestLUT(t, "spi_ocio_srgb_test.spi3d").(*LUT3D)
+	if !ok {
+		t.Fatal("expected a 3D LUT")
+	}
+	if srgb.Size != 32 {
+		t.Errorf("expected size 32, got %d", srgb.Size)
+	}
+	if expect := [3]float64{0.040157, 0.039086, 0.029591}; srgb.Values[srgb.Index(0, 0, 1)] != expect {
+		t.Errorf("expected the entry for 0 0 1 to be %v, got %v", expect, srgb.Values[srgb.Index(0, 0, 1)])
+	}
+
+	film, ok := readTestLUT(t, "colorworks_filmlg_to_p3.3dl").(*LUT3D)
+	if !ok {
+		t.Fatal("expected a 3D LUT")
+	}
+	if film.Size != 17 || film.Shaper != nil {
+		t.Errorf("expected size 17 with a uniform mesh, got %d and shaper %v", film.Size, film.Shaper)
+	}
+	for _, v := range film.Values {
+		if !nearlyEqual(v, [3]float64{0.5, 0.5, 0.5}, 0.5) {
+			t.Fatalf("expected 12-bit values scaled to [0, 1], got %v", v)
+		}
+	}
+
+	if _, err := ReadFile(filepath.Join(testLutDir, "lg10.lut")); err == nil {
+		t.Error("expected an error for an unsupported extension")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lut")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	for _, tc := range []struct {
+		name, ext string
+		tolerance float64
+	}{
+		{"lg10.spi1d", ".spi1d", 0},
+		{"gnf.spi1d", ".spi1d", 0},
+		{"lg10.spi1d", ".cube", 0},
+		{"lg10.spi1d", ".csp", 0},
+		{"hdOffset.spimtx", ".spimtx", 1e-12},
+		{"p3_to_xyz16.spimtx", ".spimtx", 0},
+		{"spi_ocio_srgb_test.spi3d", ".spi3d", 0},
+		{"spi_ocio_srgb_test.spi3d", ".cube", 0},
+		{"spi_ocio_srgb_test.spi3d", ".csp", 0},
+		{"colorworks_filmlg_to_p3.3dl", ".3dl", 0},
+		{"colorworks_filmlg_to_p3.3dl", ".spi3d", 0},
+		{"colorworks_filmlg_to_p3.3dl", ".cube", 0},
+	} {
+		in := readTestLUT(t, tc.name)
+		path := filepath.Join(dir, strings.TrimSuffix(tc.name, filepath.Ext(tc.name))+tc.ext)
+		if err = WriteFile(path, in); err != nil {
+			t.Errorf("%s to %s: %s", tc.name, tc.ext, err)
+			continue
+		}
+		out, err := ReadFile(path)
+		if err != nil {
+			t.Errorf("%s to %s: %s", tc.name, tc.ext, err)
+			continue
+		}
+		if msg := compareLUTs(in, out, tc.tolerance); msg != "" {
+			t.Errorf("%s to %s: %s", tc.name, tc.ext, msg)
+		}
+	}
+
+	lut3D := readTestLUT(t, "spi_ocio_srgb_test.spi3d")
+	if err = WriteFile(filepath.Join(dir, "srgb.spi1d"), lut3D); err == nil {
+		t.Error("expected an error writing a 3D LUT as spi1d")
+	}
+	if _, err = os.Stat(filepath.Join(dir, "srgb.spi1d")); !os.IsNotExist(err) {
+		t.Error("expected a failed write to remove the file")
+	}
+}
+
+func compareLUTs(a, b LUT, tolerance float64) string {
+	switch a := a.(type) {
+	case *LUT1D:
+		b, ok := b.(*LUT1D)
+		if !ok {
+			return "expected a 1D LUT"
+		}
+		if a.DomainMin != b.DomainMin || a.DomainMax != b.DomainMax {
+			return "domain changed"
+		}
+		return compareValues(a.Values, b.Values, tolerance)
+	case *LUT3D:
+		b, ok := b.(*LUT3D)
+		if !ok {
+			return "expected a 3D LUT"
+		}
+		if a.Size != b.Size || a.DomainMin != b.DomainMin || a.DomainMax != b.DomainMax {
+			return "size or domain changed"
+		}
+		return compareValues(a.Values, b.Values, tolerance)
+	case *Matrix:
+		b, ok := b.(*Matrix)
+		if !ok {
+			return "expected a Matrix"
+		}
+		if a.M != b.M {
+			return "matrix changed"
+		}
+		return compareValues([][3]float64{{a.Offset[0], a.Offset[1], a.Offset[2]}},
+			[][3]float64{{b.Offset[0], b.Offset[1], b.Offset[2]}}, tolerance)
+	}
+	return "unexpected LUT type"
+}
+
+func compareValues(a, b [][3]float64, tolerance float64) string {
+	if len(a) != len(b) {
+		return "number of entries changed"
+	}
+	for i := range a {
+		if !nearlyEqual(a[i], b[i], tolerance) {
+			return "values changed"
+		}
+	}
+	return ""
+}
+
+func TestCubeShaper(t *testing.T) {
+	const cube = `# Resolve style 1D shaper and 3D LUT
+TITLE "shaper test"
+LUT_1D_SIZE 3
+LUT_1D_INPUT_RANGE 0 4
+LUT_3D_SIZE 2
+LUT_3D_INPUT_RANGE 0 1
+0 0 0
+0.75 0.75 0.75
+1 1 1
+0 0 0
+1 0 0
+0 1 0
+1 1 0
+0 0 1
+1 0 1
+0 1 1
+1 1 1
+`
+	l, err := ReadCube(strings.NewReader(cube))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	lut3D, ok := l.(*LUT3D)
+	if !ok || lut3D.Shaper == nil {
+		t.Fatalf("expected a 3D LUT with a shaper, got %#v", l)
+	}
+	if lut3D.Title != "shaper test" {
+		t.Errorf("expected the title to be read, got %q", lut3D.Title)
+	}
+	expect := [3]float64{0.375, 0.75, 1}
+	if actual := lut3D.Eval([3]float64{1, 2, 4}); !nearlyEqual(actual, expect, 1e-12) {
+		t.Errorf("expected %v, got %v", expect, actual)
+	}
+
+	// The input ranges are optional, and default to [0, 1]
+	noRanges := strings.NewReplacer("LUT_1D_INPUT_RANGE 0 4\n", "", "LUT_3D_INPUT_RANGE 0 1\n", "").Replace(cube)
+	defaulted, err := ReadCube(strings.NewReader(noRanges))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if actual := defaulted.Eval([3]float64{0.25, 0.5, 1}); !nearlyEqual(actual, expect, 1e-12) {
+		t.Errorf("expected %v with the default input ranges, got %v", expect, actual)
+	}
+	withDomain := strings.Replace(noRanges, "LUT_1D_SIZE", "DOMAIN_MAX 1 1 1\nLUT_1D_SIZE", 1)
+	if _, err = ReadCube(strings.NewReader(withDomain)); err == nil {
+		t.Error("expected an error for DOMAIN_MAX with a 1D and 3D LUT")
+	}
+
+	var buf bytes.Buffer
+	if err = WriteCube(&buf, lut3D); err != nil {
+		t.Fatal(err.Error())
+	}
+	roundTrip, err := ReadCube(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if actual := roundTrip.Eval([3]float64{1, 2, 4}); !nearlyEqual(actual, expect, 1e-12) {
+		t.Errorf("expected %v after writing, got %v", expect, actual)
+	}
+
+	// A .csp prelut holds any shaper
+	lut3D.Shaper[1].In[1] = 3
+	buf.Reset()
+	if err = WriteCube(&buf, lut3D); err == nil {
+		t.Error("expected an error writing an uneven shaper to a cube file")
+	}
+	if err = WriteCSP(&buf, lut3D); err != nil {
+		t.Fatal(err.Error())
+	}
+	csp, err := ReadCSP(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if title := csp.(*LUT3D).Title; title != "shaper test" {
+		t.Errorf("expected the title to be written as metadata, got %q", title)
+	}
+	for _, in := range [][3]float64{{1, 2, 4}, {0.5, 3.5, 2}} {
+		if actual, expect := csp.Eval(in), lut3D.Eval(in); !nearlyEqual(actual, expect, 1e-12) {
+			t.Errorf("%v: expected %v from the csp, got %v", in, expect, actual)
+		}
+	}
+}
+
+func Test3DLBitDepth(t *testing.T) {
+	dark := NewLUT3D(2)
+	for i, v := range dark.Values {
+		dark.Values[i] = [3]float64{v[0] * 0.2, v[1] * 0.2, v[2] * 0.2}
+	}
+	var buf bytes.Buffer
+	if err := Write3DL(&buf, dark, 12); err != nil {
+		t.Fatal(err.Error())
+	}
+	data := buf.String()
+
+	// The largest value fits in 10 bits, so the bit depth is guessed wrong
+	guessed, err := Read3DL(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if actual := guessed.Values[len(guessed.Values)-1][0]; actual < 0.7 {
+		t.Errorf("expected the dark LUT to be read as 10 bits, got %v", actual)
+	}
+
+	l, err := Read3DLBitDepth(strings.NewReader(data), 12)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for i, v := range l.Values {
+		if !nearlyEqual(v, dark.Values[i], 1.0/4095) {
+			t.Errorf("value %d: expected %v, got %v", i, dark.Values[i], v)
+		}
+	}
+
+	if _, err = Read3DLBitDepth(strings.NewReader(data), 8); err == nil {
+		t.Error("expected an error for values out of range of the bit depth")
+	}
+	if _, err = Read3DLBitDepth(strings.NewReader(data), 32); err == nil {
+		t.Error("expected an error for an unsupported bit depth")
+	}
+
+	// A Lustre "Mesh 4 12" header gives the bit depth, and the
+	// "LUT8" line after the values is ignored
+	lustre, err := ReadFile(filepath.Join("../testdata/3dl", "lustre_dark.3dl"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	l, ok := lustre.(*LUT3D)
+	if !ok || l.Size != 17 || l.Shaper != nil {
+		t.Fatalf("expected a 3D LUT of size 17 with a uniform mesh, got %#v", lustre)
+	}
+	if expect := [3]float64{819.0 / 4095, 819.0 / 4095, 819.0 / 4095}; l.Values[len(l.Values)-1] != expect {
+		t.Errorf("expected the last value to be read as 12 bits, %v, got %v", expect, l.Values[len(l.Values)-1])
+	}
+
+	if _, err = Read3DL(strings.NewReader("Mesh 4 32\n0 1023\n")); err == nil {
+		t.Error("expected an error for an unsupported bit depth header")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		read func(string) error
+		data string
+	}{
+		{"spi1d length", readString(ReadSpi1D), "Version 1\nLength 3\n{\n0\n1\n}\n"},
+		{"spi1d components", readString(ReadSpi1D), "Version 1\nLength 2\nComponents 3\n{\n0\n1\n}\n"},
+		{"spi1d unclosed", readString(ReadSpi1D), "Version 1\nLength 2\n{\n0\n1\n"},
+		{"spi3d missing", readString(ReadSpi3D), "SPILUT 1.0\n3 3\n2 2 2\n0 0 0 0 0 0\n"},
+		{"spi3d index", readString(ReadSpi3D), "SPILUT 1.0\n3 3\n2 2 2\n0 0 2 0 0 0\n"},
+		{"spimtx count", readString(ReadSpiMtx), "1 0 0 0\n0 1 0 0\n"},
+		{"3dl count", readString(Read3DL), "0 1023\n0 0 0\n"},
+		{"cube size", readString(ReadCube), "LUT_3D_SIZE 2\n0 0 0\n"},
+		{"cube keyword", readString(ReadCube), "LUT_3D_SIZE 2\nLUT_SIZE 2\n"},
+		{"csp header", readString(ReadCSP), "CSPLUTV200\n1D\n"},
+		{"csp prelut", readString(ReadCSP), "CSPLUTV100\n1D\n2\n0 1\n0\n"},
+	} {
+		if err := tc.read(tc.data); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+
+	err := readString(ReadSpi1D)("Version 1\nLength 2\n{\n0\nx\n}\n")
+	if err == nil || err.Error() != `spi1d: line 5: invalid number "x"` {
+		t.Errorf("expected an error with the line number, got %v", err)
+	}
+}
+
+// readString adapts a Read function to read from a string
+func readString(read interface{}) func(string) error {
+	return func(data string) error {
+		r := strings.NewReader(data)
+		var err error
+		switch read := read.(type) {
+		case func(io.Reader) (*LUT1D, error):
+			_, err = read(r)
+		case func(io.Reader) (*LUT3D, error):
+			_, err = read(r)
+		case func(io.Reader) (*Matrix, error):
+			_, err = read(r)
+		case func(io.Reader) (LUT, error):
+			_, err = read(r)
+		}
+		return err
+	}
+}
@@ -0,0 +1,68 @@
+package lut
+
+import "math"
+
+// halfToFloat returns the value of a half-float bit pattern
+func halfToFloat(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * mant * 0x1p-24
+	case 0x1f:
+		if mant == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	}
+	return sign * (1 + mant/1024) * math.Ldexp(1, exp-15)
+}
+
+// floatToHalf returns the bit pattern of the nearest half-float to v
+func floatToHalf(v float64) uint16 {
+	var sign uint16
+	if math.Signbit(v) {
+		sign, v = 0x8000, -v
+	}
+	switch {
+	case v != v:
+		return 0x7e00
+	case v >= 65520:
+		return sign | 0x7c00
+	case v < 0x1p-14:
+		// Subnormal, which may round up to the smallest normal
+		return sign | uint16(math.RoundToEven(v*0x1p24))
+	}
+	frac, exp := math.Frexp(v)
+	mant := math.RoundToEven((frac*2 - 1) * 1024)
+	// A mantissa that rounds up to 1024 carries into the exponent
+	return sign | (uint16(exp+14)<<10 + uint16(mant))
+}
+
+// halfDomainIndex returns the index into a 65536 entry halfDomain
+// LUT1D of the half-float at or below the magnitude of v, and the
+// fraction of the way to the next entry
+func halfDomainIndex(v float64) (int, float64) {
+	var sign uint16
+	if v < 0 {
+		sign, v = 0x8000, -v
+	}
+	if v != v {
+		return 0, 0
+	}
+
+	// Keep the next entry within the finite values
+	const maxIndex = 0x7bfe
+	h := floatToHalf(v)
+	if h > maxIndex {
+		h = maxIndex
+	} else if halfToFloat(h) > v {
+		h--
+	}
+	lo, hi := halfToFloat(h), halfToFloat(h+1)
+	return int(sign | h), clamp01((v - lo) / (hi - lo))
+}
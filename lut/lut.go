@@ -0,0 +1,446 @@
+/*
+Package lut reads and writes color lookup table files, without OpenColorIO.
+
+It supports the Sony Pictures Imageworks .spi1d, .spi3d and .spimtx
+formats, Autodesk .3dl, Adobe / Resolve .cube and Rising Sun .csp files,
+as LUT1D, LUT3D and Matrix values that can be inspected, evaluated,
+converted between each other, and written back out.
+*/
+package lut
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// LUT is implemented by *LUT1D, *LUT3D and *Matrix
+type LUT interface {
+	// Eval applies the LUT to an RGB value
+	Eval(rgb [3]float64) [3]float64
+}
+
+// Curve is a piecewise-linear curve through the points (In[i], Out[i]),
+// where In is increasing. Input outside of In is clamped.
+type Curve struct {
+	In, Out []float64
+}
+
+// Eval returns the value of the curve at x
+func (c Curve) Eval(x float64) float64 {
+	n := len(c.In)
+	if n == 0 {
+		return x
+	}
+	if x <= c.In[0] || n == 1 {
+		return c.Out[0]
+	}
+	if x >= c.In[n-1] {
+		return c.Out[n-1]
+	}
+	i := sort.SearchFloat64s(c.In, x)
+	if c.In[i] == x {
+		return c.Out[i]
+	}
+	t := (x - c.In[i-1]) / (c.In[i] - c.In[i-1])
+	return c.Out[i-1] + t*(c.Out[i]-c.Out[i-1])
+}
+
+func (c Curve) validate() error {
+	if len(c.In) < 2 || len(c.In) != len(c.Out) {
+		return fmt.Errorf("shaper curve needs at least 2 points, with as many outputs as inputs; got %d and %d",
+			len(c.In), len(c.Out))
+	}
+	for i := 1; i < len(c.In); i++ {
+		if !(c.In[i] > c.In[i-1]) {
+			return fmt.Errorf("shaper curve inputs must be increasing; got %v after %v", c.In[i], c.In[i-1])
+		}
+	}
+	return nil
+}
+
+// Shaper is a curve for each of the red, green and blue channels,
+// applied by a LUT1D or LUT3D before its domain. It is used for the
+// prelut of a .csp file, a non-uniform .3dl input mesh, and the 1D
+// shaper of a .cube file that also holds a 3D LUT.
+type Shaper [3]Curve
+
+// Eval applies the curves to an RGB value. A nil Shaper is an identity.
+func (s *Shaper) Eval(rgb [3]float64) [3]float64 {
+	if s == nil {
+		return rgb
+	}
+	for c := range rgb {
+		rgb[c] = s[c].Eval(rgb[c])
+	}
+	return rgb
+}
+
+// inputRange returns the range of values mapped by the Shaper
+func (s *Shaper) inputRange() (lo, hi [3]float64) {
+	for c := range s {
+		lo[c], hi[c] = s[c].In[0], s[c].In[len(s[c].In)-1]
+	}
+	return lo, hi
+}
+
+func (s *Shaper) validate() error {
+	if s == nil {
+		return nil
+	}
+	for c := range s {
+		if err := s[c].validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LUT1D is a 1D LUT, holding an RGB output for each of Size()
+// evenly spaced input values from DomainMin to DomainMax
+type LUT1D struct {
+	Title string
+
+	// DomainMin and DomainMax are the input values of the first
+	// and last entries. Input outside of the domain is clamped.
+	DomainMin, DomainMax [3]float64
+
+	// Shaper is an optional curve applied before the domain
+	Shaper *Shaper
+
+	Values [][3]float64
+}
+
+// NewLUT1D returns an identity LUT1D with size entries,
+// over the domain [0, 1]
+func NewLUT1D(size int) *LUT1D {
+	l := &LUT1D{
+		DomainMax: [3]float64{1, 1, 1},
+		Values:    make([][3]float64, size),
+	}
+	for i := range l.Values {
+		v := float64(i) / float64(size-1)
+		l.Values[i] = [3]float64{v, v, v}
+	}
+	return l
+}
+
+// Size returns the number of entries
+func (l *LUT1D) Size() int {
+	return len(l.Values)
+}
+
+// Validate checks that the LUT1D can be evaluated
+func (l *LUT1D) Validate() error {
+	if len(l.Values) < 2 {
+		return fmt.Errorf("1D LUT needs at least 2 entries, got %d", len(l.Values))
+	}
+	if err := validateDomain(l.DomainMin, l.DomainMax); err != nil {
+		return err
+	}
+	return l.Shaper.validate()
+}
+
+// Eval applies the LUT1D to an RGB value, with linear interpolation
+func (l *LUT1D) Eval(rgb [3]float64) [3]float64 {
+	rgb = normalize(l.Shaper.Eval(rgb), l.DomainMin, l.DomainMax)
+	last := len(l.Values) - 1
+	for c, v := range rgb {
+		pos := v * float64(last)
+		i := int(pos)
+		if i >= last {
+			i = last - 1
+		}
+		frac := pos - float64(i)
+		rgb[c] = l.Values[i][c] + (l.Values[i+1][c]-l.Values[i][c])*frac
+	}
+	return rgb
+}
+
+// inputRange returns the range of input values mapped by the LUT
+func (l *LUT1D) inputRange() (lo, hi [3]float64) {
+	if l.Shaper != nil {
+		return l.Shaper.inputRange()
+	}
+	return l.DomainMin, l.DomainMax
+}
+
+// Resample returns a LUT1D with size entries, evaluated over the same
+// input range, without a Shaper
+func (l *LUT1D) Resample(size int) *LUT1D {
+	lo, hi := l.inputRange()
+	out := &LUT1D{
+		Title:     l.Title,
+		DomainMin: lo,
+		DomainMax: hi,
+		Values:    make([][3]float64, size),
+	}
+	for i := range out.Values {
+		out.Values[i] = l.Eval(lattice(i, size, lo, hi))
+	}
+	return out
+}
+
+// ToLUT3D returns a LUT3D with the given edge size,
+// evaluated over the same input range
+func (l *LUT1D) ToLUT3D(size int) *LUT3D {
+	lo, hi := l.inputRange()
+	return sampleLUT3D(l, l.Title, size, lo, hi)
+}
+
+// LUT3D is a 3D LUT, holding an RGB output for each of Size^3 evenly
+// spaced input values from DomainMin to DomainMax, with red changing
+// fastest. Use Index to find the entry for a lattice point.
+type LUT3D struct {
+	Title string
+
+	// DomainMin and DomainMax are the input values of the first
+	// and last lattice points. Input outside of the domain is clamped.
+	DomainMin, DomainMax [3]float64
+
+	// Shaper is an optional curve applied before the domain
+	Shaper *Shaper
+
+	// Size is the edge length of the lattice
+	Size int
+
+	Values [][3]float64
+}
+
+// NewLUT3D returns an identity LUT3D with the given edge size,
+// over the domain [0, 1]
+func NewLUT3D(size int) *LUT3D {
+	return sampleLUT3D(nil, "", size, [3]float64{}, [3]float64{1, 1, 1})
+}
+
+// Index returns the index into Values of a lattice point
+func (l *LUT3D) Index(r, g, b int) int {
+	return r + l.Size*(g+l.Size*b)
+}
+
+// Validate checks that the LUT3D can be evaluated
+func (l *LUT3D) Validate() error {
+	if l.Size < 2 {
+		return fmt.Errorf("3D LUT size must be at least 2, got %d", l.Size)
+	}
+	if len(l.Values) != l.Size*l.Size*l.Size {
+		return fmt.Errorf("3D LUT of size %d needs %d entries, got %d",
+			l.Size, l.Size*l.Size*l.Size, len(l.Values))
+	}
+	if err := validateDomain(l.DomainMin, l.DomainMax); err != nil {
+		return err
+	}
+	return l.Shaper.validate()
+}
+
+// Eval applies the LUT3D to an RGB value, with trilinear interpolation
+func (l *LUT3D) Eval(rgb [3]float64) [3]float64 {
+	rgb = normalize(l.Shaper.Eval(rgb), l.DomainMin, l.DomainMax)
+	return interpolate3D(l.Values, l.Size, rgb, false)
+}
+
+// Resample returns a LUT3D with the given edge size, evaluated
+// over the same input range, without a Shaper
+func (l *LUT3D) Resample(size int) *LUT3D {
+	lo, hi := l.DomainMin, l.DomainMax
+	if l.Shaper != nil {
+		lo, hi = l.Shaper.inputRange()
+	}
+	return sampleLUT3D(l, l.Title, size, lo, hi)
+}
+
+// Matrix is a row-major 4x4 matrix and RGBA offset,
+// in the same form as an OCIO MatrixTransform
+type Matrix struct {
+	M      [16]float64
+	Offset [4]float64
+}
+
+// NewMatrix returns an identity Matrix
+func NewMatrix() *Matrix {
+	return &Matrix{M: [16]float64{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}}
+}
+
+// Eval applies the Matrix to an RGB value. The alpha
+// column of the matrix is ignored.
+func (m *Matrix) Eval(rgb [3]float64) [3]float64 {
+	var out [3]float64
+	for r := 0; r < 3; r++ {
+		out[r] = m.M[r*4]*rgb[0] + m.M[r*4+1]*rgb[1] + m.M[r*4+2]*rgb[2] + m.Offset[r]
+	}
+	return out
+}
+
+// IsDiagonal reports whether the Matrix has no crosstalk
+// between the red, green and blue channels
+func (m *Matrix) IsDiagonal() bool {
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			if r != c && m.M[r*4+c] != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ToLUT1D returns a LUT1D with size entries, evaluating the Matrix over
+// the domain [0, 1]. It returns an error if the Matrix is not diagonal,
+// since a 1D LUT cannot represent crosstalk between channels.
+func (m *Matrix) ToLUT1D(size int) (*LUT1D, error) {
+	if !m.IsDiagonal() {
+		return nil, errors.New("cannot convert a Matrix with crosstalk between channels to a 1D LUT")
+	}
+	l := NewLUT1D(size)
+	for i, v := range l.Values {
+		l.Values[i] = m.Eval(v)
+	}
+	return l, nil
+}
+
+// ToLUT3D returns a LUT3D with the given edge size,
+// evaluating the Matrix over the domain [0, 1]
+func (m *Matrix) ToLUT3D(size int) *LUT3D {
+	return sampleLUT3D(m, "", size, [3]float64{}, [3]float64{1, 1, 1})
+}
+
+// sampleLUT3D evaluates src (or an identity, if nil) over
+// a size^3 lattice spanning the domain
+func sampleLUT3D(src LUT, title string, size int, lo, hi [3]float64) *LUT3D {
+	l := &LUT3D{
+		Title:     title,
+		DomainMin: lo,
+		DomainMax: hi,
+		Size:      size,
+		Values:    make([][3]float64, size*size*size),
+	}
+	for b := 0; b < size; b++ {
+		for g := 0; g < size; g++ {
+			for r := 0; r < size; r++ {
+				v := [3]float64{
+					lattice(r, size, lo, hi)[0],
+					lattice(g, size, lo, hi)[1],
+					lattice(b, size, lo, hi)[2],
+				}
+				if src != nil {
+					v = src.Eval(v)
+				}
+				l.Values[l.Index(r, g, b)] = v
+			}
+		}
+	}
+	return l
+}
+
+// lattice returns the input value of entry i of size,
+// spanning the domain on each channel
+func lattice(i, size int, lo, hi [3]float64) [3]float64 {
+	t := float64(i) / float64(size-1)
+	var v [3]float64
+	for c := range v {
+		v[c] = lo[c] + t*(hi[c]-lo[c])
+	}
+	return v
+}
+
+// interpolate3D returns the value at normalized rgb of a lattice with red
+// changing fastest, with trilinear or tetrahedral interpolation
+func interpolate3D(values [][3]float64, size int, rgb [3]float64, tetrahedral bool) [3]float64 {
+	var (
+		idx  [3]int
+		frac [3]float64
+	)
+	last := size - 1
+	for c, v := range rgb {
+		pos := v * float64(last)
+		i := int(pos)
+		if i >= last {
+			i = last - 1
+		}
+		idx[c], frac[c] = i, pos-float64(i)
+	}
+
+	at := func(dr, dg, db int) [3]float64 {
+		return values[idx[0]+dr+size*(idx[1]+dg+size*(idx[2]+db))]
+	}
+	if tetrahedral {
+		return tetrahedral3D(at, frac[0], frac[1], frac[2])
+	}
+
+	lerp := func(a, b [3]float64, t float64) [3]float64 {
+		for c := range a {
+			a[c] += (b[c] - a[c]) * t
+		}
+		return a
+	}
+	c00 := lerp(at(0, 0, 0), at(1, 0, 0), frac[0])
+	c10 := lerp(at(0, 1, 0), at(1, 1, 0), frac[0])
+	c01 := lerp(at(0, 0, 1), at(1, 0, 1), frac[0])
+	c11 := lerp(at(0, 1, 1), at(1, 1, 1), frac[0])
+	return lerp(lerp(c00, c10, frac[1]), lerp(c01, c11, frac[1]), frac[2])
+}
+
+// tetrahedral3D interpolates within the lattice cell returned by at,
+// splitting the cell into 6 tetrahedra
+func tetrahedral3D(at func(dr, dg, db int) [3]float64, fr, fg, fb float64) [3]float64 {
+	var (
+		w       [4]float64
+		corners [4][3]float64
+	)
+	c000, c111 := at(0, 0, 0), at(1, 1, 1)
+	switch {
+	case fr > fg && fg > fb:
+		w = [4]float64{1 - fr, fr - fg, fg - fb, fb}
+		corners = [4][3]float64{c000, at(1, 0, 0), at(1, 1, 0), c111}
+	case fr > fg && fr > fb:
+		w = [4]float64{1 - fr, fr - fb, fb - fg, fg}
+		corners = [4][3]float64{c000, at(1, 0, 0), at(1, 0, 1), c111}
+	case fr > fg:
+		w = [4]float64{1 - fb, fb - fr, fr - fg, fg}
+		corners = [4][3]float64{c000, at(0, 0, 1), at(1, 0, 1), c111}
+	case fb > fg:
+		w = [4]float64{1 - fb, fb - fg, fg - fr, fr}
+		corners = [4][3]float64{c000, at(0, 0, 1), at(0, 1, 1), c111}
+	case fb > fr:
+		w = [4]float64{1 - fg, fg - fb, fb - fr, fr}
+		corners = [4][3]float64{c000, at(0, 1, 0), at(0, 1, 1), c111}
+	default:
+		w = [4]float64{1 - fg, fg - fr, fr - fb, fb}
+		corners = [4][3]float64{c000, at(0, 1, 0), at(1, 1, 0), c111}
+	}
+	var out [3]float64
+	for i, corner := range corners {
+		for c := range out {
+			out[c] += w[i] * corner[c]
+		}
+	}
+	return out
+}
+
+// normalize maps rgb from the domain to [0, 1], clamped
+func normalize(rgb, lo, hi [3]float64) [3]float64 {
+	for c, v := range rgb {
+		v = (v - lo[c]) / (hi[c] - lo[c])
+		if v < 0 || v != v {
+			v = 0
+		} else if v > 1 {
+			v = 1
+		}
+		rgb[c] = v
+	}
+	return rgb
+}
+
+func validateDomain(lo, hi [3]float64) error {
+	for c := range lo {
+		if !(hi[c] > lo[c]) {
+			return fmt.Errorf("domain max must be greater than min; got min %v, max %v", lo, hi)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package lut
+
+import (
+	"math"
+	"testing"
+)
+
+func nearlyEqual(a, b [3]float64, tolerance float64) bool {
+	for c := range a {
+		if math.Abs(a[c]-b[c]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCurve(t *testing.T) {
+	c := Curve{In: []float64{0, 0.5, 2}, Out: []float64{0, 1, 4}}
+	for _, tc := range []struct{ in, expect float64 }{
+		{-1, 0},
+		{0, 0},
+		{0.25, 0.5},
+		{0.5, 1},
+		{1.25, 2.5},
+		{2, 4},
+		{3, 4},
+	} {
+		if actual := c.Eval(tc.in); math.Abs(actual-tc.expect) > 1e-12 {
+			t.Errorf("expected Eval(%v) = %v, got %v", tc.in, tc.expect, actual)
+		}
+	}
+	if err := (Curve{In: []float64{0, 0}, Out: []float64{0, 1}}).validate(); err == nil {
+		t.Error("expected an error for inputs that do not increase")
+	}
+}
+
+func TestLUT1D(t *testing.T) {
+	l := NewLUT1D(5)
+	for i := range l.Values {
+		v := float64(i) / 4
+		l.Values[i] = [3]float64{v * v, v, 1 - v}
+	}
+	l.DomainMin = [3]float64{-1, -1, -1}
+	l.DomainMax = [3]float64{1, 1, 1}
+	if err := l.Validate(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expect := [3]float64{0.25, 0.5, 0.5}
+	if actual := l.Eval([3]float64{0, 0, 0}); !nearlyEqual(actual, expect, 1e-12) {
+		t.Errorf("expected %v, got %v", expect, actual)
+	}
+	// Input outside the domain is clamped
+	expect = [3]float64{1, 1, 0}
+	if actual := l.Eval([3]float64{2, 2, 2}); !nearlyEqual(actual, expect, 1e-12) {
+		t.Errorf("expected %v, got %v", expect, actual)
+	}
+
+	resampled := l.Resample(9)
+	if resampled.Size() != 9 || resampled.DomainMin != l.DomainMin || resampled.DomainMax != l.DomainMax {
+		t.Errorf("expected resampling to keep the domain, got %v to %v", resampled.DomainMin, resampled.DomainMax)
+	}
+	lut3D := l.ToLUT3D(5)
+	if lut3D.DomainMin != l.DomainMin || lut3D.DomainMax != l.DomainMax {
+		t.Errorf("expected the 3D LUT to keep the domain, got %v to %v", lut3D.DomainMin, lut3D.DomainMax)
+	}
+	for _, in := range [][3]float64{{-1, 0, 1}, {-0.5, 0.5, 0}, {1, 1, -1}} {
+		if actual, expect := lut3D.Eval(in), l.Eval(in); !nearlyEqual(actual, expect, 1e-12) {
+			t.Errorf("%v: expected %v from the 3D LUT, got %v", in, expect, actual)
+		}
+	}
+
+	l.Values = l.Values[:1]
+	if err := l.Validate(); err == nil {
+		t.Error("expected an error for a 1D LUT with 1 entry")
+	}
+}
+
+func TestLUT3D(t *testing.T) {
+	l := NewLUT3D(3)
+	if err := l.Validate(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if actual := l.Values[l.Index(2, 1, 0)]; actual != [3]float64{1, 0.5, 0} {
+		t.Errorf("expected an identity lattice point, got %v", actual)
+	}
+	in := [3]float64{0.1, 0.7, 0.35}
+	if actual := l.Eval(in); !nearlyEqual(actual, in, 1e-12) {
+		t.Errorf("expected an identity, got %v", actual)
+	}
+
+	l.Shaper = &Shaper{
+		{In: []float64{0, 4}, Out: []float64{0, 1}},
+		{In: []float64{0, 4}, Out: []float64{0, 1}},
+		{In: []float64{0, 4}, Out: []float64{0, 1}},
+	}
+	expect := [3]float64{0.25, 0.5, 1}
+	if actual := l.Eval([3]float64{1, 2, 5}); !nearlyEqual(actual, expect, 1e-12) {
+		t.Errorf("expected the shaper to be applied, got %v", actual)
+	}
+	resampled := l.Resample(5)
+	if resampled.Shaper != nil || resampled.DomainMax != [3]float64{4, 4, 4} {
+		t.Errorf("expected resampling over the shaper input range, got %v to %v",
+			resampled.DomainMin, resampled.DomainMax)
+	}
+	if actual := resampled.Eval([3]float64{1, 2, 5}); !nearlyEqual(actual, expect, 1e-12) {
+		t.Errorf("expected %v from the resampled LUT, got %v", expect, actual)
+	}
+
+	l.Values = l.Values[1:]
+	if err := l.Validate(); err == nil {
+		t.Error("expected an error for a 3D LUT missing entries")
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	m := NewMatrix()
+	m.M[0], m.M[5], m.M[10] = 2, 0.5, 1
+	m.Offset = [4]float64{0.1, 0, -0.1, 0}
+	if !m.IsDiagonal() {
+		t.Error("expected a diagonal matrix")
+	}
+
+	in := [3]float64{0.2, 0.4, 0.9}
+	expect := [3]float64{0.5, 0.2, 0.8}
+	if actual := m.Eval(in); !nearlyEqual(actual, expect, 1e-12) {
+		t.Errorf("expected %v, got %v", expect, actual)
+	}
+
+	// Each channel of a diagonal matrix is sampled separately
+	lut1D, err := m.ToLUT1D(8)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if lut1D.Size() != 8 || !nearlyEqual(lut1D.Values[7], m.Eval([3]float64{1, 1, 1}), 1e-12) {
+		t.Errorf("expected 8 entries ending at %v, got %v", m.Eval([3]float64{1, 1, 1}), lut1D.Values)
+	}
+	if actual := lut1D.Eval(in); !nearlyEqual(actual, expect, 1e-12) {
+		t.Errorf("expected %v from the 1D LUT, got %v", expect, actual)
+	}
+	lut3D := m.ToLUT3D(4)
+	lattice := [3]float64{1.0 / 3, 2.0 / 3, 1}
+	if actual := lut3D.Values[lut3D.Index(1, 2, 3)]; !nearlyEqual(actual, m.Eval(lattice), 1e-12) {
+		t.Errorf("expected %v at lattice point (1, 2, 3), got %v", m.Eval(lattice), actual)
+	}
+	if actual := lut3D.Eval(in); !nearlyEqual(actual, expect, 1e-12) {
+		t.Errorf("expected %v from the 3D LUT, got %v", expect, actual)
+	}
+
+	m.M[1] = 0.25
+	if m.IsDiagonal() {
+		t.Error("expected a matrix with crosstalk")
+	}
+	if _, err = m.ToLUT1D(8); err == nil {
+		t.Error("expected an error converting a matrix with crosstalk to a 1D LUT")
+	}
+	expect = m.Eval(in)
+	if actual := m.ToLUT3D(4).Eval(in); !nearlyEqual(actual, expect, 1e-12) {
+		t.Errorf("expected %v from the 3D LUT, got %v", expect, actual)
+	}
+}
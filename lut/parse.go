@@ -0,0 +1,130 @@
+package lut
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// lineReader reads the whitespace separated fields of each
+// line of a LUT file, tracking the line number for errors
+type lineReader struct {
+	scanner *bufio.Scanner
+	format  string
+	line    int
+
+	// comment, if set, starts a line that is skipped
+	comment string
+}
+
+func newLineReader(r io.Reader, format string) *lineReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &lineReader{scanner: s, format: format}
+}
+
+// next returns the fields of the next line that is not empty or
+// a comment, or io.EOF at the end of the file
+func (r *lineReader) next() ([]string, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" || (r.comment != "" && strings.HasPrefix(line, r.comment)) {
+			continue
+		}
+		return strings.Fields(line), nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", r.format, err)
+	}
+	return nil, io.EOF
+}
+
+// nextFloats returns the next line, which must hold n numbers
+func (r *lineReader) nextFloats(n int) ([]float64, error) {
+	fields, err := r.next()
+	if err == io.EOF {
+		return nil, r.errorf("unexpected end of file")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != n {
+		return nil, r.errorf("expected %d values, got %d", n, len(fields))
+	}
+	return r.floats(fields)
+}
+
+func (r *lineReader) floats(fields []string) ([]float64, error) {
+	values := make([]float64, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, r.errorf("invalid number %q", f)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (r *lineReader) ints(fields []string) ([]int, error) {
+	values := make([]int, len(fields))
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, r.errorf("invalid integer %q", f)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (r *lineReader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s: line %d: %s", r.format, r.line, fmt.Sprintf(format, args...))
+}
+
+// lutWriter writes the lines of a LUT file, keeping the first error
+type lutWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func newLUTWriter(w io.Writer) *lutWriter {
+	return &lutWriter{w: bufio.NewWriter(w)}
+}
+
+func (w *lutWriter) printf(format string, args ...interface{}) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.w, format, args...)
+	}
+}
+
+// floats writes values separated by spaces, followed by a newline
+func (w *lutWriter) floats(values ...float64) {
+	for i, v := range values {
+		if i > 0 {
+			w.printf(" ")
+		}
+		w.printf("%s", formatFloat(v))
+	}
+	w.printf("\n")
+}
+
+func (w *lutWriter) flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Flush()
+}
+
+// formatFloat formats v with the fewest digits that read back exactly,
+// always including a decimal point
+func formatFloat(v float64) string {
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eEnN") {
+		s += ".0"
+	}
+	return s
+}
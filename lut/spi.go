@@ -0,0 +1,285 @@
+package lut
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ReadSpi1D reads a Sony Pictures Imageworks .spi1d file
+func ReadSpi1D(r io.Reader) (*LUT1D, error) {
+	lr := newLineReader(r, "spi1d")
+
+	var (
+		lo, hi     = 0.0, 1.0
+		length     = -1
+		components = 1
+	)
+
+	// Header
+	for {
+		fields, err := lr.next()
+		if err == io.EOF {
+			return nil, lr.errorf("missing LUT data")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if fields[0] == "{" {
+			break
+		}
+		switch fields[0] {
+		case "Version":
+			if len(fields) != 2 || fields[1] != "1" {
+				return nil, lr.errorf("unsupported version %v", fields[1:])
+			}
+		case "From":
+			values, err := lr.floats(fields[1:])
+			if err != nil {
+				return nil, err
+			}
+			if len(values) != 2 {
+				return nil, lr.errorf("expected From to have 2 values, got %d", len(values))
+			}
+			lo, hi = values[0], values[1]
+		case "Length":
+			if len(fields) != 2 {
+				return nil, lr.errorf("expected Length to have 1 value")
+			}
+			if length, err = strconv.Atoi(fields[1]); err != nil || length < 2 {
+				return nil, lr.errorf("invalid Length %q", fields[1])
+			}
+		case "Components":
+			if len(fields) != 2 {
+				return nil, lr.errorf("expected Components to have 1 value")
+			}
+			if components, err = strconv.Atoi(fields[1]); err != nil || (components != 1 && components != 3) {
+				return nil, lr.errorf("unsupported Components %q; must be 1 or 3", fields[1])
+			}
+		default:
+			return nil, lr.errorf("unknown header %q", fields[0])
+		}
+	}
+	if length < 0 {
+		return nil, lr.errorf("missing Length")
+	}
+
+	l := &LUT1D{
+		DomainMin: [3]float64{lo, lo, lo},
+		DomainMax: [3]float64{hi, hi, hi},
+		Values:    make([][3]float64, 0, length),
+	}
+	for {
+		fields, err := lr.next()
+		if err == io.EOF {
+			return nil, lr.errorf("missing closing }")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if fields[0] == "}" {
+			break
+		}
+		if len(fields) != components {
+			return nil, lr.errorf("expected %d values, got %d", components, len(fields))
+		}
+		values, err := lr.floats(fields)
+		if err != nil {
+			return nil, err
+		}
+		if components == 1 {
+			l.Values = append(l.Values, [3]float64{values[0], values[0], values[0]})
+		} else {
+			l.Values = append(l.Values, [3]float64{values[0], values[1], values[2]})
+		}
+	}
+	if len(l.Values) != length {
+		return nil, lr.errorf("expected %d entries, got %d", length, len(l.Values))
+	}
+	if err := l.Validate(); err != nil {
+		return nil, fmt.Errorf("spi1d: %w", err)
+	}
+	return l, nil
+}
+
+// WriteSpi1D writes a LUT1D as a .spi1d file. Entries are written with
+// a single component when the red, green and blue values are equal.
+// The format does not support a Shaper, or a domain that is
+// different on each channel.
+func WriteSpi1D(w io.Writer, l *LUT1D) error {
+	if err := l.Validate(); err != nil {
+		return fmt.Errorf("spi1d: %w", err)
+	}
+	if l.Shaper != nil {
+		return errors.New("spi1d: format does not support a shaper; use Resample")
+	}
+	if !uniformDomain(l.DomainMin, l.DomainMax) {
+		return errors.New("spi1d: format does not support a different domain on each channel")
+	}
+
+	components := 1
+	for _, v := range l.Values {
+		if v[0] != v[1] || v[0] != v[2] {
+			components = 3
+			break
+		}
+	}
+
+	lw := newLUTWriter(w)
+	lw.printf("Version 1\n")
+	lw.printf("From %s %s\n", formatFloat(l.DomainMin[0]), formatFloat(l.DomainMax[0]))
+	lw.printf("Length %d\n", len(l.Values))
+	lw.printf("Components %d\n", components)
+	lw.printf("{\n")
+	for _, v := range l.Values {
+		lw.printf("    ")
+		lw.floats(v[:components]...)
+	}
+	lw.printf("}\n")
+	return lw.flush()
+}
+
+// ReadSpi3D reads a Sony Pictures Imageworks .spi3d file
+func ReadSpi3D(r io.Reader) (*LUT3D, error) {
+	lr := newLineReader(r, "spi3d")
+
+	fields, err := lr.next()
+	if err != nil || fields[0] != "SPILUT" {
+		return nil, lr.errorf("missing SPILUT header")
+	}
+	if fields, err = lr.next(); err != nil || len(fields) != 2 || fields[0] != "3" || fields[1] != "3" {
+		return nil, lr.errorf("only 3D LUTs with 3 components are supported")
+	}
+	if fields, err = lr.next(); err != nil || len(fields) != 3 {
+		return nil, lr.errorf("missing LUT size")
+	}
+	sizes, err := lr.ints(fields)
+	if err != nil {
+		return nil, err
+	}
+	size := sizes[0]
+	if size < 2 || sizes[1] != size || sizes[2] != size {
+		return nil, lr.errorf("unsupported LUT size %v; must be equal on each axis", sizes)
+	}
+
+	l := NewLUT3D(size)
+	seen := make([]bool, len(l.Values))
+	for {
+		fields, err := lr.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) != 6 {
+			return nil, lr.errorf("expected 3 indices and 3 values, got %d fields", len(fields))
+		}
+		idx, err := lr.ints(fields[:3])
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range idx {
+			if i < 0 || i >= size {
+				return nil, lr.errorf("index %v out of range for size %d", idx, size)
+			}
+		}
+		values, err := lr.floats(fields[3:])
+		if err != nil {
+			return nil, err
+		}
+		i := l.Index(idx[0], idx[1], idx[2])
+		l.Values[i] = [3]float64{values[0], values[1], values[2]}
+		seen[i] = true
+	}
+	for i, ok := range seen {
+		if !ok {
+			return nil, fmt.Errorf("spi3d: missing entry %d %d %d", i%size, i/size%size, i/(size*size))
+		}
+	}
+	return l, nil
+}
+
+// WriteSpi3D writes a LUT3D as a .spi3d file. The format only supports
+// the domain [0, 1], without a Shaper.
+func WriteSpi3D(w io.Writer, l *LUT3D) error {
+	if err := l.Validate(); err != nil {
+		return fmt.Errorf("spi3d: %w", err)
+	}
+	if l.Shaper != nil || l.DomainMin != [3]float64{} || l.DomainMax != [3]float64{1, 1, 1} {
+		return errors.New("spi3d: format only supports the domain [0, 1] without a shaper; use Resample")
+	}
+
+	lw := newLUTWriter(w)
+	lw.printf("SPILUT 1.0\n3 3\n%d %d %d\n", l.Size, l.Size, l.Size)
+	for r := 0; r < l.Size; r++ {
+		for g := 0; g < l.Size; g++ {
+			for b := 0; b < l.Size; b++ {
+				lw.printf("%d %d %d ", r, g, b)
+				v := l.Values[l.Index(r, g, b)]
+				lw.floats(v[:]...)
+			}
+		}
+	}
+	return lw.flush()
+}
+
+// spimtxOffsetScale is the scale of the offsets in a .spimtx
+// file, which are expressed in 16-bit code values
+const spimtxOffsetScale = 65535
+
+// ReadSpiMtx reads a Sony Pictures Imageworks .spimtx file
+func ReadSpiMtx(r io.Reader) (*Matrix, error) {
+	lr := newLineReader(r, "spimtx")
+
+	var values []float64
+	for {
+		fields, err := lr.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		v, err := lr.floats(fields)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v...)
+	}
+	if len(values) != 12 {
+		return nil, fmt.Errorf("spimtx: expected 12 values, got %d", len(values))
+	}
+
+	m := NewMatrix()
+	for row := 0; row < 3; row++ {
+		copy(m.M[row*4:row*4+3], values[row*4:row*4+3])
+		m.Offset[row] = values[row*4+3] / spimtxOffsetScale
+	}
+	return m, nil
+}
+
+// WriteSpiMtx writes a Matrix as a .spimtx file. The format
+// does not support an alpha row, column or offset.
+func WriteSpiMtx(w io.Writer, m *Matrix) error {
+	for i := 0; i < 3; i++ {
+		if m.M[i*4+3] != 0 || m.M[12+i] != 0 {
+			return errors.New("spimtx: format does not support an alpha row or column")
+		}
+	}
+	if m.M[15] != 1 || m.Offset[3] != 0 {
+		return errors.New("spimtx: format does not support an alpha row or offset")
+	}
+
+	lw := newLUTWriter(w)
+	for row := 0; row < 3; row++ {
+		lw.floats(m.M[row*4], m.M[row*4+1], m.M[row*4+2], m.Offset[row]*spimtxOffsetScale)
+	}
+	return lw.flush()
+}
+
+// uniformDomain reports whether the domain is the same on each channel
+func uniformDomain(lo, hi [3]float64) bool {
+	return lo[0] == lo[1] && lo[0] == lo[2] && hi[0] == hi[1] && hi[0] == hi[2]
+}
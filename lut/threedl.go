@@ -0,0 +1,235 @@
+package lut
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Default3DLInputBitDepth is the bit depth of the input mesh written by
+// Write3DL, and Default3DLOutputBitDepth the bit depth of the values
+// written by WriteFile
+const (
+	Default3DLInputBitDepth  = 10
+	Default3DLOutputBitDepth = 12
+)
+
+// Read3DL reads an Autodesk .3dl file, as written by Lustre,
+// Flame and Truelight. The bit depth of the integer values is read
+// from a "Mesh" line such as "Mesh 4 12", or from a "LUT" line such
+// as "LUT12" if every value fits in it, and is otherwise inferred from
+// the largest value. A non-uniform input mesh is read as the Shaper of
+// the LUT3D.
+//
+// Without a header, a LUT whose values are all small is read at too
+// low a bit depth. For example, a LUT written at 12 bits with values
+// no greater than 0.25 is read as 10 bits, with values 4 times too
+// large. Use Read3DLBitDepth when the bit depth is known.
+func Read3DL(r io.Reader) (*LUT3D, error) {
+	return read3DL(r, 0)
+}
+
+// Read3DLBitDepth is like Read3DL, but reads values of the given
+// bit depth, between 8 and 16, ignoring any bit depth header
+func Read3DLBitDepth(r io.Reader, bits int) (*LUT3D, error) {
+	if bits < 8 || bits > 16 {
+		return nil, fmt.Errorf("3dl: unsupported bit depth %d", bits)
+	}
+	return read3DL(r, bits)
+}
+
+// read3DL reads a .3dl file with values of the given bit
+// depth, or of the header or inferred bit depth if it is 0
+func read3DL(r io.Reader, bits int) (*LUT3D, error) {
+	lr := newLineReader(r, "3dl")
+	lr.comment = "#"
+
+	var (
+		mesh     []int
+		values   [][3]int
+		maxOut   int
+		meshBits int
+		lutBits  int
+	)
+	for {
+		fields, err := lr.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Keyword lines such as "3DMESH", "Mesh 4 12" or "LUT8" may
+		// give the bit depth of the values, and are otherwise skipped
+		if _, numErr := strconv.Atoi(fields[0]); numErr != nil {
+			switch {
+			case strings.EqualFold(fields[0], "Mesh") && len(fields) == 3:
+				meshBits, err = parseBitDepth3DL(fields[2])
+			case len(fields) == 1 && len(fields[0]) > 3 && strings.EqualFold(fields[0][:3], "LUT"):
+				lutBits, err = parseBitDepth3DL(fields[0][3:])
+			}
+			if err != nil {
+				return nil, lr.errorf("%s", err)
+			}
+			continue
+		}
+		ints, err := lr.ints(fields)
+		if err != nil {
+			return nil, err
+		}
+		if mesh == nil {
+			mesh = ints
+			continue
+		}
+		if len(ints) != 3 {
+			return nil, lr.errorf("expected 3 values, got %d", len(ints))
+		}
+		for _, v := range ints {
+			if v > maxOut {
+				maxOut = v
+			}
+		}
+		values = append(values, [3]int{ints[0], ints[1], ints[2]})
+	}
+
+	size := len(mesh)
+	if size < 2 {
+		return nil, errors.New("3dl: missing input mesh")
+	}
+	if len(values) != size*size*size {
+		return nil, fmt.Errorf("3dl: input mesh of size %d needs %d entries, got %d",
+			size, size*size*size, len(values))
+	}
+
+	// Lustre writes a "LUT8" line after values of any bit depth, so
+	// a "LUT" line is only used if it holds every value
+	if bits == 0 {
+		bits = meshBits
+	}
+	if bits == 0 && lutBits != 0 && maxOut <= bitDepthMax(lutBits) {
+		bits = lutBits
+	}
+	if bits == 0 {
+		bits = likelyBitDepth(maxOut)
+	} else if maxOut > bitDepthMax(bits) {
+		return nil, fmt.Errorf("3dl: value %d is out of range for %d bits", maxOut, bits)
+	}
+	inMax := float64(bitDepthMax(likelyBitDepth(mesh[size-1])))
+	outMax := float64(bitDepthMax(bits))
+
+	l := NewLUT3D(size)
+	i := 0
+	for r := 0; r < size; r++ {
+		for g := 0; g < size; g++ {
+			for b := 0; b < size; b++ {
+				v := values[i]
+				l.Values[l.Index(r, g, b)] = [3]float64{
+					float64(v[0]) / outMax,
+					float64(v[1]) / outMax,
+					float64(v[2]) / outMax,
+				}
+				i++
+			}
+		}
+	}
+
+	for i, v := range mesh {
+		if d := v - mesh3DL(i, size, int(inMax)); d < -1 || d > 1 {
+			curve := Curve{In: make([]float64, size), Out: make([]float64, size)}
+			for i, v := range mesh {
+				curve.In[i] = float64(v) / inMax
+				curve.Out[i] = float64(i) / float64(size-1)
+			}
+			l.Shaper = &Shaper{curve, curve, curve}
+			break
+		}
+	}
+	if err := l.Validate(); err != nil {
+		return nil, fmt.Errorf("3dl: %w", err)
+	}
+	return l, nil
+}
+
+// Write3DL writes a LUT3D as a .3dl file, with a uniform input mesh of
+// Default3DLInputBitDepth and values of the given output bit depth.
+// Values are clamped to [0, 1]. The format only supports the domain
+// [0, 1], without a Shaper, and does not record the output bit depth,
+// so the file should be read back with Read3DLBitDepth.
+func Write3DL(w io.Writer, l *LUT3D, outBitDepth int) error {
+	if err := l.Validate(); err != nil {
+		return fmt.Errorf("3dl: %w", err)
+	}
+	if l.Shaper != nil || l.DomainMin != [3]float64{} || l.DomainMax != [3]float64{1, 1, 1} {
+		return errors.New("3dl: format only supports the domain [0, 1] without a shaper; use Resample")
+	}
+	if outBitDepth < 8 || outBitDepth > 16 {
+		return fmt.Errorf("3dl: unsupported output bit depth %d", outBitDepth)
+	}
+
+	inMax := bitDepthMax(Default3DLInputBitDepth)
+	outMax := float64(bitDepthMax(outBitDepth))
+	quantize := func(v float64) int {
+		v = v*outMax + 0.5
+		if v < 0 || v != v {
+			return 0
+		} else if v > outMax {
+			return int(outMax)
+		}
+		return int(v)
+	}
+
+	lw := newLUTWriter(w)
+	for i := 0; i < l.Size; i++ {
+		if i > 0 {
+			lw.printf(" ")
+		}
+		lw.printf("%d", mesh3DL(i, l.Size, inMax))
+	}
+	lw.printf("\n")
+	for r := 0; r < l.Size; r++ {
+		for g := 0; g < l.Size; g++ {
+			for b := 0; b < l.Size; b++ {
+				v := l.Values[l.Index(r, g, b)]
+				lw.printf("%d %d %d\n", quantize(v[0]), quantize(v[1]), quantize(v[2]))
+			}
+		}
+	}
+	return lw.flush()
+}
+
+// mesh3DL returns the conventional input mesh value of entry i
+// of size, such as 0 64 128 ... 960 1023 for size 17 at 10 bits
+func mesh3DL(i, size, max int) int {
+	v := i * (max + 1) / (size - 1)
+	if v > max {
+		v = max
+	}
+	return v
+}
+
+// parseBitDepth3DL parses the bit depth given by
+// a "Mesh" or "LUT" line of a .3dl file
+func parseBitDepth3DL(depth string) (int, error) {
+	bits, err := strconv.Atoi(depth)
+	if err != nil || bits < 8 || bits > 16 {
+		return 0, fmt.Errorf("unsupported bit depth %q", depth)
+	}
+	return bits, nil
+}
+
+// likelyBitDepth returns the smallest common bit depth
+// that holds the integer value v
+func likelyBitDepth(v int) int {
+	for _, bits := range []int{8, 10, 12, 14} {
+		if v <= bitDepthMax(bits) {
+			return bits
+		}
+	}
+	return 16
+}
+
+func bitDepthMax(bits int) int {
+	return 1<<uint(bits) - 1
+}
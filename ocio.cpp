@@ -17,6 +17,7 @@ _HandleContext* NEW_HANDLE_CONTEXT(HandleId handle) {
     ctx->handle = handle;
     ctx->last_error = NULL;
     ctx->has_error = false;
+    ctx->last_errno = 0;
     return ctx;
 }
 
@@ -57,6 +58,10 @@ extern "C" {
         return ctx->last_error;
     }
 
+    int getLastErrno(_HandleContext* ctx) {
+        return ctx->last_errno;
+    }
+
     void ClearAllCaches() { 
         BEGIN_CATCH_ERR
         OCIO::ClearAllCaches(); 
@@ -17,12 +17,16 @@ import "C"
 
 import (
 	"errors"
+	"strings"
+	"sync/atomic"
 )
 
 type (
 	LoggingLevelType int
 	EnvironmentMode  int
 	InterpType       int
+	Allocation       int
+	GpuLanguage      int
 )
 
 const (
@@ -47,6 +51,19 @@ const (
 	INTERP_BEST        InterpType = C.INTERP_BEST
 )
 
+const (
+	ALLOCATION_UNKNOWN Allocation = C.ALLOCATION_UNKNOWN
+	ALLOCATION_UNIFORM Allocation = C.ALLOCATION_UNIFORM
+	ALLOCATION_LG2     Allocation = C.ALLOCATION_LG2
+)
+
+const (
+	GPU_LANGUAGE_UNKNOWN  GpuLanguage = C.GPU_LANGUAGE_UNKNOWN
+	GPU_LANGUAGE_CG       GpuLanguage = C.GPU_LANGUAGE_CG       // Nvidia Cg shader
+	GPU_LANGUAGE_GLSL_1_0 GpuLanguage = C.GPU_LANGUAGE_GLSL_1_0 // OpenGL Shading Language
+	GPU_LANGUAGE_GLSL_1_3 GpuLanguage = C.GPU_LANGUAGE_GLSL_1_3 // OpenGL Shading Language
+)
+
 var (
 	ROLE_DEFAULT         = C.GoString(C.ROLE_DEFAULT)
 	ROLE_REFERENCE       = C.GoString(C.ROLE_REFERENCE)
@@ -63,7 +80,11 @@ var (
 Errors
 */
 
-func getLastError(ptr *C._HandleContext, errno ...error) (err error) {
+// getLastError returns any error stored in the _HandleContext by the last
+// call made with it, as an *OCIOError. The op names the API call that
+// failed, such as "Config.Processor", and the handle names the type of
+// OCIO object the call was made on.
+func getLastError(op, handle string, ptr *C._HandleContext, errno ...error) (err error) {
 	// If the function is going to return a nil error,
 	// but an errno error was passed in, return that
 	// as the default value instead.
@@ -74,6 +95,9 @@ func getLastError(ptr *C._HandleContext, errno ...error) (err error) {
 		if err == nil && len(errno) > 0 {
 			err = errno[0]
 		}
+		if err != nil {
+			err = &OCIOError{Op: op, Handle: handle, Err: err}
+		}
 	}()
 
 	if ptr == nil {
@@ -92,19 +116,97 @@ func getLastError(ptr *C._HandleContext, errno ...error) (err error) {
 	if e == "" {
 		return
 	}
+	if C.getLastErrno(ptr) == C.ERR_MISSING_FILE {
+		err = ErrMissingFile{e}
+		return
+	}
 	err = errors.New(e)
 	return
 }
 
+// takeHandleContext returns the handle and any error stored in a
+// temporary _HandleContext returned by the C API, and frees it.
+func takeHandleContext(op, handle string, ctx *C._HandleContext, errno ...error) (C.HandleId, error) {
+	if ctx == nil {
+		if len(errno) > 0 && errno[0] != nil {
+			return 0, &OCIOError{Op: op, Handle: handle, Err: errno[0]}
+		}
+		return 0, nil
+	}
+	err := getLastError(op, handle, ctx, errno...)
+	id := ctx.handle
+	ctx.handle = 0
+	C.freeHandleContext(ctx)
+	return id, err
+}
+
+// OCIOError is returned when a call into the OpenColorIO library fails.
+//
+// Use errors.As to inspect the failed operation, and
+// errors.Is(err, ErrMissingFile{}) to check whether OCIO
+// could not find a file that was expected to exist.
+type OCIOError struct {
+	// Op is the API call that failed, such as "Config.ProcessorFromNames".
+	// A wrapper such as Config.Processor reports the call it makes.
+	Op string
+	// Handle is the type of OCIO object the call was made on,
+	// such as "Config" or "Processor"
+	Handle string
+	// Err is the underlying error. It is an ErrMissingFile when the
+	// failure was an OCIO ExceptionMissingFile.
+	Err error
+}
+
+func (e *OCIOError) Error() string {
+	if e.Op == "" {
+		return e.Err.Error()
+	}
+	return e.Op + ": " + e.Err.Error()
+}
+
+func (e *OCIOError) Unwrap() error { return e.Err }
+
 // An exception class for errors detected at runtime,
 // thrown when OCIO cannot find a file that is expected to exist.
 // This is provided as a custom type to distinguish cases where
 // one wants to continue looking for missing files, but wants to
 // properly fail for other error conditions.
+//
+// It is returned wrapped in an *OCIOError, and can be
+// checked with errors.Is(err, ErrMissingFile{}).
 type ErrMissingFile struct{ what string }
 
 func (e ErrMissingFile) Error() string { return e.what }
 
+// Is reports whether target is also an ErrMissingFile,
+// regardless of the message
+func (e ErrMissingFile) Is(target error) bool {
+	_, ok := target.(ErrMissingFile)
+	return ok
+}
+
+// FileLocationError is returned by Context.ResolveFileLocation when
+// a file cannot be found, listing the candidate paths built from the
+// search path. It wraps the *OCIOError returned by OCIO.
+type FileLocationError struct {
+	// Filename is the file reference that was resolved
+	Filename string
+	// Candidates are the Filename joined to each search path, in
+	// search order. They are computed by this package, not reported
+	// by OCIO, so may differ from the paths OCIO tried.
+	Candidates []string
+	Err        error
+}
+
+func (e *FileLocationError) Error() string {
+	if len(e.Candidates) == 0 {
+		return e.Err.Error() + " (no search path candidates)"
+	}
+	return e.Err.Error() + " (search path candidates: " + strings.Join(e.Candidates, ", ") + ")"
+}
+
+func (e *FileLocationError) Unwrap() error { return e.Err }
+
 /*
 Global
 */
@@ -119,6 +221,7 @@ to re-read luts without restarting.
 */
 func ClearAllCaches() {
 	C.ClearAllCaches()
+	atomic.AddUint64(&cacheGeneration, 1)
 }
 
 // Get the version number for the library, as a dot-delimited string (e.g., “1.0.0”).
@@ -23,8 +23,9 @@ extern const char* ROLE_MATTE_PAINT;
 
 // Enum
 enum ErrnNo {
-    ERR_GENERAL     = -1,
-    ERR_BAD_ARGS    = -2,
+    ERR_GENERAL         = -1,
+    ERR_BAD_ARGS        = -2,
+    ERR_MISSING_FILE    = -3,
 };
 
 typedef enum LoggingLevel {
@@ -67,12 +68,46 @@ typedef enum TransformDirection {
     TRANSFORM_DIR_INVERSE
 } TransformDirection;
 
+typedef enum ColorSpaceDirection {
+    COLORSPACE_DIR_UNKNOWN = 0,
+    COLORSPACE_DIR_TO_REFERENCE,
+    COLORSPACE_DIR_FROM_REFERENCE
+} ColorSpaceDirection;
+
+typedef enum Allocation {
+    ALLOCATION_UNKNOWN = 0,
+    ALLOCATION_UNIFORM,
+    ALLOCATION_LG2
+} Allocation;
+
+typedef enum GpuLanguage {
+    GPU_LANGUAGE_UNKNOWN = 0,
+    GPU_LANGUAGE_CG,        //! Nvidia Cg shader
+    GPU_LANGUAGE_GLSL_1_0,  //! OpenGL Shading Language
+    GPU_LANGUAGE_GLSL_1_3   //! OpenGL Shading Language
+} GpuLanguage;
+
+typedef enum TransformType {
+    TRANSFORM_TYPE_UNKNOWN = 0,
+    TRANSFORM_TYPE_ALLOCATION,
+    TRANSFORM_TYPE_CDL,
+    TRANSFORM_TYPE_COLORSPACE,
+    TRANSFORM_TYPE_DISPLAY,
+    TRANSFORM_TYPE_EXPONENT,
+    TRANSFORM_TYPE_FILE,
+    TRANSFORM_TYPE_GROUP,
+    TRANSFORM_TYPE_LOG,
+    TRANSFORM_TYPE_LOOK,
+    TRANSFORM_TYPE_MATRIX
+} TransformType;
+
 typedef uint64_t HandleId;
 
 typedef struct _HandleContext {
     HandleId handle;
     const char* last_error;
     bool has_error;
+    int last_errno;
 } _HandleContext;
 
 // typedef void Config;
@@ -83,12 +118,26 @@ typedef _HandleContext* ProcessorId;
 typedef HandleId ProcessorMetadataId;
 typedef void ImageDesc;
 typedef void PackedImageDesc;
+typedef void PlanarImageDesc;
+typedef HandleId LookId;
+typedef _HandleContext* BakerId;
+typedef HandleId GpuShaderDescId;
 typedef HandleId TransformId;
 typedef HandleId DisplayTransformId;
+typedef HandleId LookTransformId;
+typedef HandleId AllocationTransformId;
+typedef HandleId CDLTransformId;
+typedef HandleId ColorSpaceTransformId;
+typedef HandleId ExponentTransformId;
+typedef HandleId FileTransformId;
+typedef HandleId GroupTransformId;
+typedef HandleId LogTransformId;
+typedef HandleId MatrixTransformId;
 
 void freeHandleContext(_HandleContext* ctx);
 bool hasLastError(_HandleContext* ctx);
 const char* getLastError(_HandleContext* ctx);
+int getLastErrno(_HandleContext* ctx);
 
 // Global
 void ClearAllCaches();
@@ -106,6 +155,7 @@ Config* Config_CreateFromEnv();
 Config* Config_CreateFromFile(const char* filename);
 Config* Config_CreateFromData(const char* data);
 Config* Config_createEditableCopy(Config *p);
+Config* Config_share(Config *p);
 void Config_sanityCheck(Config *p);
 
 char* Config_serialize(Config *p);
@@ -116,6 +166,11 @@ const char* Config_getDescription(Config *p);
 // Config Resources
 ContextId Config_getCurrentContext(Config *p);
 const char* Config_getSearchPath(Config *p);
+int Config_getNumEnvironmentVars(Config *p);
+const char* Config_getEnvironmentVarNameByIndex(Config *p, int index);
+const char* Config_getEnvironmentVarDefault(Config *p, const char* name);
+void Config_addEnvironmentVar(Config *p, const char* name, const char* defaultValue);
+void Config_clearEnvironmentVars(Config *p);
 const char* Config_getWorkingDir(Config *p);
 
 // Config Processors
@@ -161,6 +216,17 @@ const char* Config_getActiveDisplays(Config *p);
 void Config_setActiveViews(Config *p, const char* views);
 const char* Config_getActiveViews(Config *p);
 
+// Config Luma
+void Config_getDefaultLumaCoefs(Config *p, float* rgb);
+void Config_setDefaultLumaCoefs(Config *p, const float* rgb);
+
+// Config Looks
+LookId Config_getLook(Config *p, const char* name);
+int Config_getNumLooks(Config *p);
+const char* Config_getLookNameByIndex(Config *p, int index);
+void Config_addLook(Config *p, LookId look);
+void Config_clearLooks(Config *p);
+
 // ColorSpaces
 ColorSpaceId ColorSpace_Create();
 ColorSpaceId ColorSpace_createEditableCopy(ColorSpaceId p);
@@ -174,6 +240,30 @@ const char* ColorSpace_getDescription(ColorSpaceId p);
 void ColorSpace_setDescription(ColorSpaceId p, const char* description);
 BitDepth ColorSpace_getBitDepth(ColorSpaceId p);
 void ColorSpace_setBitDepth(ColorSpaceId p, BitDepth bitDepth);
+bool ColorSpace_isData(ColorSpaceId p);
+void ColorSpace_setIsData(ColorSpaceId p, bool isData);
+Allocation ColorSpace_getAllocation(ColorSpaceId p);
+void ColorSpace_setAllocation(ColorSpaceId p, Allocation allocation);
+int ColorSpace_getAllocationNumVars(ColorSpaceId p);
+void ColorSpace_getAllocationVars(ColorSpaceId p, float* vars);
+void ColorSpace_setAllocationVars(ColorSpaceId p, int numvars, const float* vars);
+TransformId ColorSpace_getTransform(ColorSpaceId p, ColorSpaceDirection dir);
+void ColorSpace_setTransform(ColorSpaceId p, TransformId tx, ColorSpaceDirection dir);
+
+// Look
+void deleteLook(LookId p);
+LookId Look_Create();
+LookId Look_createEditableCopy(LookId p);
+const char* Look_getName(LookId p);
+void Look_setName(LookId p, const char* name);
+const char* Look_getProcessSpace(LookId p);
+void Look_setProcessSpace(LookId p, const char* processSpace);
+TransformId Look_getTransform(LookId p);
+void Look_setTransform(LookId p, TransformId tx);
+TransformId Look_getInverseTransform(LookId p);
+void Look_setInverseTransform(LookId p, TransformId tx);
+const char* Look_getDescription(LookId p);
+void Look_setDescription(LookId p, const char* description);
 
 // Context
 void deleteContext(ContextId p);
@@ -195,14 +285,34 @@ const char* Context_resolveFileLocation(ContextId p, const char* filename);
 // Processor
 void deleteProcessor(ProcessorId p);
 ProcessorId Processor_Create();
+ProcessorId Processor_share(ProcessorId p);
 bool Processor_isNoOp(ProcessorId p);
 bool Processor_hasChannelCrosstalk(ProcessorId p);
 ProcessorMetadataId Processor_getMetadata(ProcessorId p);
 
 // Processor CPU
 void Processor_apply(ProcessorId p, ImageDesc *i);
+void Processor_applyRGB(ProcessorId p, float* pixels, long numPixels);
+void Processor_applyRGBA(ProcessorId p, float* pixels, long numPixels);
 const char* Processor_getCpuCacheID(ProcessorId p);
 
+// Processor GPU
+const char* Processor_getGpuShaderText(ProcessorId p, GpuShaderDescId shaderDesc);
+const char* Processor_getGpuShaderTextCacheID(ProcessorId p, GpuShaderDescId shaderDesc);
+void Processor_getGpuLut3D(ProcessorId p, float* lut3d, GpuShaderDescId shaderDesc);
+const char* Processor_getGpuLut3DCacheID(ProcessorId p, GpuShaderDescId shaderDesc);
+
+// GpuShaderDesc
+void deleteGpuShaderDesc(GpuShaderDescId p);
+GpuShaderDescId GpuShaderDesc_Create();
+GpuLanguage GpuShaderDesc_getLanguage(GpuShaderDescId p);
+void GpuShaderDesc_setLanguage(GpuShaderDescId p, GpuLanguage lang);
+const char* GpuShaderDesc_getFunctionName(GpuShaderDescId p);
+void GpuShaderDesc_setFunctionName(GpuShaderDescId p, const char* name);
+int GpuShaderDesc_getLut3DEdgeLen(GpuShaderDescId p);
+void GpuShaderDesc_setLut3DEdgeLen(GpuShaderDescId p, int len);
+const char* GpuShaderDesc_getCacheID(GpuShaderDescId p);
+
 void deleteProcessorMetadata(ProcessorMetadataId p);
 ProcessorMetadataId ProcessorMetadata_Create();
 int ProcessorMetadata_getNumFiles(ProcessorMetadataId p);
@@ -213,12 +323,64 @@ void ProcessorMetadata_addFile(ProcessorMetadataId p, const char* fname);
 void ProcessorMetadata_addLook(ProcessorMetadataId p, const char* look);
 
 // ImageDesc
+
+// AUTO_STRIDE may be passed as a stride to PackedImageDesc_CreateStrided,
+// which maps it to OCIO::AutoStride
+#define AUTO_STRIDE -1
+
 void deletePackedImageDesc(PackedImageDesc* p);
 PackedImageDesc* PackedImageDesc_Create(float* data, long width, long height, long numChannels);
+PackedImageDesc* PackedImageDesc_CreateStrided(float* data, long width, long height, long numChannels,
+                                             long chanStrideBytes, long xStrideBytes, long yStrideBytes);
 float* PackedImageDesc_getData(PackedImageDesc *p);
 long PackedImageDesc_getWidth(PackedImageDesc *p);
 long PackedImageDesc_getHeight(PackedImageDesc *p);
 long PackedImageDesc_getNumChannels(PackedImageDesc *p);
+long PackedImageDesc_getChanStrideBytes(PackedImageDesc *p);
+long PackedImageDesc_getXStrideBytes(PackedImageDesc *p);
+long PackedImageDesc_getYStrideBytes(PackedImageDesc *p);
+void deletePlanarImageDesc(PlanarImageDesc* p);
+PlanarImageDesc* PlanarImageDesc_Create(float* rData, float* gData, float* bData, float* aData, long width, long height);
+long PlanarImageDesc_getWidth(PlanarImageDesc *p);
+long PlanarImageDesc_getHeight(PlanarImageDesc *p);
+
+// Baker
+void deleteBaker(BakerId p);
+BakerId Baker_Create();
+BakerId Baker_createEditableCopy(BakerId p);
+Config* Baker_getConfig(BakerId p);
+void Baker_setConfig(BakerId p, Config* config);
+const char* Baker_getFormat(BakerId p);
+void Baker_setFormat(BakerId p, const char* formatName);
+const char* Baker_getType(BakerId p);
+void Baker_setType(BakerId p, const char* type);
+const char* Baker_getMetadata(BakerId p);
+void Baker_setMetadata(BakerId p, const char* metadata);
+const char* Baker_getInputSpace(BakerId p);
+void Baker_setInputSpace(BakerId p, const char* inputSpace);
+const char* Baker_getShaperSpace(BakerId p);
+void Baker_setShaperSpace(BakerId p, const char* shaperSpace);
+const char* Baker_getLooks(BakerId p);
+void Baker_setLooks(BakerId p, const char* looks);
+const char* Baker_getTargetSpace(BakerId p);
+void Baker_setTargetSpace(BakerId p, const char* targetSpace);
+int Baker_getShaperSize(BakerId p);
+void Baker_setShaperSize(BakerId p, int shapersize);
+int Baker_getCubeSize(BakerId p);
+void Baker_setCubeSize(BakerId p, int cubesize);
+char* Baker_bake(BakerId p);
+int Baker_getNumFormats();
+const char* Baker_getFormatNameByIndex(int index);
+const char* Baker_getFormatExtensionByIndex(int index);
+
+// FileTransform formats
+int FileTransform_getNumFormats();
+const char* FileTransform_getFormatNameByIndex(int index);
+const char* FileTransform_getFormatExtensionByIndex(int index);
+
+// Transform
+void deleteTransform(TransformId p);
+TransformType Transform_getType(TransformId p);
 
 // DisplayTransform
 void deleteDisplayTransform(DisplayTransformId p);
@@ -237,6 +399,122 @@ void DisplayTransform_setLooksOverride(DisplayTransformId p, const char* looks);
 bool DisplayTransform_getLooksOverrideEnabled(DisplayTransformId p);
 void DisplayTransform_setLooksOverrideEnabled(DisplayTransformId p, bool enabled);
 
+// LookTransform
+void deleteLookTransform(LookTransformId p);
+LookTransformId LookTransform_Create();
+LookTransformId LookTransform_createEditableCopy(LookTransformId p);
+TransformDirection LookTransform_getDirection(LookTransformId p);
+void LookTransform_setDirection(LookTransformId p, TransformDirection dir);
+const char* LookTransform_getSrc(LookTransformId p);
+void LookTransform_setSrc(LookTransformId p, const char* src);
+const char* LookTransform_getDst(LookTransformId p);
+void LookTransform_setDst(LookTransformId p, const char* dst);
+const char* LookTransform_getLooks(LookTransformId p);
+void LookTransform_setLooks(LookTransformId p, const char* looks);
+
+// AllocationTransform
+void deleteAllocationTransform(AllocationTransformId p);
+AllocationTransformId AllocationTransform_Create();
+AllocationTransformId AllocationTransform_createEditableCopy(AllocationTransformId p);
+TransformDirection AllocationTransform_getDirection(AllocationTransformId p);
+void AllocationTransform_setDirection(AllocationTransformId p, TransformDirection dir);
+Allocation AllocationTransform_getAllocation(AllocationTransformId p);
+void AllocationTransform_setAllocation(AllocationTransformId p, Allocation allocation);
+int AllocationTransform_getNumVars(AllocationTransformId p);
+void AllocationTransform_getVars(AllocationTransformId p, float* vars);
+void AllocationTransform_setVars(AllocationTransformId p, int numvars, const float* vars);
+
+// CDLTransform
+void deleteCDLTransform(CDLTransformId p);
+CDLTransformId CDLTransform_Create();
+CDLTransformId CDLTransform_createEditableCopy(CDLTransformId p);
+TransformDirection CDLTransform_getDirection(CDLTransformId p);
+void CDLTransform_setDirection(CDLTransformId p, TransformDirection dir);
+void CDLTransform_getSlope(CDLTransformId p, float* rgb);
+void CDLTransform_setSlope(CDLTransformId p, const float* rgb);
+void CDLTransform_getOffset(CDLTransformId p, float* rgb);
+void CDLTransform_setOffset(CDLTransformId p, const float* rgb);
+void CDLTransform_getPower(CDLTransformId p, float* rgb);
+void CDLTransform_setPower(CDLTransformId p, const float* rgb);
+float CDLTransform_getSat(CDLTransformId p);
+void CDLTransform_setSat(CDLTransformId p, float sat);
+void CDLTransform_getSatLumaCoefs(CDLTransformId p, float* rgb);
+const char* CDLTransform_getID(CDLTransformId p);
+void CDLTransform_setID(CDLTransformId p, const char* id);
+const char* CDLTransform_getDescription(CDLTransformId p);
+void CDLTransform_setDescription(CDLTransformId p, const char* desc);
+const char* CDLTransform_getXML(CDLTransformId p);
+_HandleContext* CDLTransform_setXML(CDLTransformId p, const char* xml);
+bool CDLTransform_equals(CDLTransformId p, CDLTransformId other);
+_HandleContext* CDLTransform_CreateFromFile(const char* src, const char* cccid);
+
+// ColorSpaceTransform
+void deleteColorSpaceTransform(ColorSpaceTransformId p);
+ColorSpaceTransformId ColorSpaceTransform_Create();
+ColorSpaceTransformId ColorSpaceTransform_createEditableCopy(ColorSpaceTransformId p);
+TransformDirection ColorSpaceTransform_getDirection(ColorSpaceTransformId p);
+void ColorSpaceTransform_setDirection(ColorSpaceTransformId p, TransformDirection dir);
+const char* ColorSpaceTransform_getSrc(ColorSpaceTransformId p);
+void ColorSpaceTransform_setSrc(ColorSpaceTransformId p, const char* src);
+const char* ColorSpaceTransform_getDst(ColorSpaceTransformId p);
+void ColorSpaceTransform_setDst(ColorSpaceTransformId p, const char* dst);
+
+// ExponentTransform
+void deleteExponentTransform(ExponentTransformId p);
+ExponentTransformId ExponentTransform_Create();
+ExponentTransformId ExponentTransform_createEditableCopy(ExponentTransformId p);
+TransformDirection ExponentTransform_getDirection(ExponentTransformId p);
+void ExponentTransform_setDirection(ExponentTransformId p, TransformDirection dir);
+void ExponentTransform_getValue(ExponentTransformId p, float* vec4);
+void ExponentTransform_setValue(ExponentTransformId p, const float* vec4);
+
+// FileTransform
+void deleteFileTransform(FileTransformId p);
+FileTransformId FileTransform_Create();
+FileTransformId FileTransform_createEditableCopy(FileTransformId p);
+TransformDirection FileTransform_getDirection(FileTransformId p);
+void FileTransform_setDirection(FileTransformId p, TransformDirection dir);
+const char* FileTransform_getSrc(FileTransformId p);
+void FileTransform_setSrc(FileTransformId p, const char* src);
+const char* FileTransform_getCCCId(FileTransformId p);
+void FileTransform_setCCCId(FileTransformId p, const char* id);
+Interpolation FileTransform_getInterpolation(FileTransformId p);
+void FileTransform_setInterpolation(FileTransformId p, Interpolation interp);
+
+// GroupTransform
+void deleteGroupTransform(GroupTransformId p);
+GroupTransformId GroupTransform_Create();
+GroupTransformId GroupTransform_createEditableCopy(GroupTransformId p);
+TransformDirection GroupTransform_getDirection(GroupTransformId p);
+void GroupTransform_setDirection(GroupTransformId p, TransformDirection dir);
+TransformId GroupTransform_getTransform(GroupTransformId p, int index);
+int GroupTransform_size(GroupTransformId p);
+void GroupTransform_push_back(GroupTransformId p, TransformId tx);
+void GroupTransform_clear(GroupTransformId p);
+bool GroupTransform_empty(GroupTransformId p);
+
+// LogTransform
+void deleteLogTransform(LogTransformId p);
+LogTransformId LogTransform_Create();
+LogTransformId LogTransform_createEditableCopy(LogTransformId p);
+TransformDirection LogTransform_getDirection(LogTransformId p);
+void LogTransform_setDirection(LogTransformId p, TransformDirection dir);
+float LogTransform_getBase(LogTransformId p);
+void LogTransform_setBase(LogTransformId p, float base);
+
+// MatrixTransform
+void deleteMatrixTransform(MatrixTransformId p);
+MatrixTransformId MatrixTransform_Create();
+MatrixTransformId MatrixTransform_createEditableCopy(MatrixTransformId p);
+TransformDirection MatrixTransform_getDirection(MatrixTransformId p);
+void MatrixTransform_setDirection(MatrixTransformId p, TransformDirection dir);
+void MatrixTransform_getValue(MatrixTransformId p, float* m44, float* offset4);
+void MatrixTransform_setValue(MatrixTransformId p, const float* m44, const float* offset4);
+void MatrixTransform_getMatrix(MatrixTransformId p, float* m44);
+void MatrixTransform_setMatrix(MatrixTransformId p, const float* m44);
+void MatrixTransform_getOffset(MatrixTransformId p, float* offset4);
+void MatrixTransform_setOffset(MatrixTransformId p, const float* offset4);
+
 #ifdef __cplusplus
 }
 #endif
@@ -34,6 +34,7 @@ inline void free_last_ctx_err(_HandleContext* ctx) {
         free((char*)(ctx->last_error));
         ctx->last_error = NULL;
         ctx->has_error = false;
+        ctx->last_errno = 0;
     }
 }
 
@@ -67,6 +68,9 @@ type that can store exception information.
 
 Only some of the types that need to convey error details are defined as
 _HandleContext and stored in maps (Config, Context, Processor).
+
+An OCIO::ExceptionMissingFile is recorded with ERR_MISSING_FILE, so that
+callers can distinguish a missing file from other failures.
 */
 #define BEGIN_CATCH_CTX_ERR(CTX)             \
     errno = 0;                               \
@@ -77,10 +81,18 @@ _HandleContext and stored in maps (Config, Context, Processor).
 #define END_CATCH_CTX_ERR(CTX)               \
         errno = 0;                           \
     }                                        \
+    catch (const OCIO::ExceptionMissingFile& ex) { \
+        free_last_ctx_err(CTX);              \
+        CTX->last_error = strdup(ex.what()); \
+        CTX->has_error = true;               \
+        CTX->last_errno = ERR_MISSING_FILE;  \
+        errno = ERR_MISSING_FILE;            \
+    }                                        \
     catch (const OCIO::Exception& ex) {      \
         free_last_ctx_err(CTX);              \
         CTX->last_error = strdup(ex.what()); \
         CTX->has_error = true;               \
+        CTX->last_errno = ERR_GENERAL;       \
         errno = ERR_GENERAL;                 \
     }
 
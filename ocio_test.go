@@ -1,6 +1,8 @@
 package ocio
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -170,6 +172,32 @@ func TestConfigFromFile(t *testing.T) {
 	}
 }
 
+func TestConfigFromFileContext(t *testing.T) {
+	cfgPath := os.Getenv("OCIO")
+	c, err := ConfigCreateFromFileContext(context.Background(), cfgPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if c.NumColorSpaces() == 0 {
+		t.Error("expected config to have colorspaces")
+	}
+	c.Destroy()
+
+	_, err = ConfigCreateFromFileContext(context.Background(), "/path/to/missing/ocio/file.ocio")
+	if err == nil {
+		t.Fatal("expected missing config file path to return error; got nil")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = ConfigCreateFromFileContext(ctx, cfgPath); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err = ConfigCreateFromEnvContext(ctx); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
 func TestConfigFromData(t *testing.T) {
 	c, err := ConfigCreateFromData(OCIO_CONFIG)
 	if err != nil {
@@ -546,6 +574,172 @@ func TestConfigProcessor(t *testing.T) {
 	proc.Destroy()
 }
 
+func TestConfigProcessorContext(t *testing.T) {
+	cfg, err := ConfigCreateFromEnvContext(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	proc, err := cfg.ProcessorContext(context.Background(), "scene_linear", "color_timing")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if path := proc.Metadata().File(0); !strings.HasSuffix(path, "/luts/lg10.spi1d") {
+		t.Fatalf("Expected path %q to end with /luts/lg10.spi1d", path)
+	}
+	proc.Destroy()
+
+	if _, err = cfg.ProcessorContext(context.Background(), "scene_linear", "bad"); err == nil {
+		t.Error("expected an error for an invalid colorspace")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = cfg.ProcessorContext(ctx, "scene_linear", "color_timing"); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestConfigProcessorError(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	_, err = cfg.Processor("scene_linear", "__bad__")
+	if err == nil {
+		t.Fatal("expected an error for an invalid colorspace")
+	}
+	var ocioErr *OCIOError
+	if !errors.As(err, &ocioErr) {
+		t.Fatalf("expected error to wrap an *OCIOError; got %T: %v", err, err)
+	}
+	// Processor is a wrapper, so the op is the call it makes
+	if ocioErr.Op != "Config.ProcessorFromNames" || ocioErr.Handle != "Config" {
+		t.Errorf("expected Config.ProcessorFromNames on a Config handle; got %q on %q", ocioErr.Op, ocioErr.Handle)
+	}
+	if errors.Is(err, ErrMissingFile{}) {
+		t.Errorf("expected an invalid colorspace not to be an ErrMissingFile")
+	}
+
+	_, err = ConfigCreateFromFile("/path/to/missing/ocio/file.ocio")
+	if !errors.As(err, &ocioErr) || ocioErr.Op != "ConfigCreateFromFile" {
+		t.Errorf("expected an *OCIOError from ConfigCreateFromFile; got %v", err)
+	}
+}
+
+func TestConfigProcessorTyped(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	ct, err := cfg.CurrentContext()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	src, err := cfg.ColorSpace("lnf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	dst, err := cfg.ColorSpace("lg10")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	procs := map[string]func() (*Processor, error){
+		"ProcessorFromNames": func() (*Processor, error) {
+			return cfg.ProcessorFromNames("lnf", "lg10")
+		},
+		"ProcessorCtxFromNames": func() (*Processor, error) {
+			return cfg.ProcessorCtxFromNames(ct, "lnf", "lg10")
+		},
+		"ProcessorFromColorSpaces": func() (*Processor, error) {
+			return cfg.ProcessorFromColorSpaces(src, dst)
+		},
+		"ProcessorCtxFromColorSpaces": func() (*Processor, error) {
+			return cfg.ProcessorCtxFromColorSpaces(ct, src, dst)
+		},
+		"ProcessorWithOptions/names": func() (*Processor, error) {
+			return cfg.ProcessorWithOptions(WithNames("lnf", "lg10"))
+		},
+		"ProcessorWithOptions/colorspaces": func() (*Processor, error) {
+			return cfg.ProcessorWithOptions(WithColorSpaces(src, dst), WithContext(ct))
+		},
+		"ProcessorWithOptions/transform": func() (*Processor, error) {
+			tx := NewColorSpaceTransform()
+			tx.SetSrc("lnf")
+			tx.SetDst("lg10")
+			return cfg.ProcessorWithOptions(WithTransform(tx, TRANSFORM_DIR_FORWARD))
+		},
+	}
+
+	expect, err := cfg.Processor("lnf", "lg10")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer expect.Destroy()
+	expectID, _ := expect.CpuCacheID()
+
+	for name, fn := range procs {
+		proc, err := fn()
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if id, _ := proc.CpuCacheID(); id != expectID {
+			t.Errorf("%s: expected cache id %q, got %q", name, expectID, id)
+		}
+		proc.Destroy()
+	}
+}
+
+func TestConfigProcessorTypedErrors(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	src, err := cfg.ColorSpace("lnf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, err = cfg.Processor("lnf", src); err == nil {
+		t.Error("expected an error mixing a string and a *ColorSpace")
+	}
+	if _, err = cfg.ProcessorFromColorSpaces(src, nil); err == nil {
+		t.Error("expected an error for a nil ColorSpace")
+	}
+	if _, err = cfg.ProcessorCtxFromNames(nil, "lnf", "lg10"); err == nil {
+		t.Error("expected an error for a nil Context")
+	}
+	var nilCtx *Context
+	if _, err = cfg.Processor(nilCtx, "lnf", "lg10"); err == nil {
+		t.Error("expected an error for a nil *Context argument")
+	}
+
+	proc, err := cfg.ProcessorFromNames("lnf", "__bad__")
+	if err == nil {
+		t.Error("expected an error for an invalid colorspace")
+	}
+	if proc != nil {
+		t.Error("expected a nil Processor on error")
+	}
+
+	if _, err = cfg.ProcessorWithOptions(); err == nil {
+		t.Error("expected an error with no conversion options")
+	}
+	if _, err = cfg.ProcessorWithOptions(WithNames("lnf", "lg10"), WithColorSpaces(src, src)); err == nil {
+		t.Error("expected an error with multiple conversion options")
+	}
+}
+
 func TestConfigProcessorTransform(t *testing.T) {
 	cfg, _ := CurrentConfig()
 	ct, err := cfg.CurrentContext()
@@ -598,143 +792,670 @@ func TestConfigProcessorTransform(t *testing.T) {
 	tx.Destroy()
 }
 
-func TestConfigDisplaysViews(t *testing.T) {
-	var (
-		str string
-		i   int
-		err error
-	)
-
-	cfg := CONFIG.EditableCopy()
+func TestConfigProcessorLookTransform(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cfg = cfg.EditableCopy()
 	defer cfg.Destroy()
 
-	cfg.SetActiveDisplays("DCIP3, sRGB")
-	str = cfg.DefaultDisplay()
-	if str != "DCIP3" {
-		t.Errorf("expected DefaultDisplay to be 'DCIP3', but got %q", str)
+	look := NewLook()
+	look.SetName("unittest_look")
+	look.SetProcessSpace("lg10")
+	if err = cfg.AddLook(look); err != nil {
+		t.Fatal(err.Error())
 	}
+	look.Destroy()
 
-	i = cfg.NumDisplays()
-	if i != 2 {
-		t.Errorf("expected NumDisplays to be 2. but got %d", i)
-	}
+	tx := NewLookTransform()
+	defer tx.Destroy()
+	tx.SetSrc("lnf")
+	tx.SetDst("lnf")
+	tx.SetLooks("unittest_look")
 
-	expectStrs := []string{"DCIP3", "sRGB"}
-	strs := []string{cfg.Display(0), cfg.Display(1)}
-	sort.Strings(strs)
-	if !reflect.DeepEqual(strs, expectStrs) {
-		t.Errorf("expected displays %#v, but got %#v", expectStrs, str)
+	proc, err := cfg.ProcessorTransform(tx)
+	if err != nil {
+		t.Fatal(err.Error())
 	}
-
-	str = cfg.DefaultView("sRGB")
-	if str != "Film" {
-		t.Errorf("expected DefaultView for 'sRGB' to be 'Film', but got %q", str)
+	// The look is applied in its process space
+	if path := proc.Metadata().File(0); !strings.HasSuffix(path, "/luts/lg10.spi1d") {
+		t.Fatalf("Expected path %q to end with /luts/lg10.spi1d", path)
 	}
+	proc.Destroy()
 
-	if i = cfg.NumViews("sRGB"); i != 4 {
-		t.Errorf("expected NumViews to be 4. but got %d", i)
+	proc, err = cfg.ProcessorTransformDir(tx, TRANSFORM_DIR_INVERSE)
+	if err != nil {
+		t.Fatal(err.Error())
 	}
+	proc.Destroy()
 
-	if str = cfg.View("sRGB", 2); str != "Raw" {
-		t.Errorf("expected View at index 2 to be 'Raw', but got %q", str)
+	tx.SetLooks("__missing_look__")
+	if _, err = cfg.ProcessorTransform(tx); err == nil {
+		t.Fatal("expected an error for a missing look; got nil")
 	}
+}
 
-	if str = cfg.ActiveDisplays(); str != "DCIP3, sRGB" {
-		t.Errorf("expected ActiveDisplays to be 'sRGB, DCIP3', but got %q", str)
+func TestConfigProcessorAtomicTransforms(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
 	}
+	defer cfg.Destroy()
 
-	if str = cfg.ActiveViews(); str != "Film, Log, Raw" {
-		t.Errorf("expected ActiveViews to be 'Film, Log, Raw', but got %q", str)
-	}
+	mtx := NewMatrixTransform()
+	defer mtx.Destroy()
+	mtx.SetMatrix([16]float32{
+		2, 0, 0, 0,
+		0, 2, 0, 0,
+		0, 0, 2, 0,
+		0, 0, 0, 1,
+	})
 
-	if str = cfg.DisplayLooks("sRGB", "Film DI"); str != "di" {
-		t.Errorf("expected DisplayLooks for 'sRGB' / 'Film DI' to be 'di', but got %q", str)
+	proc, err := cfg.ProcessorTransform(mtx)
+	if err != nil {
+		t.Fatal(err.Error())
 	}
-
-	if str = cfg.DisplayColorSpaceName("sRGB", "Raw"); str != "nc10" {
-		t.Errorf("expected DisplayColorSpaceName for 'sRGB' / 'Raw' to be 'nc10', but got %q", str)
+	pixel := ColorData{0.1, 0.2, 0.3}
+	imgDesc := NewPackedImageDesc(pixel, 1, 1, 3)
+	if err = proc.Apply(imgDesc); err != nil {
+		t.Fatal(err.Error())
 	}
-
-	prev := cfg.NumViews("sRGB")
-	if err = cfg.AddDisplay("sRGB", "TEST_VIEW", "vd8", "di"); err != nil {
-		t.Error(err.Error())
+	expect := ColorData{0.2, 0.4, 0.6}
+	if !reflect.DeepEqual(imgDesc.Data(), expect) {
+		t.Errorf("expected %v; got %v", expect, imgDesc.Data())
 	}
-	if i = cfg.NumViews("sRGB"); i != (prev + 1) {
-		t.Errorf("expected NumViews for 'sRGB' to be %d, but got %d", prev+1, i)
+	imgDesc.Destroy()
+	proc.Destroy()
+
+	cstx := NewColorSpaceTransform()
+	defer cstx.Destroy()
+	cstx.SetSrc("lnf")
+	cstx.SetDst("lg10")
+
+	proc, err = cfg.ProcessorTransform(cstx)
+	if err != nil {
+		t.Fatal(err.Error())
 	}
-	if str = cfg.DisplayLooks("sRGB", "TEST_VIEW"); str != "di" {
-		t.Errorf("expected DisplayLooks for 'sRGB' / 'TEST_VIEW' to be 'di', but got %q", str)
+	if path := proc.Metadata().File(0); !strings.HasSuffix(path, "/luts/lg10.spi1d") {
+		t.Fatalf("Expected path %q to end with /luts/lg10.spi1d", path)
 	}
+	proc.Destroy()
 
-	if err = cfg.SetActiveDisplays("DCIP3"); err != nil {
-		t.Error(err.Error())
+	transforms := []Transform{
+		NewAllocationTransform(),
+		NewCDLTransform(),
+		NewExponentTransform(),
+		NewGroupTransform(),
+		NewLogTransform(),
 	}
-	if str = cfg.ActiveDisplays(); str != "DCIP3" {
-		t.Errorf("expected ActiveDisplays to be 'DCIP3', but got %q", str)
+	for _, tx := range transforms {
+		proc, err = cfg.ProcessorTransform(tx)
+		if err != nil {
+			t.Errorf("%T: %v", tx, err)
+			continue
+		}
+		proc.Destroy()
 	}
+}
 
-	if err = cfg.SetActiveViews("Log, Raw"); err != nil {
-		t.Error(err.Error())
+func TestConfigProcessorFileTransform(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
 	}
-	if str = cfg.ActiveViews(); str != "Log, Raw" {
-		t.Errorf("expected ActiveViews to be 'Log, Raw', but got %q", str)
+	defer cfg.Destroy()
+
+	luts := []string{
+		"lg10.spi1d",
+		"spi_ocio_srgb_test.spi3d",
+		"p3_to_xyz16.spimtx",
+		"colorworks_filmlg_to_p3.3dl",
 	}
-}
 
-/*
+	for _, lut := range luts {
+		tx := NewFileTransform()
+		tx.SetSrc(lut)
+		tx.SetInterpolation(INTERP_LINEAR)
 
-ColorSpaces
+		proc, err := cfg.ProcessorTransform(tx)
+		if err != nil {
+			t.Errorf("%s: %v", lut, err)
+			tx.Destroy()
+			continue
+		}
+		if path := proc.Metadata().File(0); !strings.HasSuffix(path, "/luts/"+lut) {
+			t.Errorf("Expected path %q to end with /luts/%s", path, lut)
+		}
 
-*/
-func TestColorSpace(t *testing.T) {
-	c := CONFIG
+		imgDesc, imageData := getGradImageDesc(16, 16, 3)
+		imageDataCopy := make(ColorData, len(imageData))
+		copy(imageDataCopy, imageData)
 
-	name, _ := c.ColorSpaceNameByIndex(0)
-	if name == "" {
-		t.Fatal("empty colorspace name")
-	}
-	cs, err := c.ColorSpace(name)
-	if err != nil {
-		t.Fatalf("Error getting a ColorSpace from name %s: %s", name, err.Error())
+		if err = proc.Apply(imgDesc); err != nil {
+			t.Errorf("%s: %v", lut, err)
+		} else if reflect.DeepEqual(imageDataCopy, imgDesc.Data()) {
+			t.Errorf("%s: Original RGB data remained unchanged after Apply()", lut)
+		}
+		imgDesc.Destroy()
+		proc.Destroy()
+		tx.Destroy()
 	}
-	t.Logf("ColorSpace: %+v", cs)
-}
 
-func TestColorSpaceCreate(t *testing.T) {
-	cs := NewColorSpace()
-	t.Log(cs)
-	cs.Destroy()
+	tx := NewFileTransform()
+	defer tx.Destroy()
+	tx.SetSrc("__missing__.spi1d")
+
+	_, err = cfg.ProcessorTransform(tx)
+	if err == nil {
+		t.Fatal("expected an error for a missing LUT; got nil")
+	}
+	if !errors.Is(err, ErrMissingFile{}) {
+		t.Fatalf("expected error to be ErrMissingFile; got %T: %v", err, err)
+	}
+	var ocioErr *OCIOError
+	if !errors.As(err, &ocioErr) {
+		t.Fatalf("expected error to be an *OCIOError; got %T", err)
+	}
+	if ocioErr.Op != "Config.ProcessorTransform" || ocioErr.Handle != "Config" {
+		t.Errorf("expected Config.ProcessorTransform on a Config handle; got %q on %q", ocioErr.Op, ocioErr.Handle)
+	}
 }
 
-func TestColorSpaceEditableCopy(t *testing.T) {
-	cfg := CONFIG.EditableCopy()
-	defer cfg.Destroy()
-	cs, err := cfg.ColorSpace("lnf")
+func TestConfigProcessorCDLTransform(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
 	if err != nil {
 		t.Fatal(err.Error())
 	}
-	cs_copy := cs.EditableCopy()
-	t.Logf("%s is a copy of %s", cs_copy, cs)
+	defer cfg.Destroy()
 
-	if cs.Name() != cs_copy.Name() {
-		t.Fatalf("Copy colorspace name is %s, but expected %s", cs_copy.Name(), cs.Name())
+	tx, err := CDLTransformFromFile("testdata/cdl/grades.ccc", "shot_010")
+	if err != nil {
+		t.Fatal(err.Error())
 	}
-	cs.Destroy()
-	cs_copy.Destroy()
-}
+	defer tx.Destroy()
 
-func TestColorSpaceName(t *testing.T) {
-	cfg := CONFIG.EditableCopy()
-	defer cfg.Destroy()
-	cs, err := cfg.ColorSpace("lnf")
+	proc, err := cfg.ProcessorTransform(tx)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
-	cs.SetName("FOO")
-	if cs.Name() != "FOO" {
-		t.Fatalf("Expected ColorSpace name to be FOO, got %s", cs.Name())
+	defer proc.Destroy()
+
+	imgDesc, imageData := getGradImageDesc(16, 16, 3)
+	defer imgDesc.Destroy()
+	imageDataCopy := make(ColorData, len(imageData))
+	copy(imageDataCopy, imageData)
+
+	if err = proc.Apply(imgDesc); err != nil {
+		t.Fatal(err.Error())
+	}
+	if reflect.DeepEqual(imageDataCopy, imgDesc.Data()) {
+		t.Fatal("Original RGB data remained unchanged after Apply()")
 	}
-	cs.Destroy()
+}
+
+func TestConfigProcessorGroupTransform(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	ct, err := cfg.CurrentContext()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer ct.Destroy()
+
+	mtx := NewMatrixTransform()
+	defer mtx.Destroy()
+	mtx.SetOffset([4]float32{0.1, 0.1, 0.1, 0})
+
+	ltx := NewLogTransform()
+	defer ltx.Destroy()
+	ltx.SetBase(10)
+
+	ftx := NewFileTransform()
+	defer ftx.Destroy()
+	ftx.SetSrc("lg10.spi1d")
+	ftx.SetInterpolation(INTERP_LINEAR)
+
+	inner := NewGroupTransform()
+	defer inner.Destroy()
+	inner.Push(ltx)
+	inner.Push(ftx)
+
+	group := NewGroupTransform()
+	defer group.Destroy()
+	group.Push(mtx)
+	group.Push(inner)
+
+	proc, err := cfg.ProcessorTransform(group)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if path := proc.Metadata().File(0); !strings.HasSuffix(path, "/luts/lg10.spi1d") {
+		t.Fatalf("Expected path %q to end with /luts/lg10.spi1d", path)
+	}
+
+	imgDesc, imageData := getGradImageDesc(16, 16, 3)
+	imageDataCopy := make(ColorData, len(imageData))
+	copy(imageDataCopy, imageData)
+	if err = proc.Apply(imgDesc); err != nil {
+		t.Fatal(err.Error())
+	}
+	if reflect.DeepEqual(imageDataCopy, imgDesc.Data()) {
+		t.Fatal("Original RGB data remained unchanged after Apply()")
+	}
+	imgDesc.Destroy()
+	proc.Destroy()
+
+	proc, err = cfg.ProcessorTransformDir(group, TRANSFORM_DIR_INVERSE)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	proc.Destroy()
+
+	ct2 := NewContext()
+	defer ct2.Destroy()
+	ct2.SetStringVar("OVERRIDE", "luts2")
+	ct2.SetSearchPath(ct.SearchPath())
+	ct2.SetWorkingDir(ct.WorkingDir())
+
+	proc, err = cfg.ProcessorCtxTransformDir(ct2, group, TRANSFORM_DIR_FORWARD)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if path := proc.Metadata().File(0); !strings.HasSuffix(path, "/luts2/lg10.spi1d") {
+		t.Fatalf("Expected path %q to end with /luts2/lg10.spi1d", path)
+	}
+	proc.Destroy()
+}
+
+func TestFileTransformFormats(t *testing.T) {
+	formats := FileTransformFormats()
+	if len(formats) == 0 {
+		t.Fatal("expected at least one FileTransform format")
+	}
+
+	expect := map[string]bool{"spi1d": false, "spi3d": false, "spimtx": false, "3dl": false}
+	for _, format := range formats {
+		if _, ok := expect[format.Extension]; ok {
+			expect[format.Extension] = true
+		}
+	}
+	for ext, found := range expect {
+		if !found {
+			t.Errorf("expected a FileTransform format with extension %q in %v", ext, formats)
+		}
+	}
+}
+
+func TestConfigDisplaysViews(t *testing.T) {
+	var (
+		str string
+		i   int
+		err error
+	)
+
+	cfg := CONFIG.EditableCopy()
+	defer cfg.Destroy()
+
+	cfg.SetActiveDisplays("DCIP3, sRGB")
+	str = cfg.DefaultDisplay()
+	if str != "DCIP3" {
+		t.Errorf("expected DefaultDisplay to be 'DCIP3', but got %q", str)
+	}
+
+	i = cfg.NumDisplays()
+	if i != 2 {
+		t.Errorf("expected NumDisplays to be 2. but got %d", i)
+	}
+
+	expectStrs := []string{"DCIP3", "sRGB"}
+	strs := []string{cfg.Display(0), cfg.Display(1)}
+	sort.Strings(strs)
+	if !reflect.DeepEqual(strs, expectStrs) {
+		t.Errorf("expected displays %#v, but got %#v", expectStrs, str)
+	}
+
+	str = cfg.DefaultView("sRGB")
+	if str != "Film" {
+		t.Errorf("expected DefaultView for 'sRGB' to be 'Film', but got %q", str)
+	}
+
+	if i = cfg.NumViews("sRGB"); i != 4 {
+		t.Errorf("expected NumViews to be 4. but got %d", i)
+	}
+
+	if str = cfg.View("sRGB", 2); str != "Raw" {
+		t.Errorf("expected View at index 2 to be 'Raw', but got %q", str)
+	}
+
+	if str = cfg.ActiveDisplays(); str != "DCIP3, sRGB" {
+		t.Errorf("expected ActiveDisplays to be 'sRGB, DCIP3', but got %q", str)
+	}
+
+	if str = cfg.ActiveViews(); str != "Film, Log, Raw" {
+		t.Errorf("expected ActiveViews to be 'Film, Log, Raw', but got %q", str)
+	}
+
+	if str = cfg.DisplayLooks("sRGB", "Film DI"); str != "di" {
+		t.Errorf("expected DisplayLooks for 'sRGB' / 'Film DI' to be 'di', but got %q", str)
+	}
+
+	if str = cfg.DisplayColorSpaceName("sRGB", "Raw"); str != "nc10" {
+		t.Errorf("expected DisplayColorSpaceName for 'sRGB' / 'Raw' to be 'nc10', but got %q", str)
+	}
+
+	prev := cfg.NumViews("sRGB")
+	if err = cfg.AddDisplay("sRGB", "TEST_VIEW", "vd8", "di"); err != nil {
+		t.Error(err.Error())
+	}
+	if i = cfg.NumViews("sRGB"); i != (prev + 1) {
+		t.Errorf("expected NumViews for 'sRGB' to be %d, but got %d", prev+1, i)
+	}
+	if str = cfg.DisplayLooks("sRGB", "TEST_VIEW"); str != "di" {
+		t.Errorf("expected DisplayLooks for 'sRGB' / 'TEST_VIEW' to be 'di', but got %q", str)
+	}
+
+	if err = cfg.SetActiveDisplays("DCIP3"); err != nil {
+		t.Error(err.Error())
+	}
+	if str = cfg.ActiveDisplays(); str != "DCIP3" {
+		t.Errorf("expected ActiveDisplays to be 'DCIP3', but got %q", str)
+	}
+
+	if err = cfg.SetActiveViews("Log, Raw"); err != nil {
+		t.Error(err.Error())
+	}
+	if str = cfg.ActiveViews(); str != "Log, Raw" {
+		t.Errorf("expected ActiveViews to be 'Log, Raw', but got %q", str)
+	}
+}
+
+func TestConfigSearchPaths(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	expect := []string{"$OVERRIDE", "luts"}
+	if actual, err := cfg.SearchPaths(); err != nil {
+		t.Fatal(err.Error())
+	} else if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected search paths %v, got %v", expect, actual)
+	}
+
+	ct, err := cfg.CurrentContext()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	wd, _ := cfg.WorkingDir()
+	expect = []string{filepath.Join(wd, "luts"), filepath.Join(wd, "luts")}
+	if actual := ct.SearchPaths(); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected resolved search paths %v, got %v", expect, actual)
+	}
+}
+
+func TestConfigEnvironment(t *testing.T) {
+	cfg := CONFIG.EditableCopy()
+	defer cfg.Destroy()
+
+	if err := cfg.ClearEnvironmentVars(); err != nil {
+		t.Fatal(err.Error())
+	}
+	env, err := cfg.Environment()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(env) != 0 {
+		t.Errorf("expected an empty environment, got %v", env)
+	}
+
+	if err = cfg.AddEnvironmentVar("SHOT", "default_shot"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = cfg.AddEnvironmentVar("SEQ", ""); err != nil {
+		t.Fatal(err.Error())
+	}
+	expect := map[string]string{"SHOT": "default_shot", "SEQ": ""}
+	if env, err = cfg.Environment(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(env, expect) {
+		t.Errorf("expected environment %v, got %v", expect, env)
+	}
+}
+
+/*
+
+Luma
+
+*/
+func TestConfigLuma(t *testing.T) {
+	cfg := CONFIG.EditableCopy()
+	defer cfg.Destroy()
+
+	expect := [3]float32{0.2126, 0.7152, 0.0722}
+	if actual := cfg.DefaultLumaCoefs(); actual != expect {
+		t.Errorf("expected DefaultLumaCoefs %v, got %v", expect, actual)
+	}
+
+	expect = [3]float32{0.2627, 0.678, 0.0593}
+	if err := cfg.SetDefaultLumaCoefs(expect); err != nil {
+		t.Fatal(err.Error())
+	}
+	if actual := cfg.DefaultLumaCoefs(); actual != expect {
+		t.Errorf("expected DefaultLumaCoefs %v, got %v", expect, actual)
+	}
+}
+
+/*
+
+Looks
+
+*/
+func TestConfigLooks(t *testing.T) {
+	cfg := CONFIG.EditableCopy()
+	defer cfg.Destroy()
+
+	if n := cfg.NumLooks(); n != 1 {
+		t.Fatalf("expected NumLooks to be 1, but got %d", n)
+	}
+
+	name, err := cfg.LookNameByIndex(0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if name != "di" {
+		t.Errorf("expected look name at index 0 to be 'di', but got %q", name)
+	}
+
+	look, err := cfg.Look("di")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if str := look.Name(); str != "di" {
+		t.Errorf("expected look name 'di', but got %q", str)
+	}
+	if str := look.ProcessSpace(); str != "p3dci8" {
+		t.Errorf("expected look process space 'p3dci8', but got %q", str)
+	}
+	tx, err := look.Transform()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if ftx, ok := tx.(*FileTransform); !ok {
+		t.Errorf("expected look transform to be a *FileTransform, but got %T", tx)
+	} else if str := ftx.Src(); str != "look_di.cc" {
+		t.Errorf("expected look transform src 'look_di.cc', but got %q", str)
+	}
+	look.Destroy()
+
+	if _, err = cfg.Look("__missing__"); err == nil {
+		t.Error("expected an error for a missing look; got nil")
+	}
+
+	look = NewLook()
+	look.SetName("grade")
+	look.SetProcessSpace("lg10")
+	look.SetDescription("unittest grade")
+	if err = cfg.AddLook(look); err != nil {
+		t.Fatal(err.Error())
+	}
+	look.Destroy()
+
+	if n := cfg.NumLooks(); n != 2 {
+		t.Fatalf("expected NumLooks to be 2, but got %d", n)
+	}
+
+	look, err = cfg.Look("grade")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if str := look.ProcessSpace(); str != "lg10" {
+		t.Errorf("expected look process space 'lg10', but got %q", str)
+	}
+	if str := look.Description(); str != "unittest grade" {
+		t.Errorf("expected look description 'unittest grade', but got %q", str)
+	}
+	look.Destroy()
+
+	if err = cfg.ClearLooks(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if n := cfg.NumLooks(); n != 0 {
+		t.Fatalf("expected NumLooks to be 0, but got %d", n)
+	}
+}
+
+func TestLook(t *testing.T) {
+	look := NewLook()
+	defer look.Destroy()
+
+	if str := look.Name(); str != "" {
+		t.Errorf("expected empty string; got %q", str)
+	}
+	if str := look.ProcessSpace(); str != "" {
+		t.Errorf("expected empty string; got %q", str)
+	}
+
+	tx, err := look.Transform()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if tx != nil {
+		t.Errorf("expected nil transform; got %v", tx)
+	}
+
+	look.SetName("look")
+	look.SetProcessSpace("lnf")
+
+	dt := NewDisplayTransform()
+	dt.SetDisplay("sRGB")
+	look.SetTransform(dt)
+	look.SetInverseTransform(dt)
+	dt.Destroy()
+
+	for _, getter := range []func() (Transform, error){look.Transform, look.InverseTransform} {
+		tx, err = getter()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		actual, ok := tx.(*DisplayTransform)
+		if !ok {
+			t.Fatalf("expected *DisplayTransform; got %T", tx)
+		}
+		if str := actual.Display(); str != "sRGB" {
+			t.Errorf("expected display 'sRGB'; got %q", str)
+		}
+		actual.SetDisplay("changed")
+		actual.Destroy()
+	}
+
+	// The returned transforms are copies
+	tx, _ = look.Transform()
+	if str := tx.(*DisplayTransform).Display(); str != "sRGB" {
+		t.Errorf("expected the Look to keep display 'sRGB'; got %q", str)
+	}
+	tx.(*DisplayTransform).Destroy()
+
+	cpy := look.EditableCopy()
+	cpy.SetName("look2")
+	if str := cpy.Name(); str != "look2" {
+		t.Errorf("expected 'look2'; got %q", str)
+	}
+	if str := look.Name(); str != "look" {
+		t.Errorf("expected 'look'; got %q", str)
+	}
+	cpy.Destroy()
+
+	look.SetTransform(nil)
+	if tx, _ = look.Transform(); tx != nil {
+		t.Errorf("expected nil transform after clearing; got %v", tx)
+	}
+	look.SetInverseTransform((*MatrixTransform)(nil))
+	if tx, _ = look.InverseTransform(); tx != nil {
+		t.Errorf("expected nil transform after clearing with a nil pointer; got %v", tx)
+	}
+}
+
+/*
+
+ColorSpaces
+
+*/
+func TestColorSpace(t *testing.T) {
+	c := CONFIG
+
+	name, _ := c.ColorSpaceNameByIndex(0)
+	if name == "" {
+		t.Fatal("empty colorspace name")
+	}
+	cs, err := c.ColorSpace(name)
+	if err != nil {
+		t.Fatalf("Error getting a ColorSpace from name %s: %s", name, err.Error())
+	}
+	t.Logf("ColorSpace: %+v", cs)
+}
+
+func TestColorSpaceCreate(t *testing.T) {
+	cs := NewColorSpace()
+	t.Log(cs)
+	cs.Destroy()
+}
+
+func TestColorSpaceEditableCopy(t *testing.T) {
+	cfg := CONFIG.EditableCopy()
+	defer cfg.Destroy()
+	cs, err := cfg.ColorSpace("lnf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cs_copy := cs.EditableCopy()
+	t.Logf("%s is a copy of %s", cs_copy, cs)
+
+	if cs.Name() != cs_copy.Name() {
+		t.Fatalf("Copy colorspace name is %s, but expected %s", cs_copy.Name(), cs.Name())
+	}
+	cs.Destroy()
+	cs_copy.Destroy()
+}
+
+func TestColorSpaceName(t *testing.T) {
+	cfg := CONFIG.EditableCopy()
+	defer cfg.Destroy()
+	cs, err := cfg.ColorSpace("lnf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cs.SetName("FOO")
+	if cs.Name() != "FOO" {
+		t.Fatalf("Expected ColorSpace name to be FOO, got %s", cs.Name())
+	}
+	cs.Destroy()
 }
 
 func TestColorSpaceFamily(t *testing.T) {
@@ -748,65 +1469,322 @@ func TestColorSpaceFamily(t *testing.T) {
 	if cs.Family() != "FOO" {
 		t.Fatalf("Expected ColorSpace family to be FOO, got %s", cs.Family())
 	}
-	cs.Destroy()
+	cs.Destroy()
+}
+
+func TestColorSpaceEqualityGroup(t *testing.T) {
+	cfg := CONFIG.EditableCopy()
+	defer cfg.Destroy()
+	cs, err := cfg.ColorSpace("lnf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cs.SetEqualityGroup("FOO")
+	if cs.EqualityGroup() != "FOO" {
+		t.Fatalf("Expected ColorSpace EqualityGroup to be FOO, got %s", cs.EqualityGroup())
+	}
+	cs.Destroy()
+}
+
+func TestColorSpaceDescription(t *testing.T) {
+	cfg := CONFIG.EditableCopy()
+	defer cfg.Destroy()
+	cs, err := cfg.ColorSpace("lnf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cs.SetDescription("FOO")
+
+	if cs.Description() != "FOO" {
+		t.Fatalf("Expected ColorSpace Description to be FOO, got %s", cs.Description())
+	}
+	cs.Destroy()
+}
+
+func TestColorSpaceBitDepth(t *testing.T) {
+	cfg := CONFIG.EditableCopy()
+	defer cfg.Destroy()
+	cs, err := cfg.ColorSpace("lnf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	depths := []BitDepth{
+		BIT_DEPTH_UNKNOWN,
+		BIT_DEPTH_UINT8,
+		BIT_DEPTH_UINT10,
+		BIT_DEPTH_UINT12,
+		BIT_DEPTH_UINT14,
+		BIT_DEPTH_UINT16,
+		BIT_DEPTH_UINT32,
+		BIT_DEPTH_F16,
+		BIT_DEPTH_F32,
+	}
+
+	for _, d := range depths {
+		cs.SetBitDepth(d)
+		if cs.BitDepth() != d {
+			t.Fatalf("Expected ColorSpace BitDepth to be %v, got %v", d, cs.BitDepth())
+		}
+	}
+	cs.Destroy()
+}
+
+func TestColorSpaceIsData(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	cs, err := cfg.ColorSpace("ncf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cs.Destroy()
+	if !cs.IsData() {
+		t.Errorf("Expected 'ncf' ColorSpace to be data")
+	}
+
+	cs = cs.EditableCopy()
+	cs.SetIsData(false)
+	if cs.IsData() {
+		t.Errorf("Expected ColorSpace IsData to be false after SetIsData(false)")
+	}
+}
+
+func TestColorSpaceAllocation(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	cs, err := cfg.ColorSpace("lnf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cs.Destroy()
+
+	if cs.Allocation() != ALLOCATION_LG2 {
+		t.Errorf("Expected ALLOCATION_LG2, got %v", cs.Allocation())
+	}
+	expectVars := []float32{-15, 6}
+	if vars := cs.AllocationVars(); !reflect.DeepEqual(vars, expectVars) {
+		t.Errorf("Expected allocation vars %v, got %v", expectVars, vars)
+	}
+
+	cs = cs.EditableCopy()
+	cs.SetAllocation(ALLOCATION_UNIFORM)
+	if cs.Allocation() != ALLOCATION_UNIFORM {
+		t.Errorf("Expected ALLOCATION_UNIFORM, got %v", cs.Allocation())
+	}
+	expectVars = []float32{0, 1}
+	cs.SetAllocationVars(expectVars)
+	if vars := cs.AllocationVars(); !reflect.DeepEqual(vars, expectVars) {
+		t.Errorf("Expected allocation vars %v, got %v", expectVars, vars)
+	}
+	cs.SetAllocationVars(nil)
+	if vars := cs.AllocationVars(); len(vars) != 0 {
+		t.Errorf("Expected empty allocation vars, got %v", vars)
+	}
+}
+
+func TestColorSpaceReferenceTransform(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	cs, err := cfg.ColorSpace("lg10")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cs.Destroy()
+
+	tx, err := cs.Transform(COLORSPACE_DIR_TO_REFERENCE)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	ftx, ok := tx.(*FileTransform)
+	if !ok {
+		t.Fatalf("Expected a *FileTransform, got %T", tx)
+	}
+	if ftx.Src() != "lg10.spi1d" {
+		t.Errorf("Expected src 'lg10.spi1d', got %q", ftx.Src())
+	}
+	if ftx.Interpolation() != INTERP_NEAREST {
+		t.Errorf("Expected INTERP_NEAREST, got %v", ftx.Interpolation())
+	}
+
+	tx, err = cs.Transform(COLORSPACE_DIR_FROM_REFERENCE)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if tx != nil {
+		t.Errorf("Expected nil from_reference Transform, got %T", tx)
+	}
+}
+
+func TestColorSpaceTransformConfig(t *testing.T) {
+	// Build a config entirely in Go
+	cfg := NewConfig()
+	defer cfg.Destroy()
+
+	ref := NewColorSpace()
+	defer ref.Destroy()
+	ref.SetName("linear")
+	cfg.AddColorSpace(ref)
+
+	mtx := NewMatrixTransform()
+	defer mtx.Destroy()
+	mtx.SetMatrix([16]float32{
+		2, 0, 0, 0,
+		0, 2, 0, 0,
+		0, 0, 2, 0,
+		0, 0, 0, 1,
+	})
+
+	cs := NewColorSpace()
+	defer cs.Destroy()
+	cs.SetName("scaled")
+	cs.SetTransform(mtx, COLORSPACE_DIR_TO_REFERENCE)
+	cfg.AddColorSpace(cs)
+	cfg.SetRole(ROLE_REFERENCE, "linear")
+
+	tx, err := cs.Transform(COLORSPACE_DIR_TO_REFERENCE)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, ok := tx.(*MatrixTransform); !ok {
+		t.Fatalf("Expected a *MatrixTransform, got %T", tx)
+	}
+
+	// The returned transform is a copy, so changing
+	// one from the Config's ColorSpace does not change it
+	cfgCS, err := cfg.ColorSpace("scaled")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfgCS.Destroy()
+	if tx, err = cfgCS.Transform(COLORSPACE_DIR_TO_REFERENCE); err != nil {
+		t.Fatal(err.Error())
+	}
+	cfgMtx := tx.(*MatrixTransform)
+	cfgMtx.SetMatrix([16]float32{
+		3, 0, 0, 0,
+		0, 3, 0, 0,
+		0, 0, 3, 0,
+		0, 0, 0, 1,
+	})
+	cfgMtx.Destroy()
+
+	proc, err := cfg.Processor("scaled", "linear")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer proc.Destroy()
+
+	pixel := ColorData{0.1, 0.2, 0.3}
+	imgDesc := NewPackedImageDesc(pixel, 1, 1, 3)
+	defer imgDesc.Destroy()
+	if err = proc.Apply(imgDesc); err != nil {
+		t.Fatal(err.Error())
+	}
+	expect := ColorData{0.2, 0.4, 0.6}
+	if !reflect.DeepEqual(imgDesc.Data(), expect) {
+		t.Errorf("expected %v; got %v", expect, imgDesc.Data())
+	}
+
+	cs.SetTransform(nil, COLORSPACE_DIR_TO_REFERENCE)
+	if tx, err = cs.Transform(COLORSPACE_DIR_TO_REFERENCE); err != nil || tx != nil {
+		t.Errorf("Expected cleared Transform, got %T (err: %v)", tx, err)
+	}
+}
+
+/*
+
+Baker
+
+*/
+
+func TestBakerFormats(t *testing.T) {
+	formats := BakerFormats()
+	if len(formats) == 0 {
+		t.Fatal("Expected at least one Baker format")
+	}
+	found := false
+	for _, f := range formats {
+		if f.Name == "" {
+			t.Errorf("Expected a format name, got %+v", f)
+		}
+		if f.Name == "flame" && f.Extension == "3dl" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected 'flame' format with '3dl' extension in %v", formats)
+	}
 }
 
-func TestColorSpaceEqualityGroup(t *testing.T) {
-	cfg := CONFIG.EditableCopy()
-	defer cfg.Destroy()
-	cs, err := cfg.ColorSpace("lnf")
+func TestBaker(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
 	if err != nil {
 		t.Fatal(err.Error())
 	}
-	cs.SetEqualityGroup("FOO")
-	if cs.EqualityGroup() != "FOO" {
-		t.Fatalf("Expected ColorSpace EqualityGroup to be FOO, got %s", cs.EqualityGroup())
+	defer cfg.Destroy()
+
+	baker := NewBaker(cfg)
+	defer baker.Destroy()
+
+	baker.SetFormat("flame")
+	baker.SetInputSpace("lg10")
+	baker.SetTargetSpace("lnf")
+	baker.SetCubeSize(17)
+	baker.SetMetadata("test bake")
+
+	if actual := baker.Format(); actual != "flame" {
+		t.Errorf("Expected format 'flame', got %q", actual)
+	}
+	if actual := baker.InputSpace(); actual != "lg10" {
+		t.Errorf("Expected input space 'lg10', got %q", actual)
+	}
+	if actual := baker.TargetSpace(); actual != "lnf" {
+		t.Errorf("Expected target space 'lnf', got %q", actual)
+	}
+	if actual := baker.CubeSize(); actual != 17 {
+		t.Errorf("Expected cube size 17, got %d", actual)
+	}
+	if baker.Config() == nil {
+		t.Error("Expected Baker to have a Config")
 	}
-	cs.Destroy()
-}
 
-func TestColorSpaceDescription(t *testing.T) {
-	cfg := CONFIG.EditableCopy()
-	defer cfg.Destroy()
-	cs, err := cfg.ColorSpace("lnf")
-	if err != nil {
+	var buf strings.Builder
+	if err = baker.Bake(&buf); err != nil {
 		t.Fatal(err.Error())
 	}
-	cs.SetDescription("FOO")
-
-	if cs.Description() != "FOO" {
-		t.Fatalf("Expected ColorSpace Description to be FOO, got %s", cs.Description())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 17*17*17 {
+		t.Errorf("Expected at least %d lines in the baked lut, got %d", 17*17*17, len(lines))
 	}
-	cs.Destroy()
-}
 
-func TestColorSpaceBitDepth(t *testing.T) {
-	cfg := CONFIG.EditableCopy()
-	defer cfg.Destroy()
-	cs, err := cfg.ColorSpace("lnf")
+	cp, err := baker.EditableCopy()
 	if err != nil {
 		t.Fatal(err.Error())
 	}
-
-	depths := []BitDepth{
-		BIT_DEPTH_UNKNOWN,
-		BIT_DEPTH_UINT8,
-		BIT_DEPTH_UINT10,
-		BIT_DEPTH_UINT12,
-		BIT_DEPTH_UINT14,
-		BIT_DEPTH_UINT16,
-		BIT_DEPTH_UINT32,
-		BIT_DEPTH_F16,
-		BIT_DEPTH_F32,
+	defer cp.Destroy()
+	cp.SetFormat("not_a_format")
+	if err = cp.Bake(&buf); err == nil {
+		t.Error("Expected an error baking an unknown format")
 	}
 
-	for _, d := range depths {
-		cs.SetBitDepth(d)
-		if cs.BitDepth() != d {
-			t.Fatalf("Expected ColorSpace BitDepth to be %v, got %v", d, cs.BitDepth())
-		}
+	cp = NewBaker(cfg)
+	defer cp.Destroy()
+	cp.SetFormat("flame")
+	if err = cp.Bake(&buf); err == nil {
+		t.Error("Expected an error baking without an input and target space")
 	}
-	cs.Destroy()
 }
 
 /*
@@ -955,6 +1933,44 @@ func TestContextResolveFileLocation(t *testing.T) {
 	if !strings.Contains(err.Error(), "could not be located") {
 		t.Fatalf("unxpected error: %v", err)
 	}
+	if !errors.Is(err, ErrMissingFile{}) {
+		t.Fatalf("expected error to be ErrMissingFile; got %T", err)
+	}
+	var missing ErrMissingFile
+	if !errors.As(err, &missing) || !strings.Contains(missing.Error(), "could not be located") {
+		t.Fatalf("expected errors.As to find the ErrMissingFile; got %v", missing)
+	}
+	var ocioErr *OCIOError
+	if !errors.As(err, &ocioErr) || ocioErr.Op != "Context.ResolveFileLocation" || ocioErr.Handle != "Context" {
+		t.Fatalf("expected an *OCIOError from Context.ResolveFileLocation; got %#v", ocioErr)
+	}
+	var locErr *FileLocationError
+	if !errors.As(err, &locErr) {
+		t.Fatalf("expected a *FileLocationError; got %T", err)
+	}
+	expectPaths := []string{"testdata/spi-vfx/missing.ocio"}
+	if !reflect.DeepEqual(locErr.Candidates, expectPaths) {
+		t.Errorf("expected candidates %v, got %v", expectPaths, locErr.Candidates)
+	}
+
+	c.SetWorkingDir("/show")
+	c.SetSearchPath("luts:$A/$B:/abs/luts")
+	expectPaths = []string{"/show/luts", "/show/testdata/spi-vfx", "/abs/luts"}
+	if actual := c.SearchPaths(); !reflect.DeepEqual(actual, expectPaths) {
+		t.Errorf("expected search paths %v, got %v", expectPaths, actual)
+	}
+
+	_, err = c.ResolveFileLocation("$C.spi1d")
+	if !errors.As(err, &locErr) {
+		t.Fatalf("expected a *FileLocationError; got %T", err)
+	}
+	expectPaths = []string{"/show/luts/missing.spi1d", "/show/testdata/spi-vfx/missing.spi1d", "/abs/luts/missing.spi1d"}
+	if !reflect.DeepEqual(locErr.Candidates, expectPaths) {
+		t.Errorf("expected candidates %v, got %v", expectPaths, locErr.Candidates)
+	}
+	if !strings.Contains(err.Error(), "/abs/luts/missing.spi1d") {
+		t.Errorf("expected the error message to list the candidates; got %v", err)
+	}
 }
 
 /*
@@ -985,6 +2001,102 @@ func TestPackedImageDesc(t *testing.T) {
 	imgDesc.Destroy()
 }
 
+func TestPackedImageDescStrided(t *testing.T) {
+	width, height, channels := 8, 6, 3
+	fb := getImageData(width, height, channels)
+
+	imgDesc, err := NewPackedImageDescStrided(fb, width, height, channels, AutoStride, AutoStride, AutoStride)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if imgDesc.ChanStrideBytes() != 4 {
+		t.Errorf("expected chanStrideBytes 4, but got %d", imgDesc.ChanStrideBytes())
+	}
+	if imgDesc.XStrideBytes() != channels*4 {
+		t.Errorf("expected xStrideBytes %d, but got %d", channels*4, imgDesc.XStrideBytes())
+	}
+	if imgDesc.YStrideBytes() != width*channels*4 {
+		t.Errorf("expected yStrideBytes %d, but got %d", width*channels*4, imgDesc.YStrideBytes())
+	}
+	imgDesc.Destroy()
+
+	for _, tt := range []struct {
+		name                         string
+		data                         ColorData
+		chanStride, xStride, yStride int
+	}{
+		{"short buffer", fb[:len(fb)-1], AutoStride, AutoStride, AutoStride},
+		{"large row stride", fb, AutoStride, AutoStride, (width*channels + 1) * 4},
+		{"large pixel stride", fb, AutoStride, (channels + 1) * 4, AutoStride},
+		{"negative stride", fb, AutoStride, AutoStride, -8},
+		{"unaligned row stride", fb, AutoStride, AutoStride, width*channels*4 - 2},
+		{"unaligned channel stride", fb, 2, AutoStride, AutoStride},
+		{"empty buffer", nil, AutoStride, AutoStride, AutoStride},
+	} {
+		if _, err = NewPackedImageDescStrided(tt.data, width, height, channels,
+			tt.chanStride, tt.xStride, tt.yStride); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestProcessorApplyWindow(t *testing.T) {
+	width, height, channels := 8, 6, 3
+	fb := getImageData(width, height, channels)
+	orig := make(ColorData, len(fb))
+	copy(orig, fb)
+
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	mtx := NewMatrixTransform()
+	defer mtx.Destroy()
+	mtx.SetMatrix([16]float32{
+		2, 0, 0, 0,
+		0, 2, 0, 0,
+		0, 0, 2, 0,
+		0, 0, 0, 1,
+	})
+	processor, err := cfg.ProcessorTransform(mtx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer processor.Destroy()
+
+	// Process a 3x2 window at (2, 1)
+	winX, winY, winW, winH := 2, 1, 3, 2
+	start := (winY*width + winX) * channels
+	imgDesc, err := NewPackedImageDescStrided(fb[start:], winW, winH, channels,
+		4, channels*4, width*channels*4)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer imgDesc.Destroy()
+
+	if err = processor.Apply(imgDesc); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			inside := x >= winX && x < winX+winW && y >= winY && y < winY+winH
+			for c := 0; c < channels; c++ {
+				i := (y*width+x)*channels + c
+				expect := orig[i]
+				if inside {
+					expect *= 2
+				}
+				if fb[i] != expect {
+					t.Fatalf("pixel (%d, %d) channel %d: expected %v, got %v", x, y, c, expect, fb[i])
+				}
+			}
+		}
+	}
+}
+
 func TestProcessorApply(t *testing.T) {
 	width, height, channels := 512, 256, 3
 	imgDesc, imageData := getGradImageDesc(width, height, channels)
@@ -1029,6 +2141,311 @@ func TestProcessorApply(t *testing.T) {
 	processor.Destroy()
 }
 
+func TestProcessorApplyContext(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	processor, err := cfg.Processor("scene_linear", "color_timing")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer processor.Destroy()
+
+	// Large enough to be processed in multiple bands
+	width, height, channels := 300, 500, 3
+	expectDesc, expect := getGradImageDesc(width, height, channels)
+	defer expectDesc.Destroy()
+	if err = processor.Apply(expectDesc); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	imgDesc, data := getGradImageDesc(width, height, channels)
+	defer imgDesc.Destroy()
+	if err = processor.ApplyContext(context.Background(), imgDesc); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(data, expect) {
+		t.Error("expected ApplyContext result to match Apply")
+	}
+
+	// Planar
+	size := width * height
+	r, g, b := make(ColorData, size), make(ColorData, size), make(ColorData, size)
+	grad := getImageData(width, height, channels)
+	for i := 0; i < size; i++ {
+		r[i], g[i], b[i] = grad[i*3], grad[i*3+1], grad[i*3+2]
+	}
+	planar, err := NewPlanarImageDesc(r, g, b, nil, width, height)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer planar.Destroy()
+	if err = processor.ApplyContext(context.Background(), planar); err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 0; i < size; i++ {
+		if r[i] != expect[i*3] || g[i] != expect[i*3+1] || b[i] != expect[i*3+2] {
+			t.Fatalf("pixel %d: expected planar ApplyContext result to match Apply", i)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	imgDesc2, data2 := getGradImageDesc(width, height, channels)
+	defer imgDesc2.Destroy()
+	if err = processor.ApplyContext(ctx, imgDesc2); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !reflect.DeepEqual(data2, getImageData(width, height, channels)) {
+		t.Error("expected image to be unmodified when cancelled before processing")
+	}
+}
+
+func TestProcessorApplyRGB(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	processor, err := cfg.Processor("scene_linear", "color_timing")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer processor.Destroy()
+
+	// Reference results, computed through an ImageDesc
+	data := ColorData{
+		0.1, 0.2, 0.3, 1.0,
+		0.4, 0.5, 0.6, 0.5,
+	}
+	imgDesc := NewPackedImageDesc(data, 2, 1, 4)
+	defer imgDesc.Destroy()
+	if err = processor.Apply(imgDesc); err != nil {
+		t.Fatal(err.Error())
+	}
+	expectRGBA := [][4]float32{
+		{data[0], data[1], data[2], data[3]},
+		{data[4], data[5], data[6], data[7]},
+	}
+
+	rgb, err := processor.ApplyRGB([3]float32{0.1, 0.2, 0.3})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if expect := [3]float32{data[0], data[1], data[2]}; rgb != expect {
+		t.Errorf("ApplyRGB: expected %v, got %v", expect, rgb)
+	}
+
+	rgba, err := processor.ApplyRGBA([4]float32{0.4, 0.5, 0.6, 0.5})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if rgba != expectRGBA[1] {
+		t.Errorf("ApplyRGBA: expected %v, got %v", expectRGBA[1], rgba)
+	}
+
+	pixels := [][4]float32{{0.1, 0.2, 0.3, 1.0}, {0.4, 0.5, 0.6, 0.5}}
+	if err = processor.ApplyRGBAPixels(pixels); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(pixels, expectRGBA) {
+		t.Errorf("ApplyRGBAPixels: expected %v, got %v", expectRGBA, pixels)
+	}
+
+	rgbPixels := [][3]float32{{0.1, 0.2, 0.3}, {0.4, 0.5, 0.6}}
+	if err = processor.ApplyRGBPixels(rgbPixels); err != nil {
+		t.Fatal(err.Error())
+	}
+	for i, px := range rgbPixels {
+		expect := [3]float32{expectRGBA[i][0], expectRGBA[i][1], expectRGBA[i][2]}
+		if px != expect {
+			t.Errorf("ApplyRGBPixels[%d]: expected %v, got %v", i, expect, px)
+		}
+	}
+
+	if err = processor.ApplyRGBPixels(nil); err != nil {
+		t.Errorf("expected nil error for empty pixels, got %v", err)
+	}
+}
+
+func TestPlanarImageDesc(t *testing.T) {
+	width, height := 16, 8
+	r := make(ColorData, width*height)
+	g := make(ColorData, width*height)
+	b := make(ColorData, width*height)
+
+	imgDesc, err := NewPlanarImageDesc(r, g, b, nil, width, height)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer imgDesc.Destroy()
+
+	if imgDesc.Width() != width {
+		t.Errorf("expected width %d, but got %d", width, imgDesc.Width())
+	}
+	if imgDesc.Height() != height {
+		t.Errorf("expected height %d, but got %d", height, imgDesc.Height())
+	}
+	if imgDesc.AData() != nil {
+		t.Errorf("expected nil alpha plane, got len %d", len(imgDesc.AData()))
+	}
+
+	short := make(ColorData, width*height-1)
+	for i, planes := range [][4]ColorData{
+		{short, g, b, nil},
+		{r, short, b, nil},
+		{r, g, short, nil},
+		{r, g, b, short},
+	} {
+		if _, err = NewPlanarImageDesc(planes[0], planes[1], planes[2], planes[3], width, height); err == nil {
+			t.Errorf("plane %d: expected an error for a plane smaller than width*height", i)
+		}
+	}
+	if _, err = NewPlanarImageDesc(r, g, b, nil, -width, -height); err == nil {
+		t.Error("expected an error for negative dimensions")
+	}
+}
+
+func TestProcessorApplyPlanar(t *testing.T) {
+	width, height, channels := 64, 32, 4
+	packedDesc, packed := getGradImageDesc(width, height, channels)
+	defer packedDesc.Destroy()
+
+	// Split the packed data into planes, before processing
+	size := width * height
+	r, g, b, a := make(ColorData, size), make(ColorData, size), make(ColorData, size), make(ColorData, size)
+	for i := 0; i < size; i++ {
+		r[i] = packed[i*channels]
+		g[i] = packed[i*channels+1]
+		b[i] = packed[i*channels+2]
+		a[i] = packed[i*channels+3]
+	}
+	planarDesc, err := NewPlanarImageDesc(r, g, b, a, width, height)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer planarDesc.Destroy()
+
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	processor, err := cfg.Processor("scene_linear", "color_timing")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer processor.Destroy()
+
+	if err = processor.Apply(packedDesc); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = processor.Apply(planarDesc); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for i := 0; i < size; i++ {
+		actual := [4]float32{r[i], g[i], b[i], a[i]}
+		expect := [4]float32{packed[i*channels], packed[i*channels+1], packed[i*channels+2], packed[i*channels+3]}
+		if actual != expect {
+			t.Fatalf("pixel %d: expected planar result %v to match packed result %v", i, actual, expect)
+		}
+	}
+}
+
+/*
+
+Processor GPU
+
+*/
+
+func TestGpuShaderDesc(t *testing.T) {
+	desc := NewGpuShaderDesc()
+	defer desc.Destroy()
+
+	id := desc.CacheID()
+
+	desc.SetLanguage(GPU_LANGUAGE_GLSL_1_3)
+	if actual := desc.Language(); actual != GPU_LANGUAGE_GLSL_1_3 {
+		t.Errorf("expected language %v, got %v", GPU_LANGUAGE_GLSL_1_3, actual)
+	}
+
+	desc.SetFunctionName("ocio_display")
+	if actual := desc.FunctionName(); actual != "ocio_display" {
+		t.Errorf("expected function name %q, got %q", "ocio_display", actual)
+	}
+
+	desc.SetLut3DEdgeLen(17)
+	if actual := desc.Lut3DEdgeLen(); actual != 17 {
+		t.Errorf("expected 3D LUT edge length 17, got %d", actual)
+	}
+
+	if desc.CacheID() == id {
+		t.Error("expected the CacheID to change with the GpuShaderDesc")
+	}
+}
+
+func TestProcessorGpu(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	proc, err := cfg.ProcessorFromNames("lnf", "lg10")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer proc.Destroy()
+
+	desc := NewGpuShaderDesc()
+	defer desc.Destroy()
+	desc.SetLanguage(GPU_LANGUAGE_GLSL_1_3)
+	desc.SetFunctionName("ocio_lnf_to_lg10")
+	desc.SetLut3DEdgeLen(8)
+
+	text, err := proc.GpuShaderText(desc)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !strings.Contains(text, "ocio_lnf_to_lg10") {
+		t.Errorf("expected the shader text to define the function; got:\n%s", text)
+	}
+
+	id, err := proc.GpuShaderCacheID(desc)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if id == "" {
+		t.Error("expected a GpuShaderCacheID")
+	}
+
+	lut, err := proc.GpuLut3D(desc)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if expect := 3 * 8 * 8 * 8; len(lut) != expect {
+		t.Errorf("expected a 3D LUT of %d values, got %d", expect, len(lut))
+	}
+	if _, err = proc.GpuLut3DCacheID(desc); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	desc.SetLanguage(GPU_LANGUAGE_CG)
+	id2, err := proc.GpuShaderCacheID(desc)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if id2 == id {
+		t.Error("expected a different GpuShaderCacheID for a different language")
+	}
+}
+
 /*
 
 Utility
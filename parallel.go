@@ -0,0 +1,112 @@
+package ocio
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// bandsPerWorker is the number of row bands created for each
+// worker by ApplyParallel, to balance uneven processing costs
+const bandsPerWorker = 4
+
+// applyParallelBandHook, if set, is called by ApplyParallel
+// after each band of rows is processed. It is used by tests.
+var applyParallelBandHook func(y, rows int)
+
+// ApplyParallel applies the Processor to an image, in place, splitting
+// it into bands of rows that are processed concurrently by a pool of
+// worker goroutines. If workers is <= 0, runtime.GOMAXPROCS(0) is used.
+//
+// OCIO Processors are thread-safe, and each worker applies the
+// Processor through its own handle, so that errors are reported
+// independently.
+//
+// Processing stops early, returning ctx.Err(), if the context is
+// cancelled. In that case the image may be partially processed. If
+// every band has already been processed, nil is returned.
+func (p *Processor) ApplyParallel(ctx context.Context, img *PackedImageDesc, workers int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	width, height := img.Width(), img.Height()
+	if width <= 0 || height <= 0 {
+		// Let OCIO report invalid dimensions
+		return p.Apply(img)
+	}
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	numBands := workers * bandsPerWorker
+	if numBands > height {
+		numBands = height
+	}
+	bandRows := (height + numBands - 1) / numBands
+	numBands = (height + bandRows - 1) / bandRows
+	if workers > numBands {
+		workers = numBands
+	}
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	bands := make(chan int)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			proc := p.share()
+			defer proc.Destroy()
+
+			for y := range bands {
+				rows := bandRows
+				if y+rows > height {
+					rows = height - y
+				}
+				band, err := img.band(y, rows)
+				if err == nil {
+					err = proc.Apply(band)
+					band.Destroy()
+				}
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+					cancel()
+				}
+				if applyParallelBandHook != nil {
+					applyParallelBandHook(y, rows)
+				}
+			}
+		}()
+	}
+
+	// Cancelling the context only fails the call if bands were skipped
+	skipped := false
+feed:
+	for y := 0; y < height; y += bandRows {
+		select {
+		case bands <- y:
+		case <-workCtx.Done():
+			skipped = true
+			break feed
+		}
+	}
+	close(bands)
+	wg.Wait()
+	runtime.KeepAlive(img)
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if skipped {
+		return ctx.Err()
+	}
+	return nil
+}
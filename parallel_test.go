@@ -0,0 +1,143 @@
+package ocio
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestProcessorApplyParallel(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	processor, err := cfg.Processor("scene_linear", "color_timing")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer processor.Destroy()
+
+	width, height, channels := 256, 101, 4
+	expectDesc, expect := getGradImageDesc(width, height, channels)
+	defer expectDesc.Destroy()
+	if err = processor.Apply(expectDesc); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, workers := range []int{0, 1, 3, 200} {
+		imgDesc, data := getGradImageDesc(width, height, channels)
+		if err = processor.ApplyParallel(context.Background(), imgDesc, workers); err != nil {
+			t.Fatalf("workers=%d: %v", workers, err)
+		}
+		if !reflect.DeepEqual(data, expect) {
+			t.Errorf("workers=%d: expected parallel result to match serial Apply", workers)
+		}
+		imgDesc.Destroy()
+	}
+}
+
+func TestProcessorApplyParallelWindow(t *testing.T) {
+	proc := getMatrixProcessor(t, 2, 0)
+	defer proc.Destroy()
+
+	width, height, channels := 16, 40, 3
+	fb := getImageData(width, height, channels)
+	orig := make(ColorData, len(fb))
+	copy(orig, fb)
+
+	// Process a 4x30 window at (5, 3)
+	winX, winY, winW, winH := 5, 3, 4, 30
+	start := (winY*width + winX) * channels
+	imgDesc, err := NewPackedImageDescStrided(fb[start:], winW, winH, channels,
+		4, channels*4, width*channels*4)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer imgDesc.Destroy()
+
+	if err = proc.ApplyParallel(context.Background(), imgDesc, 4); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			inside := x >= winX && x < winX+winW && y >= winY && y < winY+winH
+			for c := 0; c < channels; c++ {
+				i := (y*width+x)*channels + c
+				expect := orig[i]
+				if inside {
+					expect *= 2
+				}
+				if fb[i] != expect {
+					t.Fatalf("pixel (%d, %d) channel %d: expected %v, got %v", x, y, c, expect, fb[i])
+				}
+			}
+		}
+	}
+}
+
+func TestProcessorApplyParallelCancel(t *testing.T) {
+	proc := getMatrixProcessor(t, 2, 0)
+	defer proc.Destroy()
+
+	imgDesc, data := getGradImageDesc(64, 64, 3)
+	defer imgDesc.Destroy()
+	orig := make(ColorData, len(data))
+	copy(orig, data)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := proc.ApplyParallel(ctx, imgDesc, 4); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !reflect.DeepEqual(data, orig) {
+		t.Error("expected image to be unmodified when cancelled before processing")
+	}
+}
+
+func TestProcessorApplyParallelLateCancel(t *testing.T) {
+	proc := getMatrixProcessor(t, 2, 0)
+	defer proc.Destroy()
+
+	imgDesc, data := getGradImageDesc(64, 64, 3)
+	defer imgDesc.Destroy()
+	orig := make(ColorData, len(data))
+	copy(orig, data)
+
+	// The last band is fed last, so once it is processed
+	// every band has been fed and the cancellation is not an error
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	applyParallelBandHook = func(y, rows int) {
+		if y+rows == 64 {
+			cancel()
+		}
+	}
+	defer func() { applyParallelBandHook = nil }()
+
+	if err := proc.ApplyParallel(ctx, imgDesc, 4); err != nil {
+		t.Fatalf("expected no error for a fully processed image, got %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("expected the context to be cancelled while processing")
+	}
+	for i := range data {
+		if data[i] != orig[i]*2 {
+			t.Fatalf("value %d: expected %v, got %v", i, orig[i]*2, data[i])
+		}
+	}
+}
+
+func TestProcessorApplyParallelError(t *testing.T) {
+	proc := getMatrixProcessor(t, 2, 0)
+	defer proc.Destroy()
+
+	imgDesc := NewPackedImageDesc([]float32{0, 0, 0}, 0, 0, 1)
+	defer imgDesc.Destroy()
+	if err := proc.ApplyParallel(context.Background(), imgDesc, 2); err == nil {
+		t.Error("expected an error for an empty image")
+	}
+}
@@ -10,6 +10,7 @@ namespace ocigo {
 
 IndexMap<OCIO::ProcessorRcPtr> g_Processor_map;
 IndexMap<OCIO::ProcessorMetadataRcPtr> g_ProcessorMetadata_map;
+IndexMap<OCIO::GpuShaderDescRcPtr> g_GpuShaderDesc_map;
 
 }
 
@@ -34,6 +35,13 @@ extern "C" {
         return p;
     }
 
+    // Create a new handle to the same underlying Processor,
+    // with its own error context
+    ProcessorId Processor_share(ProcessorId p) {
+        return NEW_HANDLE_CONTEXT(ocigo::g_Processor_map.add(
+                ocigo::g_Processor_map.get(p->handle)));
+    }
+
     bool Processor_isNoOp(ProcessorId p) {
         bool ret = false;
         BEGIN_CATCH_CTX_ERR(p)
@@ -58,6 +66,24 @@ extern "C" {
         END_CATCH_CTX_ERR(p)
     }
 
+    void Processor_applyRGB(ProcessorId p, float* pixels, long numPixels) {
+        BEGIN_CATCH_CTX_ERR(p)
+        OCIO::ConstProcessorRcPtr proc = ocigo::g_Processor_map.get(p->handle);
+        for (long i = 0; i < numPixels; ++i) {
+            proc->applyRGB(pixels + i*3);
+        }
+        END_CATCH_CTX_ERR(p)
+    }
+
+    void Processor_applyRGBA(ProcessorId p, float* pixels, long numPixels) {
+        BEGIN_CATCH_CTX_ERR(p)
+        OCIO::ConstProcessorRcPtr proc = ocigo::g_Processor_map.get(p->handle);
+        for (long i = 0; i < numPixels; ++i) {
+            proc->applyRGBA(pixels + i*4);
+        }
+        END_CATCH_CTX_ERR(p)
+    }
+
     const char* Processor_getCpuCacheID(ProcessorId p) {
         const char* ret = NULL;
         BEGIN_CATCH_CTX_ERR(p)
@@ -66,6 +92,104 @@ extern "C" {
         return ret;
     }
 
+    // Processor GPU
+    const char* Processor_getGpuShaderText(ProcessorId p, GpuShaderDescId shaderDesc) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        OCIO::ConstGpuShaderDescRcPtr desc = ocigo::g_GpuShaderDesc_map.get(shaderDesc);
+        ret = ocigo::g_Processor_map.get(p->handle).get()->getGpuShaderText(*desc);
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    const char* Processor_getGpuShaderTextCacheID(ProcessorId p, GpuShaderDescId shaderDesc) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        OCIO::ConstGpuShaderDescRcPtr desc = ocigo::g_GpuShaderDesc_map.get(shaderDesc);
+        ret = ocigo::g_Processor_map.get(p->handle).get()->getGpuShaderTextCacheID(*desc);
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    void Processor_getGpuLut3D(ProcessorId p, float* lut3d, GpuShaderDescId shaderDesc) {
+        BEGIN_CATCH_CTX_ERR(p)
+        OCIO::ConstGpuShaderDescRcPtr desc = ocigo::g_GpuShaderDesc_map.get(shaderDesc);
+        ocigo::g_Processor_map.get(p->handle).get()->getGpuLut3D(lut3d, *desc);
+        END_CATCH_CTX_ERR(p)
+    }
+
+    const char* Processor_getGpuLut3DCacheID(ProcessorId p, GpuShaderDescId shaderDesc) {
+        const char* ret = NULL;
+        BEGIN_CATCH_CTX_ERR(p)
+        OCIO::ConstGpuShaderDescRcPtr desc = ocigo::g_GpuShaderDesc_map.get(shaderDesc);
+        ret = ocigo::g_Processor_map.get(p->handle).get()->getGpuLut3DCacheID(*desc);
+        END_CATCH_CTX_ERR(p)
+        return ret;
+    }
+
+    // GpuShaderDesc
+    void deleteGpuShaderDesc(GpuShaderDescId p) {
+        ocigo::g_GpuShaderDesc_map.remove(p);
+    }
+
+    GpuShaderDescId GpuShaderDesc_Create() {
+        OCIO::GpuShaderDescRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = OCIO::GpuShaderDescRcPtr(new OCIO::GpuShaderDesc());
+        END_CATCH_ERR
+        return ocigo::g_GpuShaderDesc_map.add(ptr);
+    }
+
+    GpuLanguage GpuShaderDesc_getLanguage(GpuShaderDescId p) {
+        GpuLanguage ret = GPU_LANGUAGE_UNKNOWN;
+        BEGIN_CATCH_ERR
+        ret = (GpuLanguage)ocigo::g_GpuShaderDesc_map.get(p).get()->getLanguage();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void GpuShaderDesc_setLanguage(GpuShaderDescId p, GpuLanguage lang) {
+        BEGIN_CATCH_ERR
+        ocigo::g_GpuShaderDesc_map.get(p).get()->setLanguage((OCIO::GpuLanguage)lang);
+        END_CATCH_ERR
+    }
+
+    const char* GpuShaderDesc_getFunctionName(GpuShaderDescId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = ocigo::g_GpuShaderDesc_map.get(p).get()->getFunctionName();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void GpuShaderDesc_setFunctionName(GpuShaderDescId p, const char* name) {
+        BEGIN_CATCH_ERR
+        ocigo::g_GpuShaderDesc_map.get(p).get()->setFunctionName(name);
+        END_CATCH_ERR
+    }
+
+    int GpuShaderDesc_getLut3DEdgeLen(GpuShaderDescId p) {
+        int ret = 0;
+        BEGIN_CATCH_ERR
+        ret = ocigo::g_GpuShaderDesc_map.get(p).get()->getLut3DEdgeLen();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void GpuShaderDesc_setLut3DEdgeLen(GpuShaderDescId p, int len) {
+        BEGIN_CATCH_ERR
+        ocigo::g_GpuShaderDesc_map.get(p).get()->setLut3DEdgeLen(len);
+        END_CATCH_ERR
+    }
+
+    const char* GpuShaderDesc_getCacheID(GpuShaderDescId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = ocigo::g_GpuShaderDesc_map.get(p).get()->getCacheID();
+        END_CATCH_ERR
+        return ret;
+    }
+
     // ProcessorMetadata
     void deleteProcessorMetadata(ProcessorMetadataId p) {
         ocigo::g_ProcessorMetadata_map.remove(p);
@@ -145,6 +269,22 @@ extern "C" {
         return ret;
     }
 
+    // Map the AUTO_STRIDE sentinel of the Go API to OCIO::AutoStride
+    static ptrdiff_t toStride(long strideBytes) {
+        return strideBytes == AUTO_STRIDE ? OCIO::AutoStride : (ptrdiff_t)strideBytes;
+    }
+
+    PackedImageDesc* PackedImageDesc_CreateStrided(float* data, long width, long height, long numChannels,
+                                                   long chanStrideBytes, long xStrideBytes, long yStrideBytes) {
+        PackedImageDesc* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = (PackedImageDesc*) new OCIO::PackedImageDesc(data, width, height, numChannels,
+                                                           toStride(chanStrideBytes), toStride(xStrideBytes),
+                                                           toStride(yStrideBytes));
+        END_CATCH_ERR
+        return ret;
+    }
+
     float* PackedImageDesc_getData(PackedImageDesc *p) {
         float* ret = NULL;
         BEGIN_CATCH_ERR
@@ -177,4 +317,58 @@ extern "C" {
         return ret;
     }
 
-}
\ No newline at end of file
+    long PackedImageDesc_getChanStrideBytes(PackedImageDesc *p) {
+        long ret = 0;
+        BEGIN_CATCH_ERR
+        ret = static_cast<OCIO::PackedImageDesc*>(p)->getChanStrideBytes();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    long PackedImageDesc_getXStrideBytes(PackedImageDesc *p) {
+        long ret = 0;
+        BEGIN_CATCH_ERR
+        ret = static_cast<OCIO::PackedImageDesc*>(p)->getXStrideBytes();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    long PackedImageDesc_getYStrideBytes(PackedImageDesc *p) {
+        long ret = 0;
+        BEGIN_CATCH_ERR
+        ret = static_cast<OCIO::PackedImageDesc*>(p)->getYStrideBytes();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void deletePlanarImageDesc(PlanarImageDesc* p) {
+        if (p != NULL) {
+            delete (OCIO::PlanarImageDesc*)p;
+        }
+    }
+
+    PlanarImageDesc* PlanarImageDesc_Create(float* rData, float* gData, float* bData, float* aData, long width, long height) {
+        PlanarImageDesc* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = (PlanarImageDesc*) new OCIO::PlanarImageDesc(rData, gData, bData, aData, width, height);
+        END_CATCH_ERR
+        return ret;
+    }
+
+    long PlanarImageDesc_getWidth(PlanarImageDesc *p) {
+        long ret = 0;
+        BEGIN_CATCH_ERR
+        ret = static_cast<OCIO::PlanarImageDesc*>(p)->getWidth();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    long PlanarImageDesc_getHeight(PlanarImageDesc *p) {
+        long ret = 0;
+        BEGIN_CATCH_ERR
+        ret = static_cast<OCIO::PlanarImageDesc*>(p)->getHeight();
+        END_CATCH_ERR
+        return ret;
+    }
+
+}
@@ -7,6 +7,9 @@ package ocio
 import "C"
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"runtime"
 	"unsafe"
 )
@@ -40,11 +43,20 @@ func NewProcessor() *Processor {
 	return newProcessor(C.Processor_Create())
 }
 
-func (p *Processor) lastError(errno ...error) error {
+// share returns a new Processor handle to the same underlying
+// OCIO Processor. Each handle has its own error context, so
+// shared handles can be used safely from separate goroutines.
+func (p *Processor) share() *Processor {
+	ret := newProcessor(C.Processor_share(p.ptr))
+	runtime.KeepAlive(p)
+	return ret
+}
+
+func (p *Processor) lastError(op string, errno ...error) error {
 	if p == nil {
 		return nil
 	}
-	err := getLastError(p.ptr, errno...)
+	err := getLastError(op, "Processor", p.ptr, errno...)
 	runtime.KeepAlive(p)
 	return err
 }
@@ -79,21 +91,258 @@ func (p *Processor) Metadata() *ProcessorMetadata {
 // Apply to an image.
 func (p *Processor) Apply(i ImageDescriptor) error {
 	_, err := C.Processor_apply(p.ptr, unsafe.Pointer(i.imageDescPtr()))
-	err = p.lastError(err)
+	err = p.lastError("Processor.Apply", err)
 	runtime.KeepAlive(p)
 	runtime.KeepAlive(i)
 	return err
 }
 
+// ApplyContext is like Apply, but processes a *PackedImageDesc or
+// *PlanarImageDesc in bands of rows, returning ctx.Err() between bands
+// if the context has been cancelled. In that case the image may be
+// partially processed. Other ImageDescriptor types are applied in one call.
+func (p *Processor) ApplyContext(ctx context.Context, img ImageDescriptor) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	banded, ok := img.(bandedImageDesc)
+	if !ok {
+		return p.Apply(img)
+	}
+	width, height := banded.Width(), banded.Height()
+	if width <= 0 || height <= 0 {
+		// Let OCIO report invalid dimensions
+		return p.Apply(img)
+	}
+
+	rows := bufferChunkPixels / width
+	if rows < 1 {
+		rows = 1
+	}
+	for y := 0; y < height; y += rows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := rows
+		if y+n > height {
+			n = height - y
+		}
+		band, err := banded.band(y, n)
+		if err != nil {
+			return err
+		}
+		err = p.Apply(band)
+		band.Destroy()
+		if err != nil {
+			return err
+		}
+	}
+	runtime.KeepAlive(img)
+	return nil
+}
+
+// ApplyRGB applies the processor to a single RGB pixel,
+// returning the transformed value.
+// This avoids the overhead of creating an ImageDescriptor.
+func (p *Processor) ApplyRGB(rgb [3]float32) ([3]float32, error) {
+	_, err := C.Processor_applyRGB(p.ptr, (*C.float)(&rgb[0]), 1)
+	err = p.lastError("Processor.ApplyRGB", err)
+	runtime.KeepAlive(p)
+	return rgb, err
+}
+
+// ApplyRGBA applies the processor to a single RGBA pixel,
+// returning the transformed value.
+// This avoids the overhead of creating an ImageDescriptor.
+func (p *Processor) ApplyRGBA(rgba [4]float32) ([4]float32, error) {
+	_, err := C.Processor_applyRGBA(p.ptr, (*C.float)(&rgba[0]), 1)
+	err = p.lastError("Processor.ApplyRGBA", err)
+	runtime.KeepAlive(p)
+	return rgba, err
+}
+
+// ApplyRGBPixels applies the processor, in place,
+// to each RGB pixel in the slice
+func (p *Processor) ApplyRGBPixels(pixels [][3]float32) error {
+	if len(pixels) == 0 {
+		return nil
+	}
+	_, err := C.Processor_applyRGB(p.ptr, (*C.float)(&pixels[0][0]), C.long(len(pixels)))
+	err = p.lastError("Processor.ApplyRGBPixels", err)
+	runtime.KeepAlive(p)
+	runtime.KeepAlive(pixels)
+	return err
+}
+
+// ApplyRGBAPixels applies the processor, in place,
+// to each RGBA pixel in the slice
+func (p *Processor) ApplyRGBAPixels(pixels [][4]float32) error {
+	if len(pixels) == 0 {
+		return nil
+	}
+	_, err := C.Processor_applyRGBA(p.ptr, (*C.float)(&pixels[0][0]), C.long(len(pixels)))
+	err = p.lastError("Processor.ApplyRGBAPixels", err)
+	runtime.KeepAlive(p)
+	runtime.KeepAlive(pixels)
+	return err
+}
+
 func (p *Processor) CpuCacheID() (string, error) {
 	id, err := C.Processor_getCpuCacheID(p.ptr)
-	if err = p.lastError(err); err != nil {
+	if err = p.lastError("Processor.CpuCacheID", err); err != nil {
+		return "", err
+	}
+	runtime.KeepAlive(p)
+	return C.GoString(id), nil
+}
+
+/* Processor GPU */
+
+// GpuShaderText returns the source of a shader function, in the language
+// of the GpuShaderDesc, that applies the Processor using a 3D LUT texture.
+// The text can be generated without a GPU or graphics context.
+func (p *Processor) GpuShaderText(desc *GpuShaderDesc) (string, error) {
+	text, err := C.Processor_getGpuShaderText(p.ptr, desc.ptr)
+	if err = p.lastError("Processor.GpuShaderText", err); err != nil {
+		return "", err
+	}
+	runtime.KeepAlive(p)
+	runtime.KeepAlive(desc)
+	return C.GoString(text), nil
+}
+
+// GpuShaderCacheID returns a cache id for the shader text
+// generated by GpuShaderText for the GpuShaderDesc
+func (p *Processor) GpuShaderCacheID(desc *GpuShaderDesc) (string, error) {
+	id, err := C.Processor_getGpuShaderTextCacheID(p.ptr, desc.ptr)
+	if err = p.lastError("Processor.GpuShaderCacheID", err); err != nil {
+		return "", err
+	}
+	runtime.KeepAlive(p)
+	runtime.KeepAlive(desc)
+	return C.GoString(id), nil
+}
+
+/*
+GpuLut3D returns the 3D LUT to upload as the texture sampled by the shader
+from GpuShaderText. It holds edge*edge*edge RGB values, where edge is the
+Lut3DEdgeLen of the GpuShaderDesc, with red changing fastest:
+
+	lut[3*(r + edge*(g + edge*b))]
+*/
+func (p *Processor) GpuLut3D(desc *GpuShaderDesc) ([]float32, error) {
+	edge := desc.Lut3DEdgeLen()
+	if edge <= 0 {
+		return nil, fmt.Errorf("invalid GpuShaderDesc 3D LUT edge length: %d", edge)
+	}
+	lut := make([]float32, 3*edge*edge*edge)
+	_, err := C.Processor_getGpuLut3D(p.ptr, (*C.float)(&lut[0]), desc.ptr)
+	if err = p.lastError("Processor.GpuLut3D", err); err != nil {
+		return nil, err
+	}
+	runtime.KeepAlive(p)
+	runtime.KeepAlive(desc)
+	return lut, nil
+}
+
+// GpuLut3DCacheID returns a cache id for the 3D LUT returned by
+// GpuLut3D for the GpuShaderDesc. If the Processor does not need a
+// 3D LUT, the id is "<NULL>", and the LUT upload can be skipped.
+func (p *Processor) GpuLut3DCacheID(desc *GpuShaderDesc) (string, error) {
+	id, err := C.Processor_getGpuLut3DCacheID(p.ptr, desc.ptr)
+	if err = p.lastError("Processor.GpuLut3DCacheID", err); err != nil {
 		return "", err
 	}
 	runtime.KeepAlive(p)
+	runtime.KeepAlive(desc)
 	return C.GoString(id), nil
 }
 
+/* GpuShaderDesc */
+
+// GpuShaderDesc describes the shader generated by
+// Processor.GpuShaderText and the 3D LUT from Processor.GpuLut3D
+type GpuShaderDesc struct {
+	ptr C.GpuShaderDescId
+}
+
+func newGpuShaderDesc(p C.GpuShaderDescId) *GpuShaderDesc {
+	desc := &GpuShaderDesc{p}
+	runtime.SetFinalizer(desc, deleteGpuShaderDesc)
+	return desc
+}
+
+func deleteGpuShaderDesc(p *GpuShaderDesc) {
+	if p == nil {
+		return
+	}
+	if p.ptr != 0 {
+		runtime.SetFinalizer(p, nil)
+		C.deleteGpuShaderDesc(p.ptr)
+		p.ptr = 0
+	}
+	runtime.KeepAlive(p)
+}
+
+// Create a new GpuShaderDesc, with an unknown language,
+// and the default function name and 3D LUT edge length
+func NewGpuShaderDesc() *GpuShaderDesc {
+	return newGpuShaderDesc(C.GpuShaderDesc_Create())
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (p *GpuShaderDesc) Destroy() {
+	deleteGpuShaderDesc(p)
+}
+
+func (p *GpuShaderDesc) Language() GpuLanguage {
+	ret := GpuLanguage(C.GpuShaderDesc_getLanguage(p.ptr))
+	runtime.KeepAlive(p)
+	return ret
+}
+
+// Set the shader language to be generated
+func (p *GpuShaderDesc) SetLanguage(lang GpuLanguage) {
+	C.GpuShaderDesc_setLanguage(p.ptr, C.GpuLanguage(lang))
+	runtime.KeepAlive(p)
+}
+
+func (p *GpuShaderDesc) FunctionName() string {
+	ret := C.GoString(C.GpuShaderDesc_getFunctionName(p.ptr))
+	runtime.KeepAlive(p)
+	return ret
+}
+
+// Set the name of the generated shader function
+func (p *GpuShaderDesc) SetFunctionName(name string) {
+	c_str := C.CString(name)
+	defer C.free(unsafe.Pointer(c_str))
+	C.GpuShaderDesc_setFunctionName(p.ptr, c_str)
+	runtime.KeepAlive(p)
+}
+
+func (p *GpuShaderDesc) Lut3DEdgeLen() int {
+	ret := int(C.GpuShaderDesc_getLut3DEdgeLen(p.ptr))
+	runtime.KeepAlive(p)
+	return ret
+}
+
+// Set the edge length of the 3D LUT used to
+// approximate the Processor on the GPU
+func (p *GpuShaderDesc) SetLut3DEdgeLen(edgeLen int) {
+	C.GpuShaderDesc_setLut3DEdgeLen(p.ptr, C.int(edgeLen))
+	runtime.KeepAlive(p)
+}
+
+func (p *GpuShaderDesc) CacheID() string {
+	ret := C.GoString(C.GpuShaderDesc_getCacheID(p.ptr))
+	runtime.KeepAlive(p)
+	return ret
+}
+
 // This class contains meta information about the process that generated this processor.
 // The results of these functions do not impact the pixel processing.
 type ProcessorMetadata struct {
@@ -225,6 +474,69 @@ func NewPackedImageDesc(rgb ColorData, width, height, numChannels int) *PackedIm
 	return newPackedImageDesc(ptr, rgb)
 }
 
+// AutoStride may be passed as any of the stride arguments to
+// NewPackedImageDescStrided, to have it computed from the
+// image dimensions and number of channels
+const AutoStride = C.AUTO_STRIDE
+
+// Create a PackedImageDesc wrapper for some raw rgb data, using
+// custom strides (in bytes) between channels, pixels and rows.
+//
+// This allows processing a window into a larger framebuffer, without
+// copying, by passing a slice starting at the first pixel of the window,
+// the window dimensions, and the strides of the full framebuffer:
+//
+//	// Process the w*h window at (x, y) in a fbWidth wide RGB framebuffer
+//	start := (y*fbWidth + x) * 3
+//	desc, err := NewPackedImageDescStrided(fb[start:], w, h, 3, 4, 3*4, fbWidth*3*4)
+//
+// An error is returned if the strides are negative or not a multiple
+// of 4 bytes (the size of a float32), or if the last pixel of the
+// image lies outside of rgb.
+func NewPackedImageDescStrided(rgb ColorData, width, height, numChannels,
+	chanStrideBytes, xStrideBytes, yStrideBytes int) (*PackedImageDesc, error) {
+
+	if width < 0 || height < 0 || numChannels < 0 {
+		return nil, fmt.Errorf("image dimensions and number of channels must not be negative, got %dx%d with %d channels",
+			width, height, numChannels)
+	}
+
+	// Resolve the strides as OCIO does, to find the extent of the image
+	chanStride, xStride, yStride := chanStrideBytes, xStrideBytes, yStrideBytes
+	if chanStride == AutoStride {
+		chanStride = 4
+	}
+	if xStride == AutoStride {
+		xStride = chanStride * numChannels
+	}
+	if yStride == AutoStride {
+		yStride = xStride * width
+	}
+	if chanStride < 0 || xStride < 0 || yStride < 0 {
+		return nil, fmt.Errorf("strides must not be negative, got %d, %d and %d bytes",
+			chanStrideBytes, xStrideBytes, yStrideBytes)
+	}
+	if chanStride%4 != 0 || xStride%4 != 0 || yStride%4 != 0 {
+		return nil, fmt.Errorf("strides must be a multiple of 4 bytes, got %d, %d and %d bytes",
+			chanStride, xStride, yStride)
+	}
+	if width > 0 && height > 0 && numChannels > 0 {
+		need := (height-1)*yStride + (width-1)*xStride + (numChannels-1)*chanStride + 4
+		if size := len(rgb) * 4; size < need {
+			return nil, fmt.Errorf("buffer of %d bytes is too small for a %dx%d image with %d channels and strides of %d, %d and %d bytes (need %d)",
+				size, width, height, numChannels, chanStride, xStride, yStride, need)
+		}
+	}
+	if len(rgb) == 0 {
+		return nil, errors.New("cannot create a PackedImageDesc for an empty buffer")
+	}
+
+	ptr := C.PackedImageDesc_CreateStrided((*C.float)(&rgb[0]),
+		C.long(width), C.long(height), C.long(numChannels),
+		C.long(chanStrideBytes), C.long(xStrideBytes), C.long(yStrideBytes))
+	return newPackedImageDesc(ptr, rgb), nil
+}
+
 // Destroy immediately frees resources for this
 // instance instead of waiting for garbage collection
 // finalizer to run at some point later
@@ -260,6 +572,169 @@ func (p *PackedImageDesc) NumChannels() int {
 	return ret
 }
 
+// Number of bytes between channels of a pixel
+func (p *PackedImageDesc) ChanStrideBytes() int {
+	ret := int(C.PackedImageDesc_getChanStrideBytes(p.ptr))
+	runtime.KeepAlive(p)
+	return ret
+}
+
+// Number of bytes between consecutive pixels in a row
+func (p *PackedImageDesc) XStrideBytes() int {
+	ret := int(C.PackedImageDesc_getXStrideBytes(p.ptr))
+	runtime.KeepAlive(p)
+	return ret
+}
+
+// Number of bytes between consecutive rows
+func (p *PackedImageDesc) YStrideBytes() int {
+	ret := int(C.PackedImageDesc_getYStrideBytes(p.ptr))
+	runtime.KeepAlive(p)
+	return ret
+}
+
 func (p *PackedImageDesc) imageDescPtr() unsafe.Pointer {
 	return p.ptr
 }
+
+// bandedImageDesc is an ImageDescriptor that can be split
+// into bands of rows, sharing the same pixel data
+type bandedImageDesc interface {
+	ImageDescriptor
+	Width() int
+	Height() int
+	Destroy()
+	band(y, rows int) (bandedImageDesc, error)
+}
+
+// band returns a PackedImageDesc for the rows [y, y+rows)
+// of the image, sharing its data
+func (p *PackedImageDesc) band(y, rows int) (bandedImageDesc, error) {
+	yStride := p.YStrideBytes()
+	desc, err := NewPackedImageDescStrided(p.data[y*yStride/4:], p.Width(), rows, p.NumChannels(),
+		p.ChanStrideBytes(), p.XStrideBytes(), yStride)
+	if err != nil {
+		return nil, err
+	}
+	return desc, nil
+}
+
+// PlanarImageDesc is a light-weight wrapper around an image stored as
+// separate channel planes, that provides a context for pixel access
+type PlanarImageDesc struct {
+	ptr        unsafe.Pointer
+	r, g, b, a ColorData
+}
+
+func newPlanarImageDesc(p unsafe.Pointer, r, g, b, a ColorData) *PlanarImageDesc {
+	i := &PlanarImageDesc{p, r, g, b, a}
+	runtime.SetFinalizer(i, deletePlanarImageDesc)
+	return i
+}
+
+func deletePlanarImageDesc(p *PlanarImageDesc) {
+	if p == nil {
+		return
+	}
+	if p.ptr != nil {
+		runtime.SetFinalizer(p, nil)
+		C.deletePlanarImageDesc(p.ptr)
+		p.ptr = nil
+	}
+	runtime.KeepAlive(p)
+}
+
+// Create a PlanarImageDesc wrapper for separate r, g, b and
+// optional a planes of image data. Each plane must hold at least
+// width*height values, or an error is returned. The alpha plane may be nil.
+func NewPlanarImageDesc(r, g, b, a ColorData, width, height int) (*PlanarImageDesc, error) {
+	if width < 0 || height < 0 {
+		return nil, fmt.Errorf("image dimensions must not be negative, got %dx%d", width, height)
+	}
+	need := width * height
+	for i, plane := range [4]ColorData{r, g, b, a} {
+		if i == 3 && plane == nil {
+			break
+		}
+		if len(plane) < need {
+			return nil, fmt.Errorf("%s plane of size %d is too small for a %dx%d image (need %d)",
+				[4]string{"red", "green", "blue", "alpha"}[i], len(plane), width, height, need)
+		}
+	}
+
+	ptr := C.PlanarImageDesc_Create(
+		planePtr(r), planePtr(g), planePtr(b), planePtr(a),
+		C.long(width), C.long(height))
+	return newPlanarImageDesc(ptr, r, g, b, a), nil
+}
+
+func planePtr(data ColorData) *C.float {
+	if len(data) == 0 {
+		return nil
+	}
+	return (*C.float)(&data[0])
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (p *PlanarImageDesc) Destroy() {
+	deletePlanarImageDesc(p)
+}
+
+// Return the current state of the red plane,
+// potentially modified if color transformations
+// have been applied.
+func (p *PlanarImageDesc) RData() ColorData {
+	return p.r
+}
+
+// Return the current state of the green plane
+func (p *PlanarImageDesc) GData() ColorData {
+	return p.g
+}
+
+// Return the current state of the blue plane
+func (p *PlanarImageDesc) BData() ColorData {
+	return p.b
+}
+
+// Return the current state of the alpha plane,
+// or nil if no alpha plane was given
+func (p *PlanarImageDesc) AData() ColorData {
+	return p.a
+}
+
+// Pixel width of the image
+func (p *PlanarImageDesc) Width() int {
+	ret := int(C.PlanarImageDesc_getWidth(p.ptr))
+	runtime.KeepAlive(p)
+	return ret
+}
+
+// Pixel height of the image
+func (p *PlanarImageDesc) Height() int {
+	ret := int(C.PlanarImageDesc_getHeight(p.ptr))
+	runtime.KeepAlive(p)
+	return ret
+}
+
+func (p *PlanarImageDesc) imageDescPtr() unsafe.Pointer {
+	return p.ptr
+}
+
+// band returns a PlanarImageDesc for the rows [y, y+rows)
+// of the image, sharing its planes
+func (p *PlanarImageDesc) band(y, rows int) (bandedImageDesc, error) {
+	width := p.Width()
+	offset := y * width
+	a := p.a
+	if len(a) > 0 {
+		a = a[offset:]
+	}
+	desc, err := NewPlanarImageDesc(p.r[offset:], p.g[offset:], p.b[offset:], a, width, rows)
+	if err != nil {
+		return nil, err
+	}
+	return desc, nil
+}
@@ -8,6 +8,7 @@ namespace ocigo {
 
 extern IndexMap<OCIO_NAMESPACE::ProcessorRcPtr> g_Processor_map;
 extern IndexMap<OCIO_NAMESPACE::ProcessorMetadataRcPtr> g_ProcessorMetadata_map;
+extern IndexMap<OCIO_NAMESPACE::GpuShaderDescRcPtr> g_GpuShaderDesc_map;
 
 } // ocigo
 
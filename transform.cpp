@@ -14,6 +14,71 @@ IndexMap<OCIO::TransformRcPtr> g_Transform_map;
 
 extern "C" {
 
+    void deleteTransform(TransformId p) {
+        ocigo::g_Transform_map.remove(p);
+    }
+
+    int FileTransform_getNumFormats() {
+        int ret = 0;
+        BEGIN_CATCH_ERR
+        ret = OCIO::FileTransform::getNumFormats();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    const char* FileTransform_getFormatNameByIndex(int index) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO::FileTransform::getFormatNameByIndex(index);
+        END_CATCH_ERR
+        return ret;
+    }
+
+    const char* FileTransform_getFormatExtensionByIndex(int index) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO::FileTransform::getFormatExtensionByIndex(index);
+        END_CATCH_ERR
+        return ret;
+    }
+
+    TransformType Transform_getType(TransformId p) {
+        OCIO::ConstTransformRcPtr ptr = ocigo::g_Transform_map.get(p);
+        if (ptr == NULL) { return TRANSFORM_TYPE_UNKNOWN; }
+
+        if (OCIO_DYNAMIC_POINTER_CAST<const OCIO::AllocationTransform>(ptr)) {
+            return TRANSFORM_TYPE_ALLOCATION;
+        }
+        if (OCIO_DYNAMIC_POINTER_CAST<const OCIO::CDLTransform>(ptr)) {
+            return TRANSFORM_TYPE_CDL;
+        }
+        if (OCIO_DYNAMIC_POINTER_CAST<const OCIO::ColorSpaceTransform>(ptr)) {
+            return TRANSFORM_TYPE_COLORSPACE;
+        }
+        if (OCIO_DYNAMIC_POINTER_CAST<const OCIO::DisplayTransform>(ptr)) {
+            return TRANSFORM_TYPE_DISPLAY;
+        }
+        if (OCIO_DYNAMIC_POINTER_CAST<const OCIO::ExponentTransform>(ptr)) {
+            return TRANSFORM_TYPE_EXPONENT;
+        }
+        if (OCIO_DYNAMIC_POINTER_CAST<const OCIO::FileTransform>(ptr)) {
+            return TRANSFORM_TYPE_FILE;
+        }
+        if (OCIO_DYNAMIC_POINTER_CAST<const OCIO::GroupTransform>(ptr)) {
+            return TRANSFORM_TYPE_GROUP;
+        }
+        if (OCIO_DYNAMIC_POINTER_CAST<const OCIO::LogTransform>(ptr)) {
+            return TRANSFORM_TYPE_LOG;
+        }
+        if (OCIO_DYNAMIC_POINTER_CAST<const OCIO::LookTransform>(ptr)) {
+            return TRANSFORM_TYPE_LOOK;
+        }
+        if (OCIO_DYNAMIC_POINTER_CAST<const OCIO::MatrixTransform>(ptr)) {
+            return TRANSFORM_TYPE_MATRIX;
+        }
+        return TRANSFORM_TYPE_UNKNOWN;
+    }
+
     void deleteDisplayTransform(DisplayTransformId p) {
         ocigo::g_Transform_map.remove(p);
     }
@@ -130,4 +195,757 @@ extern "C" {
        END_CATCH_ERR
     }
 
+    // LookTransform
+    void deleteLookTransform(LookTransformId p) {
+        ocigo::g_Transform_map.remove(p);
+    }
+
+    LookTransformId LookTransform_Create() {
+        OCIO::LookTransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = OCIO::LookTransform::Create();
+        END_CATCH_ERR
+        return ocigo::g_Transform_map.add(OCIO_DYNAMIC_POINTER_CAST<OCIO::Transform>(ptr));
+    }
+
+    LookTransformId LookTransform_createEditableCopy(LookTransformId p) {
+        OCIO::TransformRcPtr tptr;
+        BEGIN_CATCH_ERR
+        tptr = ocigo::g_Transform_map.get(p).get()->createEditableCopy();
+        END_CATCH_ERR
+        if ( tptr == NULL) { return 0; }
+
+        return ocigo::g_Transform_map.add(tptr);
+    }
+
+    TransformDirection LookTransform_getDirection(LookTransformId p) {
+        TransformDirection ret;
+        BEGIN_CATCH_ERR
+        ret = (TransformDirection)(ocigo::g_Transform_map.get(p).get()->getDirection());
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void LookTransform_setDirection(LookTransformId p, TransformDirection dir) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Transform_map.get(p).get()->setDirection((OCIO::TransformDirection)dir);
+        END_CATCH_ERR
+    }
+
+    const char* LookTransform_getSrc(LookTransformId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::LookTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getSrc();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void LookTransform_setSrc(LookTransformId p, const char* src) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::LookTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setSrc(src);
+       END_CATCH_ERR
+    }
+
+    const char* LookTransform_getDst(LookTransformId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::LookTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getDst();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void LookTransform_setDst(LookTransformId p, const char* dst) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::LookTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setDst(dst);
+       END_CATCH_ERR
+    }
+
+    const char* LookTransform_getLooks(LookTransformId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::LookTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getLooks();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void LookTransform_setLooks(LookTransformId p, const char* looks) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::LookTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setLooks(looks);
+       END_CATCH_ERR
+    }
+
+    // AllocationTransform
+    void deleteAllocationTransform(AllocationTransformId p) {
+        ocigo::g_Transform_map.remove(p);
+    }
+
+    AllocationTransformId AllocationTransform_Create() {
+        OCIO::AllocationTransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = OCIO::AllocationTransform::Create();
+        END_CATCH_ERR
+        return ocigo::g_Transform_map.add(OCIO_DYNAMIC_POINTER_CAST<OCIO::Transform>(ptr));
+    }
+
+    AllocationTransformId AllocationTransform_createEditableCopy(AllocationTransformId p) {
+        OCIO::TransformRcPtr tptr;
+        BEGIN_CATCH_ERR
+        tptr = ocigo::g_Transform_map.get(p).get()->createEditableCopy();
+        END_CATCH_ERR
+        if ( tptr == NULL) { return 0; }
+
+        return ocigo::g_Transform_map.add(tptr);
+    }
+
+    TransformDirection AllocationTransform_getDirection(AllocationTransformId p) {
+        TransformDirection ret;
+        BEGIN_CATCH_ERR
+        ret = (TransformDirection)(ocigo::g_Transform_map.get(p).get()->getDirection());
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void AllocationTransform_setDirection(AllocationTransformId p, TransformDirection dir) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Transform_map.get(p).get()->setDirection((OCIO::TransformDirection)dir);
+        END_CATCH_ERR
+    }
+
+    Allocation AllocationTransform_getAllocation(AllocationTransformId p) {
+        Allocation ret = ALLOCATION_UNKNOWN;
+        BEGIN_CATCH_ERR
+        ret = (Allocation)(OCIO_DYNAMIC_POINTER_CAST<OCIO::AllocationTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getAllocation());
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void AllocationTransform_setAllocation(AllocationTransformId p, Allocation allocation) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::AllocationTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setAllocation((OCIO::Allocation)allocation);
+       END_CATCH_ERR
+    }
+
+    int AllocationTransform_getNumVars(AllocationTransformId p) {
+        int ret = 0;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::AllocationTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getNumVars();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void AllocationTransform_getVars(AllocationTransformId p, float* vars) {
+        BEGIN_CATCH_ERR
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::AllocationTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getVars(vars);
+        END_CATCH_ERR
+    }
+
+    void AllocationTransform_setVars(AllocationTransformId p, int numvars, const float* vars) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::AllocationTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setVars(numvars, vars);
+       END_CATCH_ERR
+    }
+
+    // CDLTransform
+    void deleteCDLTransform(CDLTransformId p) {
+        ocigo::g_Transform_map.remove(p);
+    }
+
+    CDLTransformId CDLTransform_Create() {
+        OCIO::CDLTransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = OCIO::CDLTransform::Create();
+        END_CATCH_ERR
+        return ocigo::g_Transform_map.add(OCIO_DYNAMIC_POINTER_CAST<OCIO::Transform>(ptr));
+    }
+
+    CDLTransformId CDLTransform_createEditableCopy(CDLTransformId p) {
+        OCIO::TransformRcPtr tptr;
+        BEGIN_CATCH_ERR
+        tptr = ocigo::g_Transform_map.get(p).get()->createEditableCopy();
+        END_CATCH_ERR
+        if ( tptr == NULL) { return 0; }
+
+        return ocigo::g_Transform_map.add(tptr);
+    }
+
+    TransformDirection CDLTransform_getDirection(CDLTransformId p) {
+        TransformDirection ret;
+        BEGIN_CATCH_ERR
+        ret = (TransformDirection)(ocigo::g_Transform_map.get(p).get()->getDirection());
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void CDLTransform_setDirection(CDLTransformId p, TransformDirection dir) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Transform_map.get(p).get()->setDirection((OCIO::TransformDirection)dir);
+        END_CATCH_ERR
+    }
+
+    void CDLTransform_getSlope(CDLTransformId p, float* rgb) {
+        BEGIN_CATCH_ERR
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getSlope(rgb);
+        END_CATCH_ERR
+    }
+
+    void CDLTransform_setSlope(CDLTransformId p, const float* rgb) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setSlope(rgb);
+       END_CATCH_ERR
+    }
+
+    void CDLTransform_getOffset(CDLTransformId p, float* rgb) {
+        BEGIN_CATCH_ERR
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getOffset(rgb);
+        END_CATCH_ERR
+    }
+
+    void CDLTransform_setOffset(CDLTransformId p, const float* rgb) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setOffset(rgb);
+       END_CATCH_ERR
+    }
+
+    void CDLTransform_getPower(CDLTransformId p, float* rgb) {
+        BEGIN_CATCH_ERR
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getPower(rgb);
+        END_CATCH_ERR
+    }
+
+    void CDLTransform_setPower(CDLTransformId p, const float* rgb) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setPower(rgb);
+       END_CATCH_ERR
+    }
+
+    float CDLTransform_getSat(CDLTransformId p) {
+        float ret = 0;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getSat();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void CDLTransform_setSat(CDLTransformId p, float sat) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setSat(sat);
+       END_CATCH_ERR
+    }
+
+    void CDLTransform_getSatLumaCoefs(CDLTransformId p, float* rgb) {
+        BEGIN_CATCH_ERR
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getSatLumaCoefs(rgb);
+        END_CATCH_ERR
+    }
+
+    const char* CDLTransform_getID(CDLTransformId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getID();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void CDLTransform_setID(CDLTransformId p, const char* id) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setID(id);
+       END_CATCH_ERR
+    }
+
+    const char* CDLTransform_getDescription(CDLTransformId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getDescription();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void CDLTransform_setDescription(CDLTransformId p, const char* desc) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setDescription(desc);
+       END_CATCH_ERR
+    }
+
+    const char* CDLTransform_getXML(CDLTransformId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getXML();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    _HandleContext* CDLTransform_setXML(CDLTransformId p, const char* xml) {
+        _HandleContext* ctx = NEW_HANDLE_CONTEXT();
+        BEGIN_CATCH_CTX_ERR(ctx)
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+                .get()->setXML(xml);
+        END_CATCH_CTX_ERR(ctx)
+        return ctx;
+    }
+
+    bool CDLTransform_equals(CDLTransformId p, CDLTransformId other) {
+        bool ret = false;
+        OCIO::ConstCDLTransformRcPtr other_ptr =
+                OCIO_DYNAMIC_POINTER_CAST<const OCIO::CDLTransform>(ocigo::g_Transform_map.get(other));
+        if (other_ptr == NULL) { return false; }
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::CDLTransform>(ocigo::g_Transform_map.get(p))
+                .get()->equals(other_ptr);
+        END_CATCH_ERR
+        return ret;
+    }
+
+    _HandleContext* CDLTransform_CreateFromFile(const char* src, const char* cccid) {
+        _HandleContext* ctx = NEW_HANDLE_CONTEXT();
+        BEGIN_CATCH_CTX_ERR(ctx)
+        ctx->handle = ocigo::g_Transform_map.add(OCIO_DYNAMIC_POINTER_CAST<OCIO::Transform>(
+                OCIO::CDLTransform::CreateFromFile(src, cccid)));
+        END_CATCH_CTX_ERR(ctx)
+        return ctx;
+    }
+
+    // ColorSpaceTransform
+    void deleteColorSpaceTransform(ColorSpaceTransformId p) {
+        ocigo::g_Transform_map.remove(p);
+    }
+
+    ColorSpaceTransformId ColorSpaceTransform_Create() {
+        OCIO::ColorSpaceTransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = OCIO::ColorSpaceTransform::Create();
+        END_CATCH_ERR
+        return ocigo::g_Transform_map.add(OCIO_DYNAMIC_POINTER_CAST<OCIO::Transform>(ptr));
+    }
+
+    ColorSpaceTransformId ColorSpaceTransform_createEditableCopy(ColorSpaceTransformId p) {
+        OCIO::TransformRcPtr tptr;
+        BEGIN_CATCH_ERR
+        tptr = ocigo::g_Transform_map.get(p).get()->createEditableCopy();
+        END_CATCH_ERR
+        if ( tptr == NULL) { return 0; }
+
+        return ocigo::g_Transform_map.add(tptr);
+    }
+
+    TransformDirection ColorSpaceTransform_getDirection(ColorSpaceTransformId p) {
+        TransformDirection ret;
+        BEGIN_CATCH_ERR
+        ret = (TransformDirection)(ocigo::g_Transform_map.get(p).get()->getDirection());
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void ColorSpaceTransform_setDirection(ColorSpaceTransformId p, TransformDirection dir) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Transform_map.get(p).get()->setDirection((OCIO::TransformDirection)dir);
+        END_CATCH_ERR
+    }
+
+    const char* ColorSpaceTransform_getSrc(ColorSpaceTransformId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::ColorSpaceTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getSrc();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void ColorSpaceTransform_setSrc(ColorSpaceTransformId p, const char* src) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::ColorSpaceTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setSrc(src);
+       END_CATCH_ERR
+    }
+
+    const char* ColorSpaceTransform_getDst(ColorSpaceTransformId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::ColorSpaceTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getDst();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void ColorSpaceTransform_setDst(ColorSpaceTransformId p, const char* dst) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::ColorSpaceTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setDst(dst);
+       END_CATCH_ERR
+    }
+
+    // ExponentTransform
+    void deleteExponentTransform(ExponentTransformId p) {
+        ocigo::g_Transform_map.remove(p);
+    }
+
+    ExponentTransformId ExponentTransform_Create() {
+        OCIO::ExponentTransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = OCIO::ExponentTransform::Create();
+        END_CATCH_ERR
+        return ocigo::g_Transform_map.add(OCIO_DYNAMIC_POINTER_CAST<OCIO::Transform>(ptr));
+    }
+
+    ExponentTransformId ExponentTransform_createEditableCopy(ExponentTransformId p) {
+        OCIO::TransformRcPtr tptr;
+        BEGIN_CATCH_ERR
+        tptr = ocigo::g_Transform_map.get(p).get()->createEditableCopy();
+        END_CATCH_ERR
+        if ( tptr == NULL) { return 0; }
+
+        return ocigo::g_Transform_map.add(tptr);
+    }
+
+    TransformDirection ExponentTransform_getDirection(ExponentTransformId p) {
+        TransformDirection ret;
+        BEGIN_CATCH_ERR
+        ret = (TransformDirection)(ocigo::g_Transform_map.get(p).get()->getDirection());
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void ExponentTransform_setDirection(ExponentTransformId p, TransformDirection dir) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Transform_map.get(p).get()->setDirection((OCIO::TransformDirection)dir);
+        END_CATCH_ERR
+    }
+
+    void ExponentTransform_getValue(ExponentTransformId p, float* vec4) {
+        BEGIN_CATCH_ERR
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::ExponentTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getValue(vec4);
+        END_CATCH_ERR
+    }
+
+    void ExponentTransform_setValue(ExponentTransformId p, const float* vec4) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::ExponentTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setValue(vec4);
+       END_CATCH_ERR
+    }
+
+    // FileTransform
+    void deleteFileTransform(FileTransformId p) {
+        ocigo::g_Transform_map.remove(p);
+    }
+
+    FileTransformId FileTransform_Create() {
+        OCIO::FileTransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = OCIO::FileTransform::Create();
+        END_CATCH_ERR
+        return ocigo::g_Transform_map.add(OCIO_DYNAMIC_POINTER_CAST<OCIO::Transform>(ptr));
+    }
+
+    FileTransformId FileTransform_createEditableCopy(FileTransformId p) {
+        OCIO::TransformRcPtr tptr;
+        BEGIN_CATCH_ERR
+        tptr = ocigo::g_Transform_map.get(p).get()->createEditableCopy();
+        END_CATCH_ERR
+        if ( tptr == NULL) { return 0; }
+
+        return ocigo::g_Transform_map.add(tptr);
+    }
+
+    TransformDirection FileTransform_getDirection(FileTransformId p) {
+        TransformDirection ret;
+        BEGIN_CATCH_ERR
+        ret = (TransformDirection)(ocigo::g_Transform_map.get(p).get()->getDirection());
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void FileTransform_setDirection(FileTransformId p, TransformDirection dir) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Transform_map.get(p).get()->setDirection((OCIO::TransformDirection)dir);
+        END_CATCH_ERR
+    }
+
+    const char* FileTransform_getSrc(FileTransformId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::FileTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getSrc();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void FileTransform_setSrc(FileTransformId p, const char* src) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::FileTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setSrc(src);
+       END_CATCH_ERR
+    }
+
+    const char* FileTransform_getCCCId(FileTransformId p) {
+        const char* ret = NULL;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::FileTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getCCCId();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void FileTransform_setCCCId(FileTransformId p, const char* id) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::FileTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setCCCId(id);
+       END_CATCH_ERR
+    }
+
+    Interpolation FileTransform_getInterpolation(FileTransformId p) {
+        Interpolation ret = INTERP_UNKNOWN;
+        BEGIN_CATCH_ERR
+        ret = (Interpolation)(OCIO_DYNAMIC_POINTER_CAST<OCIO::FileTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getInterpolation());
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void FileTransform_setInterpolation(FileTransformId p, Interpolation interp) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::FileTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setInterpolation((OCIO::Interpolation)interp);
+       END_CATCH_ERR
+    }
+
+    // GroupTransform
+    void deleteGroupTransform(GroupTransformId p) {
+        ocigo::g_Transform_map.remove(p);
+    }
+
+    GroupTransformId GroupTransform_Create() {
+        OCIO::GroupTransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = OCIO::GroupTransform::Create();
+        END_CATCH_ERR
+        return ocigo::g_Transform_map.add(OCIO_DYNAMIC_POINTER_CAST<OCIO::Transform>(ptr));
+    }
+
+    GroupTransformId GroupTransform_createEditableCopy(GroupTransformId p) {
+        OCIO::TransformRcPtr tptr;
+        BEGIN_CATCH_ERR
+        tptr = ocigo::g_Transform_map.get(p).get()->createEditableCopy();
+        END_CATCH_ERR
+        if ( tptr == NULL) { return 0; }
+
+        return ocigo::g_Transform_map.add(tptr);
+    }
+
+    TransformDirection GroupTransform_getDirection(GroupTransformId p) {
+        TransformDirection ret;
+        BEGIN_CATCH_ERR
+        ret = (TransformDirection)(ocigo::g_Transform_map.get(p).get()->getDirection());
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void GroupTransform_setDirection(GroupTransformId p, TransformDirection dir) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Transform_map.get(p).get()->setDirection((OCIO::TransformDirection)dir);
+        END_CATCH_ERR
+    }
+
+    TransformId GroupTransform_getTransform(GroupTransformId p, int index) {
+        OCIO::TransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        OCIO::ConstTransformRcPtr tx = OCIO_DYNAMIC_POINTER_CAST<OCIO::GroupTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getTransform(index);
+        if (tx) { ptr = tx->createEditableCopy(); }
+        END_CATCH_ERR
+        if ( ptr == NULL) { return 0; }
+        return ocigo::g_Transform_map.add(ptr);
+    }
+
+    int GroupTransform_size(GroupTransformId p) {
+        int ret = 0;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::GroupTransform>(ocigo::g_Transform_map.get(p))
+                .get()->size();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void GroupTransform_push_back(GroupTransformId p, TransformId tx) {
+        OCIO::ConstTransformRcPtr tx_ptr = ocigo::g_Transform_map.get(tx);
+        if (tx_ptr == NULL) { return; }
+        BEGIN_CATCH_ERR
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::GroupTransform>(ocigo::g_Transform_map.get(p))
+                .get()->push_back(tx_ptr);
+        END_CATCH_ERR
+    }
+
+    void GroupTransform_clear(GroupTransformId p) {
+        BEGIN_CATCH_ERR
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::GroupTransform>(ocigo::g_Transform_map.get(p))
+                .get()->clear();
+        END_CATCH_ERR
+    }
+
+    bool GroupTransform_empty(GroupTransformId p) {
+        bool ret = true;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::GroupTransform>(ocigo::g_Transform_map.get(p))
+                .get()->empty();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    // LogTransform
+    void deleteLogTransform(LogTransformId p) {
+        ocigo::g_Transform_map.remove(p);
+    }
+
+    LogTransformId LogTransform_Create() {
+        OCIO::LogTransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = OCIO::LogTransform::Create();
+        END_CATCH_ERR
+        return ocigo::g_Transform_map.add(OCIO_DYNAMIC_POINTER_CAST<OCIO::Transform>(ptr));
+    }
+
+    LogTransformId LogTransform_createEditableCopy(LogTransformId p) {
+        OCIO::TransformRcPtr tptr;
+        BEGIN_CATCH_ERR
+        tptr = ocigo::g_Transform_map.get(p).get()->createEditableCopy();
+        END_CATCH_ERR
+        if ( tptr == NULL) { return 0; }
+
+        return ocigo::g_Transform_map.add(tptr);
+    }
+
+    TransformDirection LogTransform_getDirection(LogTransformId p) {
+        TransformDirection ret;
+        BEGIN_CATCH_ERR
+        ret = (TransformDirection)(ocigo::g_Transform_map.get(p).get()->getDirection());
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void LogTransform_setDirection(LogTransformId p, TransformDirection dir) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Transform_map.get(p).get()->setDirection((OCIO::TransformDirection)dir);
+        END_CATCH_ERR
+    }
+
+    float LogTransform_getBase(LogTransformId p) {
+        float ret = 0;
+        BEGIN_CATCH_ERR
+        ret = OCIO_DYNAMIC_POINTER_CAST<OCIO::LogTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getBase();
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void LogTransform_setBase(LogTransformId p, float base) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::LogTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setBase(base);
+       END_CATCH_ERR
+    }
+
+    // MatrixTransform
+    void deleteMatrixTransform(MatrixTransformId p) {
+        ocigo::g_Transform_map.remove(p);
+    }
+
+    MatrixTransformId MatrixTransform_Create() {
+        OCIO::MatrixTransformRcPtr ptr;
+        BEGIN_CATCH_ERR
+        ptr = OCIO::MatrixTransform::Create();
+        END_CATCH_ERR
+        return ocigo::g_Transform_map.add(OCIO_DYNAMIC_POINTER_CAST<OCIO::Transform>(ptr));
+    }
+
+    MatrixTransformId MatrixTransform_createEditableCopy(MatrixTransformId p) {
+        OCIO::TransformRcPtr tptr;
+        BEGIN_CATCH_ERR
+        tptr = ocigo::g_Transform_map.get(p).get()->createEditableCopy();
+        END_CATCH_ERR
+        if ( tptr == NULL) { return 0; }
+
+        return ocigo::g_Transform_map.add(tptr);
+    }
+
+    TransformDirection MatrixTransform_getDirection(MatrixTransformId p) {
+        TransformDirection ret;
+        BEGIN_CATCH_ERR
+        ret = (TransformDirection)(ocigo::g_Transform_map.get(p).get()->getDirection());
+        END_CATCH_ERR
+        return ret;
+    }
+
+    void MatrixTransform_setDirection(MatrixTransformId p, TransformDirection dir) {
+        BEGIN_CATCH_ERR
+        ocigo::g_Transform_map.get(p).get()->setDirection((OCIO::TransformDirection)dir);
+        END_CATCH_ERR
+    }
+
+    void MatrixTransform_getValue(MatrixTransformId p, float* m44, float* offset4) {
+        BEGIN_CATCH_ERR
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::MatrixTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getValue(m44, offset4);
+        END_CATCH_ERR
+    }
+
+    void MatrixTransform_setValue(MatrixTransformId p, const float* m44, const float* offset4) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::MatrixTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setValue(m44, offset4);
+       END_CATCH_ERR
+    }
+
+    void MatrixTransform_getMatrix(MatrixTransformId p, float* m44) {
+        BEGIN_CATCH_ERR
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::MatrixTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getMatrix(m44);
+        END_CATCH_ERR
+    }
+
+    void MatrixTransform_setMatrix(MatrixTransformId p, const float* m44) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::MatrixTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setMatrix(m44);
+       END_CATCH_ERR
+    }
+
+    void MatrixTransform_getOffset(MatrixTransformId p, float* offset4) {
+        BEGIN_CATCH_ERR
+        OCIO_DYNAMIC_POINTER_CAST<OCIO::MatrixTransform>(ocigo::g_Transform_map.get(p))
+                .get()->getOffset(offset4);
+        END_CATCH_ERR
+    }
+
+    void MatrixTransform_setOffset(MatrixTransformId p, const float* offset4) {
+       BEGIN_CATCH_ERR
+       OCIO_DYNAMIC_POINTER_CAST<OCIO::MatrixTransform>(ocigo::g_Transform_map.get(p))
+               .get()->setOffset(offset4);
+       END_CATCH_ERR
+    }
+
 }
\ No newline at end of file
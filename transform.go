@@ -8,7 +8,12 @@ package ocio
 import "C"
 
 import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"unsafe"
 )
 
@@ -26,6 +31,49 @@ type Transform interface {
 	transformHandle() C.HandleId
 }
 
+// newTransform wraps a transform handle returned from the C API
+// in the concrete Go type matching the underlying OCIO transform.
+// A zero handle returns a nil Transform.
+func newTransform(p C.TransformId) (Transform, error) {
+	if p == 0 {
+		return nil, nil
+	}
+	switch typ := C.Transform_getType(p); typ {
+	case C.TRANSFORM_TYPE_ALLOCATION:
+		return newAllocationTransform(p), nil
+	case C.TRANSFORM_TYPE_CDL:
+		return newCDLTransform(p), nil
+	case C.TRANSFORM_TYPE_COLORSPACE:
+		return newColorSpaceTransform(p), nil
+	case C.TRANSFORM_TYPE_DISPLAY:
+		return newDisplayTransform(p), nil
+	case C.TRANSFORM_TYPE_EXPONENT:
+		return newExponentTransform(p), nil
+	case C.TRANSFORM_TYPE_FILE:
+		return newFileTransform(p), nil
+	case C.TRANSFORM_TYPE_GROUP:
+		return newGroupTransform(p), nil
+	case C.TRANSFORM_TYPE_LOG:
+		return newLogTransform(p), nil
+	case C.TRANSFORM_TYPE_LOOK:
+		return newLookTransform(p), nil
+	case C.TRANSFORM_TYPE_MATRIX:
+		return newMatrixTransform(p), nil
+	default:
+		C.deleteTransform(p)
+		return nil, fmt.Errorf("unsupported transform type: %d", int(typ))
+	}
+}
+
+// transformHandleOrZero returns the handle of a Transform,
+// or a zero handle if the Transform is nil or a nil pointer
+func transformHandleOrZero(tx Transform) C.HandleId {
+	if tx == nil {
+		return 0
+	}
+	return tx.transformHandle()
+}
+
 type DisplayTransform struct {
 	ptr C.DisplayTransformId
 }
@@ -61,6 +109,9 @@ func (tx *DisplayTransform) Destroy() {
 }
 
 func (tx *DisplayTransform) transformHandle() C.HandleId {
+	if tx == nil {
+		return 0
+	}
 	return tx.ptr
 }
 
@@ -174,3 +225,1025 @@ func (tx *DisplayTransform) SetLooksOverrideEnabled(enabled bool) {
 	C.DisplayTransform_setLooksOverrideEnabled(tx.ptr, C.bool(enabled))
 	runtime.KeepAlive(tx)
 }
+
+// LookTransform applies one or more looks between a source
+// and destination color space.
+type LookTransform struct {
+	ptr C.LookTransformId
+}
+
+func newLookTransform(p C.LookTransformId) *LookTransform {
+	tx := &LookTransform{p}
+	runtime.SetFinalizer(tx, deleteLookTransform)
+	return tx
+}
+
+func deleteLookTransform(tx *LookTransform) {
+	if tx == nil {
+		return
+	}
+	if tx.ptr != 0 {
+		runtime.SetFinalizer(tx, nil)
+		C.deleteLookTransform(tx.ptr)
+		tx.ptr = 0
+	}
+	runtime.KeepAlive(tx)
+}
+
+// Create a new empty LookTransform
+func NewLookTransform() *LookTransform {
+	return newLookTransform(C.LookTransform_Create())
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (tx *LookTransform) Destroy() {
+	deleteLookTransform(tx)
+}
+
+func (tx *LookTransform) transformHandle() C.HandleId {
+	if tx == nil {
+		return 0
+	}
+	return tx.ptr
+}
+
+// Create a new editable copy of this LookTransform
+func (tx *LookTransform) EditableCopy() *LookTransform {
+	cpy := newLookTransform(C.LookTransform_createEditableCopy(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cpy
+}
+
+func (tx *LookTransform) Direction() TransformDirection {
+	dir := TransformDirection(C.LookTransform_getDirection(tx.ptr))
+	runtime.KeepAlive(tx)
+	return dir
+}
+
+func (tx *LookTransform) SetDirection(dir TransformDirection) {
+	C.LookTransform_setDirection(tx.ptr, C.TransformDirection(dir))
+	runtime.KeepAlive(tx)
+}
+
+// Src returns the source color space
+func (tx *LookTransform) Src() string {
+	cs := C.GoString(C.LookTransform_getSrc(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cs
+}
+
+// SetSrc sets the source color space
+func (tx *LookTransform) SetSrc(cs string) {
+	c_str := C.CString(cs)
+	defer C.free(unsafe.Pointer(c_str))
+	C.LookTransform_setSrc(tx.ptr, c_str)
+	runtime.KeepAlive(tx)
+}
+
+// Dst returns the destination color space
+func (tx *LookTransform) Dst() string {
+	cs := C.GoString(C.LookTransform_getDst(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cs
+}
+
+// SetDst sets the destination color space
+func (tx *LookTransform) SetDst(cs string) {
+	c_str := C.CString(cs)
+	defer C.free(unsafe.Pointer(c_str))
+	C.LookTransform_setDst(tx.ptr, c_str)
+	runtime.KeepAlive(tx)
+}
+
+func (tx *LookTransform) Looks() string {
+	looks := C.GoString(C.LookTransform_getLooks(tx.ptr))
+	runtime.KeepAlive(tx)
+	return looks
+}
+
+// SetLooks specifies the looks to apply.
+// Looks is a potentially comma (or colon) delimited list of lookNames,
+// where +/- prefixes are optionally allowed to denote forward/inverse
+// look specification (And forward is assumed in the absence of either).
+func (tx *LookTransform) SetLooks(looks string) {
+	c_str := C.CString(looks)
+	defer C.free(unsafe.Pointer(c_str))
+	C.LookTransform_setLooks(tx.ptr, c_str)
+	runtime.KeepAlive(tx)
+}
+
+// AllocationTransform is used to define the range of values a
+// ColorSpace occupies, for purposes of approximating the transform
+// (such as when baking LUTs or building the GPU path).
+type AllocationTransform struct {
+	ptr C.AllocationTransformId
+}
+
+func newAllocationTransform(p C.AllocationTransformId) *AllocationTransform {
+	tx := &AllocationTransform{p}
+	runtime.SetFinalizer(tx, deleteAllocationTransform)
+	return tx
+}
+
+func deleteAllocationTransform(tx *AllocationTransform) {
+	if tx == nil {
+		return
+	}
+	if tx.ptr != 0 {
+		runtime.SetFinalizer(tx, nil)
+		C.deleteAllocationTransform(tx.ptr)
+		tx.ptr = 0
+	}
+	runtime.KeepAlive(tx)
+}
+
+// Create a new empty AllocationTransform
+func NewAllocationTransform() *AllocationTransform {
+	return newAllocationTransform(C.AllocationTransform_Create())
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (tx *AllocationTransform) Destroy() {
+	deleteAllocationTransform(tx)
+}
+
+func (tx *AllocationTransform) transformHandle() C.HandleId {
+	if tx == nil {
+		return 0
+	}
+	return tx.ptr
+}
+
+// Create a new editable copy of this AllocationTransform
+func (tx *AllocationTransform) EditableCopy() *AllocationTransform {
+	cpy := newAllocationTransform(C.AllocationTransform_createEditableCopy(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cpy
+}
+
+func (tx *AllocationTransform) Direction() TransformDirection {
+	dir := TransformDirection(C.AllocationTransform_getDirection(tx.ptr))
+	runtime.KeepAlive(tx)
+	return dir
+}
+
+func (tx *AllocationTransform) SetDirection(dir TransformDirection) {
+	C.AllocationTransform_setDirection(tx.ptr, C.TransformDirection(dir))
+	runtime.KeepAlive(tx)
+}
+
+func (tx *AllocationTransform) Allocation() Allocation {
+	ret := Allocation(C.AllocationTransform_getAllocation(tx.ptr))
+	runtime.KeepAlive(tx)
+	return ret
+}
+
+func (tx *AllocationTransform) SetAllocation(allocation Allocation) {
+	C.AllocationTransform_setAllocation(tx.ptr, C.Allocation(allocation))
+	runtime.KeepAlive(tx)
+}
+
+// Vars returns the allocation variables, such as
+// the [min, max] range of the allocation
+func (tx *AllocationTransform) Vars() []float32 {
+	num := int(C.AllocationTransform_getNumVars(tx.ptr))
+	vars := make([]float32, num)
+	if num > 0 {
+		C.AllocationTransform_getVars(tx.ptr, (*C.float)(&vars[0]))
+	}
+	runtime.KeepAlive(tx)
+	return vars
+}
+
+// SetVars sets the allocation variables.
+// Passing an empty slice clears the variables.
+func (tx *AllocationTransform) SetVars(vars []float32) {
+	var ptr *C.float
+	if len(vars) > 0 {
+		ptr = (*C.float)(&vars[0])
+	}
+	C.AllocationTransform_setVars(tx.ptr, C.int(len(vars)), ptr)
+	runtime.KeepAlive(tx)
+	runtime.KeepAlive(vars)
+}
+
+// CDLTransform applies an ASC Color Decision List
+// (slope, offset, power and saturation) correction.
+type CDLTransform struct {
+	ptr C.CDLTransformId
+}
+
+func newCDLTransform(p C.CDLTransformId) *CDLTransform {
+	tx := &CDLTransform{p}
+	runtime.SetFinalizer(tx, deleteCDLTransform)
+	return tx
+}
+
+func deleteCDLTransform(tx *CDLTransform) {
+	if tx == nil {
+		return
+	}
+	if tx.ptr != 0 {
+		runtime.SetFinalizer(tx, nil)
+		C.deleteCDLTransform(tx.ptr)
+		tx.ptr = 0
+	}
+	runtime.KeepAlive(tx)
+}
+
+// Create a new empty CDLTransform
+func NewCDLTransform() *CDLTransform {
+	return newCDLTransform(C.CDLTransform_Create())
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (tx *CDLTransform) Destroy() {
+	deleteCDLTransform(tx)
+}
+
+func (tx *CDLTransform) transformHandle() C.HandleId {
+	if tx == nil {
+		return 0
+	}
+	return tx.ptr
+}
+
+// Create a new editable copy of this CDLTransform
+func (tx *CDLTransform) EditableCopy() *CDLTransform {
+	cpy := newCDLTransform(C.CDLTransform_createEditableCopy(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cpy
+}
+
+func (tx *CDLTransform) Direction() TransformDirection {
+	dir := TransformDirection(C.CDLTransform_getDirection(tx.ptr))
+	runtime.KeepAlive(tx)
+	return dir
+}
+
+func (tx *CDLTransform) SetDirection(dir TransformDirection) {
+	C.CDLTransform_setDirection(tx.ptr, C.TransformDirection(dir))
+	runtime.KeepAlive(tx)
+}
+
+func (tx *CDLTransform) Slope() [3]float32 {
+	var rgb [3]float32
+	C.CDLTransform_getSlope(tx.ptr, (*C.float)(&rgb[0]))
+	runtime.KeepAlive(tx)
+	return rgb
+}
+
+func (tx *CDLTransform) SetSlope(rgb [3]float32) {
+	C.CDLTransform_setSlope(tx.ptr, (*C.float)(&rgb[0]))
+	runtime.KeepAlive(tx)
+}
+
+func (tx *CDLTransform) Offset() [3]float32 {
+	var rgb [3]float32
+	C.CDLTransform_getOffset(tx.ptr, (*C.float)(&rgb[0]))
+	runtime.KeepAlive(tx)
+	return rgb
+}
+
+func (tx *CDLTransform) SetOffset(rgb [3]float32) {
+	C.CDLTransform_setOffset(tx.ptr, (*C.float)(&rgb[0]))
+	runtime.KeepAlive(tx)
+}
+
+func (tx *CDLTransform) Power() [3]float32 {
+	var rgb [3]float32
+	C.CDLTransform_getPower(tx.ptr, (*C.float)(&rgb[0]))
+	runtime.KeepAlive(tx)
+	return rgb
+}
+
+func (tx *CDLTransform) SetPower(rgb [3]float32) {
+	C.CDLTransform_setPower(tx.ptr, (*C.float)(&rgb[0]))
+	runtime.KeepAlive(tx)
+}
+
+func (tx *CDLTransform) Sat() float32 {
+	sat := float32(C.CDLTransform_getSat(tx.ptr))
+	runtime.KeepAlive(tx)
+	return sat
+}
+
+func (tx *CDLTransform) SetSat(sat float32) {
+	C.CDLTransform_setSat(tx.ptr, C.float(sat))
+	runtime.KeepAlive(tx)
+}
+
+// SatLumaCoefs returns the luma coefficients used
+// for the saturation operation
+func (tx *CDLTransform) SatLumaCoefs() [3]float32 {
+	var rgb [3]float32
+	C.CDLTransform_getSatLumaCoefs(tx.ptr, (*C.float)(&rgb[0]))
+	runtime.KeepAlive(tx)
+	return rgb
+}
+
+// ID returns the unique identifier of the correction
+func (tx *CDLTransform) ID() string {
+	id := C.GoString(C.CDLTransform_getID(tx.ptr))
+	runtime.KeepAlive(tx)
+	return id
+}
+
+// SetID sets the unique identifier of the correction
+func (tx *CDLTransform) SetID(id string) {
+	c_str := C.CString(id)
+	defer C.free(unsafe.Pointer(c_str))
+	C.CDLTransform_setID(tx.ptr, c_str)
+	runtime.KeepAlive(tx)
+}
+
+func (tx *CDLTransform) Description() string {
+	desc := C.GoString(C.CDLTransform_getDescription(tx.ptr))
+	runtime.KeepAlive(tx)
+	return desc
+}
+
+func (tx *CDLTransform) SetDescription(desc string) {
+	c_str := C.CString(desc)
+	defer C.free(unsafe.Pointer(c_str))
+	C.CDLTransform_setDescription(tx.ptr, c_str)
+	runtime.KeepAlive(tx)
+}
+
+// XML returns the correction as an ASC ColorCorrection XML string
+func (tx *CDLTransform) XML() string {
+	xml := C.GoString(C.CDLTransform_getXML(tx.ptr))
+	runtime.KeepAlive(tx)
+	return xml
+}
+
+// SetXML sets the correction from an ASC ColorCorrection XML string.
+// An error is returned if the XML cannot be parsed.
+func (tx *CDLTransform) SetXML(xml string) error {
+	c_str := C.CString(xml)
+	defer C.free(unsafe.Pointer(c_str))
+	ctx, err := C.CDLTransform_setXML(tx.ptr, c_str)
+	_, err = takeHandleContext("CDLTransform.SetXML", "CDLTransform", ctx, err)
+	runtime.KeepAlive(tx)
+	return err
+}
+
+// Equals returns true if the other CDLTransform
+// describes the same correction
+func (tx *CDLTransform) Equals(other *CDLTransform) bool {
+	if other == nil {
+		return false
+	}
+	ret := bool(C.CDLTransform_equals(tx.ptr, other.ptr))
+	runtime.KeepAlive(tx)
+	runtime.KeepAlive(other)
+	return ret
+}
+
+// CDLTransformFromFile loads a correction from a .cc, .ccc or .cdl file.
+// The cccid selects a correction by id from a .ccc or .cdl file.
+// OCIO v1 cannot read a .cdl (ColorDecisionList) file, so its
+// ColorCorrection is read here and set with SetXML. The first
+// correction of a .cdl file is used if cccid is empty.
+func CDLTransformFromFile(src, cccid string) (*CDLTransform, error) {
+	if strings.EqualFold(filepath.Ext(src), ".cdl") {
+		return cdlTransformFromCDL(src, cccid)
+	}
+
+	c_src := C.CString(src)
+	c_id := C.CString(cccid)
+	defer C.free(unsafe.Pointer(c_src))
+	defer C.free(unsafe.Pointer(c_id))
+
+	ctx, err := C.CDLTransform_CreateFromFile(c_src, c_id)
+	ptr, err := takeHandleContext("CDLTransformFromFile", "CDLTransform", ctx, err)
+	if err != nil {
+		return nil, err
+	}
+	return newCDLTransform(ptr), nil
+}
+
+// cdlDecisionList is the part of an ASC ColorDecisionList
+// read by cdlTransformFromCDL
+type cdlDecisionList struct {
+	Decisions []struct {
+		Corrections []struct {
+			ID       string `xml:"id,attr"`
+			InnerXML string `xml:",innerxml"`
+		} `xml:"ColorCorrection"`
+	} `xml:"ColorDecision"`
+}
+
+// cdlTransformFromCDL loads a correction from a .cdl file
+func cdlTransformFromCDL(src, cccid string) (*CDLTransform, error) {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	var list cdlDecisionList
+	if err = xml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", src, err)
+	}
+	for _, d := range list.Decisions {
+		for _, cc := range d.Corrections {
+			if cccid != "" && cc.ID != cccid {
+				continue
+			}
+			var id strings.Builder
+			if err = xml.EscapeText(&id, []byte(cc.ID)); err != nil {
+				return nil, err
+			}
+			tx := NewCDLTransform()
+			if err = tx.SetXML(fmt.Sprintf(`<ColorCorrection id="%s">%s</ColorCorrection>`,
+				id.String(), cc.InnerXML)); err != nil {
+				tx.Destroy()
+				return nil, fmt.Errorf("%s: %w", src, err)
+			}
+			return tx, nil
+		}
+	}
+	if cccid != "" {
+		return nil, fmt.Errorf("%s: no ColorCorrection with id %q", src, cccid)
+	}
+	return nil, fmt.Errorf("%s: no ColorCorrection", src)
+}
+
+// ColorSpaceTransform converts between two named color spaces.
+type ColorSpaceTransform struct {
+	ptr C.ColorSpaceTransformId
+}
+
+func newColorSpaceTransform(p C.ColorSpaceTransformId) *ColorSpaceTransform {
+	tx := &ColorSpaceTransform{p}
+	runtime.SetFinalizer(tx, deleteColorSpaceTransform)
+	return tx
+}
+
+func deleteColorSpaceTransform(tx *ColorSpaceTransform) {
+	if tx == nil {
+		return
+	}
+	if tx.ptr != 0 {
+		runtime.SetFinalizer(tx, nil)
+		C.deleteColorSpaceTransform(tx.ptr)
+		tx.ptr = 0
+	}
+	runtime.KeepAlive(tx)
+}
+
+// Create a new empty ColorSpaceTransform
+func NewColorSpaceTransform() *ColorSpaceTransform {
+	return newColorSpaceTransform(C.ColorSpaceTransform_Create())
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (tx *ColorSpaceTransform) Destroy() {
+	deleteColorSpaceTransform(tx)
+}
+
+func (tx *ColorSpaceTransform) transformHandle() C.HandleId {
+	if tx == nil {
+		return 0
+	}
+	return tx.ptr
+}
+
+// Create a new editable copy of this ColorSpaceTransform
+func (tx *ColorSpaceTransform) EditableCopy() *ColorSpaceTransform {
+	cpy := newColorSpaceTransform(C.ColorSpaceTransform_createEditableCopy(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cpy
+}
+
+func (tx *ColorSpaceTransform) Direction() TransformDirection {
+	dir := TransformDirection(C.ColorSpaceTransform_getDirection(tx.ptr))
+	runtime.KeepAlive(tx)
+	return dir
+}
+
+func (tx *ColorSpaceTransform) SetDirection(dir TransformDirection) {
+	C.ColorSpaceTransform_setDirection(tx.ptr, C.TransformDirection(dir))
+	runtime.KeepAlive(tx)
+}
+
+// Src returns the source color space
+func (tx *ColorSpaceTransform) Src() string {
+	cs := C.GoString(C.ColorSpaceTransform_getSrc(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cs
+}
+
+// SetSrc sets the source color space
+func (tx *ColorSpaceTransform) SetSrc(cs string) {
+	c_str := C.CString(cs)
+	defer C.free(unsafe.Pointer(c_str))
+	C.ColorSpaceTransform_setSrc(tx.ptr, c_str)
+	runtime.KeepAlive(tx)
+}
+
+// Dst returns the destination color space
+func (tx *ColorSpaceTransform) Dst() string {
+	cs := C.GoString(C.ColorSpaceTransform_getDst(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cs
+}
+
+// SetDst sets the destination color space
+func (tx *ColorSpaceTransform) SetDst(cs string) {
+	c_str := C.CString(cs)
+	defer C.free(unsafe.Pointer(c_str))
+	C.ColorSpaceTransform_setDst(tx.ptr, c_str)
+	runtime.KeepAlive(tx)
+}
+
+// ExponentTransform represents an exponent transform: pow( clamp(color), value)
+// Values less than 0 are clamped to 0.
+type ExponentTransform struct {
+	ptr C.ExponentTransformId
+}
+
+func newExponentTransform(p C.ExponentTransformId) *ExponentTransform {
+	tx := &ExponentTransform{p}
+	runtime.SetFinalizer(tx, deleteExponentTransform)
+	return tx
+}
+
+func deleteExponentTransform(tx *ExponentTransform) {
+	if tx == nil {
+		return
+	}
+	if tx.ptr != 0 {
+		runtime.SetFinalizer(tx, nil)
+		C.deleteExponentTransform(tx.ptr)
+		tx.ptr = 0
+	}
+	runtime.KeepAlive(tx)
+}
+
+// Create a new empty ExponentTransform
+func NewExponentTransform() *ExponentTransform {
+	return newExponentTransform(C.ExponentTransform_Create())
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (tx *ExponentTransform) Destroy() {
+	deleteExponentTransform(tx)
+}
+
+func (tx *ExponentTransform) transformHandle() C.HandleId {
+	if tx == nil {
+		return 0
+	}
+	return tx.ptr
+}
+
+// Create a new editable copy of this ExponentTransform
+func (tx *ExponentTransform) EditableCopy() *ExponentTransform {
+	cpy := newExponentTransform(C.ExponentTransform_createEditableCopy(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cpy
+}
+
+func (tx *ExponentTransform) Direction() TransformDirection {
+	dir := TransformDirection(C.ExponentTransform_getDirection(tx.ptr))
+	runtime.KeepAlive(tx)
+	return dir
+}
+
+func (tx *ExponentTransform) SetDirection(dir TransformDirection) {
+	C.ExponentTransform_setDirection(tx.ptr, C.TransformDirection(dir))
+	runtime.KeepAlive(tx)
+}
+
+// Value returns the RGBA exponent values
+func (tx *ExponentTransform) Value() [4]float32 {
+	var vec4 [4]float32
+	C.ExponentTransform_getValue(tx.ptr, (*C.float)(&vec4[0]))
+	runtime.KeepAlive(tx)
+	return vec4
+}
+
+// SetValue sets the RGBA exponent values
+func (tx *ExponentTransform) SetValue(vec4 [4]float32) {
+	C.ExponentTransform_setValue(tx.ptr, (*C.float)(&vec4[0]))
+	runtime.KeepAlive(tx)
+}
+
+// FileTransform applies an external LUT (or other file based
+// transform) by its path on disk.
+type FileTransform struct {
+	ptr C.FileTransformId
+}
+
+func newFileTransform(p C.FileTransformId) *FileTransform {
+	tx := &FileTransform{p}
+	runtime.SetFinalizer(tx, deleteFileTransform)
+	return tx
+}
+
+func deleteFileTransform(tx *FileTransform) {
+	if tx == nil {
+		return
+	}
+	if tx.ptr != 0 {
+		runtime.SetFinalizer(tx, nil)
+		C.deleteFileTransform(tx.ptr)
+		tx.ptr = 0
+	}
+	runtime.KeepAlive(tx)
+}
+
+// Create a new empty FileTransform
+func NewFileTransform() *FileTransform {
+	return newFileTransform(C.FileTransform_Create())
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (tx *FileTransform) Destroy() {
+	deleteFileTransform(tx)
+}
+
+func (tx *FileTransform) transformHandle() C.HandleId {
+	if tx == nil {
+		return 0
+	}
+	return tx.ptr
+}
+
+// Create a new editable copy of this FileTransform
+func (tx *FileTransform) EditableCopy() *FileTransform {
+	cpy := newFileTransform(C.FileTransform_createEditableCopy(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cpy
+}
+
+func (tx *FileTransform) Direction() TransformDirection {
+	dir := TransformDirection(C.FileTransform_getDirection(tx.ptr))
+	runtime.KeepAlive(tx)
+	return dir
+}
+
+func (tx *FileTransform) SetDirection(dir TransformDirection) {
+	C.FileTransform_setDirection(tx.ptr, C.TransformDirection(dir))
+	runtime.KeepAlive(tx)
+}
+
+// Src returns the path of the file to apply
+func (tx *FileTransform) Src() string {
+	src := C.GoString(C.FileTransform_getSrc(tx.ptr))
+	runtime.KeepAlive(tx)
+	return src
+}
+
+// SetSrc sets the path of the file to apply.
+// Relative paths are resolved against the Config search path.
+// If the file cannot be located when a Processor is created
+// for the transform, an ErrMissingFile is returned.
+func (tx *FileTransform) SetSrc(src string) {
+	c_str := C.CString(src)
+	defer C.free(unsafe.Pointer(c_str))
+	C.FileTransform_setSrc(tx.ptr, c_str)
+	runtime.KeepAlive(tx)
+}
+
+// CCCID returns the id of the correction to use
+// from a .ccc file
+func (tx *FileTransform) CCCID() string {
+	id := C.GoString(C.FileTransform_getCCCId(tx.ptr))
+	runtime.KeepAlive(tx)
+	return id
+}
+
+// SetCCCID sets the id of the correction to use
+// from a .ccc file
+func (tx *FileTransform) SetCCCID(id string) {
+	c_str := C.CString(id)
+	defer C.free(unsafe.Pointer(c_str))
+	C.FileTransform_setCCCId(tx.ptr, c_str)
+	runtime.KeepAlive(tx)
+}
+
+// Interpolation returns one of the INTERP_* const values
+func (tx *FileTransform) Interpolation() InterpType {
+	interp := InterpType(C.FileTransform_getInterpolation(tx.ptr))
+	runtime.KeepAlive(tx)
+	return interp
+}
+
+// SetInterpolation sets one of the INTERP_* const values
+func (tx *FileTransform) SetInterpolation(interp InterpType) {
+	C.FileTransform_setInterpolation(tx.ptr, C.Interpolation(interp))
+	runtime.KeepAlive(tx)
+}
+
+// FileFormat describes a file format supported for reading
+// (FileTransform) or writing (Baker)
+type FileFormat struct {
+	Name      string
+	Extension string
+}
+
+// FileTransformFormats returns the file formats that
+// can be read by a FileTransform
+func FileTransformFormats() []FileFormat {
+	num := int(C.FileTransform_getNumFormats())
+	formats := make([]FileFormat, 0, num)
+	for i := 0; i < num; i++ {
+		formats = append(formats, FileFormat{
+			Name:      C.GoString(C.FileTransform_getFormatNameByIndex(C.int(i))),
+			Extension: C.GoString(C.FileTransform_getFormatExtensionByIndex(C.int(i))),
+		})
+	}
+	return formats
+}
+
+// GroupTransform is an ordered collection of transforms
+// that are applied as a single transform.
+type GroupTransform struct {
+	ptr C.GroupTransformId
+}
+
+func newGroupTransform(p C.GroupTransformId) *GroupTransform {
+	tx := &GroupTransform{p}
+	runtime.SetFinalizer(tx, deleteGroupTransform)
+	return tx
+}
+
+func deleteGroupTransform(tx *GroupTransform) {
+	if tx == nil {
+		return
+	}
+	if tx.ptr != 0 {
+		runtime.SetFinalizer(tx, nil)
+		C.deleteGroupTransform(tx.ptr)
+		tx.ptr = 0
+	}
+	runtime.KeepAlive(tx)
+}
+
+// Create a new empty GroupTransform
+func NewGroupTransform() *GroupTransform {
+	return newGroupTransform(C.GroupTransform_Create())
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (tx *GroupTransform) Destroy() {
+	deleteGroupTransform(tx)
+}
+
+func (tx *GroupTransform) transformHandle() C.HandleId {
+	if tx == nil {
+		return 0
+	}
+	return tx.ptr
+}
+
+// Create a new editable copy of this GroupTransform
+func (tx *GroupTransform) EditableCopy() *GroupTransform {
+	cpy := newGroupTransform(C.GroupTransform_createEditableCopy(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cpy
+}
+
+func (tx *GroupTransform) Direction() TransformDirection {
+	dir := TransformDirection(C.GroupTransform_getDirection(tx.ptr))
+	runtime.KeepAlive(tx)
+	return dir
+}
+
+func (tx *GroupTransform) SetDirection(dir TransformDirection) {
+	C.GroupTransform_setDirection(tx.ptr, C.TransformDirection(dir))
+	runtime.KeepAlive(tx)
+}
+
+// Transform returns a copy of the transform at the given index
+// in the group, so changing it does not change the group.
+func (tx *GroupTransform) Transform(index int) (Transform, error) {
+	if size := tx.Size(); index < 0 || index >= size {
+		return nil, fmt.Errorf("transform index %d out of range [0, %d)", index, size)
+	}
+	ret, err := newTransform(C.GroupTransform_getTransform(tx.ptr, C.int(index)))
+	runtime.KeepAlive(tx)
+	return ret, err
+}
+
+// Size returns the number of transforms in the group
+func (tx *GroupTransform) Size() int {
+	size := int(C.GroupTransform_size(tx.ptr))
+	runtime.KeepAlive(tx)
+	return size
+}
+
+// Empty returns true if the group contains no transforms
+func (tx *GroupTransform) Empty() bool {
+	empty := bool(C.GroupTransform_empty(tx.ptr))
+	runtime.KeepAlive(tx)
+	return empty
+}
+
+// Push appends a transform to the end of the group.
+// This stores a copy of the specified transform, so later
+// changes to it are not reflected in the group.
+// A nil transform is ignored.
+func (tx *GroupTransform) Push(other Transform) {
+	handle := transformHandleOrZero(other)
+	if handle == 0 {
+		return
+	}
+	C.GroupTransform_push_back(tx.ptr, handle)
+	runtime.KeepAlive(tx)
+	runtime.KeepAlive(other)
+}
+
+// Clear removes all transforms from the group
+func (tx *GroupTransform) Clear() {
+	C.GroupTransform_clear(tx.ptr)
+	runtime.KeepAlive(tx)
+}
+
+// LogTransform represents a log transform: log(color, base)
+// Values less than or equal to 0 are clamped to the smallest positive float.
+type LogTransform struct {
+	ptr C.LogTransformId
+}
+
+func newLogTransform(p C.LogTransformId) *LogTransform {
+	tx := &LogTransform{p}
+	runtime.SetFinalizer(tx, deleteLogTransform)
+	return tx
+}
+
+func deleteLogTransform(tx *LogTransform) {
+	if tx == nil {
+		return
+	}
+	if tx.ptr != 0 {
+		runtime.SetFinalizer(tx, nil)
+		C.deleteLogTransform(tx.ptr)
+		tx.ptr = 0
+	}
+	runtime.KeepAlive(tx)
+}
+
+// Create a new empty LogTransform
+func NewLogTransform() *LogTransform {
+	return newLogTransform(C.LogTransform_Create())
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (tx *LogTransform) Destroy() {
+	deleteLogTransform(tx)
+}
+
+func (tx *LogTransform) transformHandle() C.HandleId {
+	if tx == nil {
+		return 0
+	}
+	return tx.ptr
+}
+
+// Create a new editable copy of this LogTransform
+func (tx *LogTransform) EditableCopy() *LogTransform {
+	cpy := newLogTransform(C.LogTransform_createEditableCopy(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cpy
+}
+
+func (tx *LogTransform) Direction() TransformDirection {
+	dir := TransformDirection(C.LogTransform_getDirection(tx.ptr))
+	runtime.KeepAlive(tx)
+	return dir
+}
+
+func (tx *LogTransform) SetDirection(dir TransformDirection) {
+	C.LogTransform_setDirection(tx.ptr, C.TransformDirection(dir))
+	runtime.KeepAlive(tx)
+}
+
+func (tx *LogTransform) Base() float32 {
+	base := float32(C.LogTransform_getBase(tx.ptr))
+	runtime.KeepAlive(tx)
+	return base
+}
+
+func (tx *LogTransform) SetBase(base float32) {
+	C.LogTransform_setBase(tx.ptr, C.float(base))
+	runtime.KeepAlive(tx)
+}
+
+// MatrixTransform represents an MX+B Matrix transform
+type MatrixTransform struct {
+	ptr C.MatrixTransformId
+}
+
+func newMatrixTransform(p C.MatrixTransformId) *MatrixTransform {
+	tx := &MatrixTransform{p}
+	runtime.SetFinalizer(tx, deleteMatrixTransform)
+	return tx
+}
+
+func deleteMatrixTransform(tx *MatrixTransform) {
+	if tx == nil {
+		return
+	}
+	if tx.ptr != 0 {
+		runtime.SetFinalizer(tx, nil)
+		C.deleteMatrixTransform(tx.ptr)
+		tx.ptr = 0
+	}
+	runtime.KeepAlive(tx)
+}
+
+// Create a new empty MatrixTransform
+func NewMatrixTransform() *MatrixTransform {
+	return newMatrixTransform(C.MatrixTransform_Create())
+}
+
+// Destroy immediately frees resources for this
+// instance instead of waiting for garbage collection
+// finalizer to run at some point later
+func (tx *MatrixTransform) Destroy() {
+	deleteMatrixTransform(tx)
+}
+
+func (tx *MatrixTransform) transformHandle() C.HandleId {
+	if tx == nil {
+		return 0
+	}
+	return tx.ptr
+}
+
+// Create a new editable copy of this MatrixTransform
+func (tx *MatrixTransform) EditableCopy() *MatrixTransform {
+	cpy := newMatrixTransform(C.MatrixTransform_createEditableCopy(tx.ptr))
+	runtime.KeepAlive(tx)
+	return cpy
+}
+
+func (tx *MatrixTransform) Direction() TransformDirection {
+	dir := TransformDirection(C.MatrixTransform_getDirection(tx.ptr))
+	runtime.KeepAlive(tx)
+	return dir
+}
+
+func (tx *MatrixTransform) SetDirection(dir TransformDirection) {
+	C.MatrixTransform_setDirection(tx.ptr, C.TransformDirection(dir))
+	runtime.KeepAlive(tx)
+}
+
+// Value returns the row-major 4x4 matrix and the RGBA offset
+func (tx *MatrixTransform) Value() (m44 [16]float32, offset4 [4]float32) {
+	C.MatrixTransform_getValue(tx.ptr, (*C.float)(&m44[0]), (*C.float)(&offset4[0]))
+	runtime.KeepAlive(tx)
+	return m44, offset4
+}
+
+// SetValue sets the row-major 4x4 matrix and the RGBA offset
+func (tx *MatrixTransform) SetValue(m44 [16]float32, offset4 [4]float32) {
+	C.MatrixTransform_setValue(tx.ptr, (*C.float)(&m44[0]), (*C.float)(&offset4[0]))
+	runtime.KeepAlive(tx)
+}
+
+// Matrix returns the row-major 4x4 matrix
+func (tx *MatrixTransform) Matrix() [16]float32 {
+	var m44 [16]float32
+	C.MatrixTransform_getMatrix(tx.ptr, (*C.float)(&m44[0]))
+	runtime.KeepAlive(tx)
+	return m44
+}
+
+// SetMatrix sets the row-major 4x4 matrix
+func (tx *MatrixTransform) SetMatrix(m44 [16]float32) {
+	C.MatrixTransform_setMatrix(tx.ptr, (*C.float)(&m44[0]))
+	runtime.KeepAlive(tx)
+}
+
+// Offset returns the RGBA offset
+func (tx *MatrixTransform) Offset() [4]float32 {
+	var offset4 [4]float32
+	C.MatrixTransform_getOffset(tx.ptr, (*C.float)(&offset4[0]))
+	runtime.KeepAlive(tx)
+	return offset4
+}
+
+// SetOffset sets the RGBA offset
+func (tx *MatrixTransform) SetOffset(offset4 [4]float32) {
+	C.MatrixTransform_setOffset(tx.ptr, (*C.float)(&offset4[0]))
+	runtime.KeepAlive(tx)
+}
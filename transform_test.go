@@ -1,6 +1,8 @@
 package ocio
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -74,3 +76,499 @@ func TestDisplayTransform(t *testing.T) {
 	dt.Destroy()
 	cpy.Destroy()
 }
+
+func TestLookTransform(t *testing.T) {
+	lt := NewLookTransform()
+	// assert interface
+	var _ Transform = lt
+
+	if val := lt.Src(); val != "" {
+		t.Errorf("expected empty string; got %q", val)
+	}
+	if val := lt.Dst(); val != "" {
+		t.Errorf("expected empty string; got %q", val)
+	}
+	if val := lt.Looks(); val != "" {
+		t.Errorf("expected empty string; got %q", val)
+	}
+	if val := lt.Direction(); val != TRANSFORM_DIR_FORWARD {
+		t.Errorf("expected TRANSFORM_DIR_FORWARD(%v); got %v", TRANSFORM_DIR_FORWARD, val)
+	}
+
+	lt.SetSrc("src")
+	lt.SetDst("dst")
+	lt.SetLooks("+look1, -look2")
+	lt.SetDirection(TRANSFORM_DIR_INVERSE)
+
+	if val := lt.Src(); val != "src" {
+		t.Errorf("expected 'src'; got %q", val)
+	}
+	if val := lt.Dst(); val != "dst" {
+		t.Errorf("expected 'dst'; got %q", val)
+	}
+	if val := lt.Looks(); val != "+look1, -look2" {
+		t.Errorf("expected '+look1, -look2'; got %q", val)
+	}
+	if val := lt.Direction(); val != TRANSFORM_DIR_INVERSE {
+		t.Errorf("expected TRANSFORM_DIR_INVERSE(%v); got %v", TRANSFORM_DIR_INVERSE, val)
+	}
+
+	cpy := lt.EditableCopy()
+	cpy.SetLooks("look3")
+
+	if val := cpy.Looks(); val != "look3" {
+		t.Errorf("expected 'look3'; got %q", val)
+	}
+	if val := lt.Looks(); val != "+look1, -look2" {
+		t.Errorf("expected '+look1, -look2'; got %q", val)
+	}
+	lt.Destroy()
+	cpy.Destroy()
+}
+
+func TestAllocationTransform(t *testing.T) {
+	tx := NewAllocationTransform()
+	// assert interface
+	var _ Transform = tx
+
+	if val := tx.Allocation(); val != ALLOCATION_UNIFORM {
+		t.Errorf("expected ALLOCATION_UNIFORM(%v); got %v", ALLOCATION_UNIFORM, val)
+	}
+	if val := tx.Vars(); len(val) != 0 {
+		t.Errorf("expected empty vars; got %v", val)
+	}
+
+	tx.SetAllocation(ALLOCATION_LG2)
+	tx.SetVars([]float32{-15, 6})
+	tx.SetDirection(TRANSFORM_DIR_INVERSE)
+
+	if val := tx.Allocation(); val != ALLOCATION_LG2 {
+		t.Errorf("expected ALLOCATION_LG2(%v); got %v", ALLOCATION_LG2, val)
+	}
+	if val := tx.Vars(); !reflect.DeepEqual(val, []float32{-15, 6}) {
+		t.Errorf("expected [-15 6]; got %v", val)
+	}
+	if val := tx.Direction(); val != TRANSFORM_DIR_INVERSE {
+		t.Errorf("expected TRANSFORM_DIR_INVERSE(%v); got %v", TRANSFORM_DIR_INVERSE, val)
+	}
+
+	cpy := tx.EditableCopy()
+	cpy.SetVars(nil)
+	if val := cpy.Vars(); len(val) != 0 {
+		t.Errorf("expected empty vars; got %v", val)
+	}
+	if val := tx.Vars(); len(val) != 2 {
+		t.Errorf("expected 2 vars; got %v", val)
+	}
+	tx.Destroy()
+	cpy.Destroy()
+}
+
+func TestCDLTransform(t *testing.T) {
+	tx := NewCDLTransform()
+	// assert interface
+	var _ Transform = tx
+
+	if val := tx.Slope(); val != [3]float32{1, 1, 1} {
+		t.Errorf("expected slope [1 1 1]; got %v", val)
+	}
+	if val := tx.Offset(); val != [3]float32{0, 0, 0} {
+		t.Errorf("expected offset [0 0 0]; got %v", val)
+	}
+	if val := tx.Power(); val != [3]float32{1, 1, 1} {
+		t.Errorf("expected power [1 1 1]; got %v", val)
+	}
+	if val := tx.Sat(); val != 1 {
+		t.Errorf("expected sat 1; got %v", val)
+	}
+
+	tx.SetSlope([3]float32{1.1, 1.2, 1.3})
+	tx.SetOffset([3]float32{0.1, 0.2, 0.3})
+	tx.SetPower([3]float32{0.9, 0.8, 0.7})
+	tx.SetSat(0.5)
+
+	if val := tx.Slope(); val != [3]float32{1.1, 1.2, 1.3} {
+		t.Errorf("expected slope [1.1 1.2 1.3]; got %v", val)
+	}
+	if val := tx.Offset(); val != [3]float32{0.1, 0.2, 0.3} {
+		t.Errorf("expected offset [0.1 0.2 0.3]; got %v", val)
+	}
+	if val := tx.Power(); val != [3]float32{0.9, 0.8, 0.7} {
+		t.Errorf("expected power [0.9 0.8 0.7]; got %v", val)
+	}
+	if val := tx.Sat(); val != 0.5 {
+		t.Errorf("expected sat 0.5; got %v", val)
+	}
+
+	cpy := tx.EditableCopy()
+	cpy.SetSat(2)
+	if val := cpy.Sat(); val != 2 {
+		t.Errorf("expected sat 2; got %v", val)
+	}
+	if val := tx.Sat(); val != 0.5 {
+		t.Errorf("expected sat 0.5; got %v", val)
+	}
+	tx.Destroy()
+	cpy.Destroy()
+}
+
+func TestColorSpaceTransform(t *testing.T) {
+	tx := NewColorSpaceTransform()
+	// assert interface
+	var _ Transform = tx
+
+	if val := tx.Src(); val != "" {
+		t.Errorf("expected empty string; got %q", val)
+	}
+	if val := tx.Dst(); val != "" {
+		t.Errorf("expected empty string; got %q", val)
+	}
+
+	tx.SetSrc("src")
+	tx.SetDst("dst")
+
+	if val := tx.Src(); val != "src" {
+		t.Errorf("expected 'src'; got %q", val)
+	}
+	if val := tx.Dst(); val != "dst" {
+		t.Errorf("expected 'dst'; got %q", val)
+	}
+
+	cpy := tx.EditableCopy()
+	cpy.SetDst("dst2")
+	if val := cpy.Dst(); val != "dst2" {
+		t.Errorf("expected 'dst2'; got %q", val)
+	}
+	if val := tx.Dst(); val != "dst" {
+		t.Errorf("expected 'dst'; got %q", val)
+	}
+	tx.Destroy()
+	cpy.Destroy()
+}
+
+func TestExponentTransform(t *testing.T) {
+	tx := NewExponentTransform()
+	// assert interface
+	var _ Transform = tx
+
+	if val := tx.Value(); val != [4]float32{1, 1, 1, 1} {
+		t.Errorf("expected [1 1 1 1]; got %v", val)
+	}
+
+	tx.SetValue([4]float32{2.2, 2.2, 2.2, 1})
+	if val := tx.Value(); val != [4]float32{2.2, 2.2, 2.2, 1} {
+		t.Errorf("expected [2.2 2.2 2.2 1]; got %v", val)
+	}
+
+	cpy := tx.EditableCopy()
+	cpy.SetDirection(TRANSFORM_DIR_INVERSE)
+	if val := cpy.Direction(); val != TRANSFORM_DIR_INVERSE {
+		t.Errorf("expected TRANSFORM_DIR_INVERSE(%v); got %v", TRANSFORM_DIR_INVERSE, val)
+	}
+	if val := tx.Direction(); val != TRANSFORM_DIR_FORWARD {
+		t.Errorf("expected TRANSFORM_DIR_FORWARD(%v); got %v", TRANSFORM_DIR_FORWARD, val)
+	}
+	tx.Destroy()
+	cpy.Destroy()
+}
+
+func TestFileTransform(t *testing.T) {
+	tx := NewFileTransform()
+	// assert interface
+	var _ Transform = tx
+
+	if val := tx.Src(); val != "" {
+		t.Errorf("expected empty string; got %q", val)
+	}
+	if val := tx.CCCID(); val != "" {
+		t.Errorf("expected empty string; got %q", val)
+	}
+	if val := tx.Interpolation(); val != INTERP_UNKNOWN {
+		t.Errorf("expected INTERP_UNKNOWN(%v); got %v", INTERP_UNKNOWN, val)
+	}
+
+	tx.SetSrc("lg10.spi1d")
+	tx.SetCCCID("shot_010")
+	tx.SetInterpolation(INTERP_LINEAR)
+
+	if val := tx.Src(); val != "lg10.spi1d" {
+		t.Errorf("expected 'lg10.spi1d'; got %q", val)
+	}
+	if val := tx.CCCID(); val != "shot_010" {
+		t.Errorf("expected 'shot_010'; got %q", val)
+	}
+	if val := tx.Interpolation(); val != INTERP_LINEAR {
+		t.Errorf("expected INTERP_LINEAR(%v); got %v", INTERP_LINEAR, val)
+	}
+
+	cpy := tx.EditableCopy()
+	cpy.SetSrc("lgf.spi1d")
+	if val := cpy.Src(); val != "lgf.spi1d" {
+		t.Errorf("expected 'lgf.spi1d'; got %q", val)
+	}
+	if val := tx.Src(); val != "lg10.spi1d" {
+		t.Errorf("expected 'lg10.spi1d'; got %q", val)
+	}
+	tx.Destroy()
+	cpy.Destroy()
+}
+
+func TestGroupTransform(t *testing.T) {
+	tx := NewGroupTransform()
+	// assert interface
+	var _ Transform = tx
+
+	if val := tx.Direction(); val != TRANSFORM_DIR_FORWARD {
+		t.Errorf("expected TRANSFORM_DIR_FORWARD(%v); got %v", TRANSFORM_DIR_FORWARD, val)
+	}
+	tx.SetDirection(TRANSFORM_DIR_INVERSE)
+
+	if val := tx.Size(); val != 0 {
+		t.Errorf("expected size 0; got %d", val)
+	}
+	if !tx.Empty() {
+		t.Error("expected group to be empty")
+	}
+
+	mtx := NewMatrixTransform()
+	ltx := NewLogTransform()
+	ltx.SetBase(10)
+	tx.Push(mtx)
+	tx.Push(ltx)
+	tx.Push(nil)
+	tx.Push((*MatrixTransform)(nil))
+	mtx.Destroy()
+
+	// Pushed transforms are copies
+	ltx.SetBase(2)
+	ltx.Destroy()
+
+	if val := tx.Size(); val != 2 {
+		t.Errorf("expected size 2; got %d", val)
+	}
+	if tx.Empty() {
+		t.Error("expected group to not be empty")
+	}
+
+	child, err := tx.Transform(0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, ok := child.(*MatrixTransform); !ok {
+		t.Errorf("expected *MatrixTransform at index 0; got %T", child)
+	}
+	child, err = tx.Transform(1)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if log, ok := child.(*LogTransform); !ok {
+		t.Errorf("expected *LogTransform at index 1; got %T", child)
+	} else if val := log.Base(); val != 10 {
+		t.Errorf("expected base 10; got %v", val)
+	} else {
+		// The returned transform is a copy
+		log.SetBase(2)
+		log.Destroy()
+		child, _ = tx.Transform(1)
+		if val := child.(*LogTransform).Base(); val != 10 {
+			t.Errorf("expected the group to keep base 10; got %v", val)
+		}
+	}
+	if _, err = tx.Transform(2); err == nil {
+		t.Error("expected an error for an out of range index; got nil")
+	}
+	if _, err = tx.Transform(-1); err == nil {
+		t.Error("expected an error for an out of range index; got nil")
+	}
+
+	cpy := tx.EditableCopy()
+	if val := cpy.Direction(); val != TRANSFORM_DIR_INVERSE {
+		t.Errorf("expected TRANSFORM_DIR_INVERSE(%v); got %v", TRANSFORM_DIR_INVERSE, val)
+	}
+	cpy.Clear()
+	if val := cpy.Size(); val != 0 {
+		t.Errorf("expected size 0; got %d", val)
+	}
+	if val := tx.Size(); val != 2 {
+		t.Errorf("expected size 2; got %d", val)
+	}
+	tx.Destroy()
+	cpy.Destroy()
+}
+
+func TestLogTransform(t *testing.T) {
+	tx := NewLogTransform()
+	// assert interface
+	var _ Transform = tx
+
+	if val := tx.Base(); val != 2 {
+		t.Errorf("expected base 2; got %v", val)
+	}
+
+	tx.SetBase(10)
+	if val := tx.Base(); val != 10 {
+		t.Errorf("expected base 10; got %v", val)
+	}
+
+	cpy := tx.EditableCopy()
+	cpy.SetBase(2)
+	if val := tx.Base(); val != 10 {
+		t.Errorf("expected base 10; got %v", val)
+	}
+	tx.Destroy()
+	cpy.Destroy()
+}
+
+func TestMatrixTransform(t *testing.T) {
+	tx := NewMatrixTransform()
+	// assert interface
+	var _ Transform = tx
+
+	identity := [16]float32{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+	if val := tx.Matrix(); val != identity {
+		t.Errorf("expected identity matrix; got %v", val)
+	}
+	if val := tx.Offset(); val != [4]float32{} {
+		t.Errorf("expected zero offset; got %v", val)
+	}
+
+	scale := [16]float32{
+		2, 0, 0, 0,
+		0, 3, 0, 0,
+		0, 0, 4, 0,
+		0, 0, 0, 1,
+	}
+	offset := [4]float32{0.1, 0.2, 0.3, 0}
+
+	tx.SetValue(scale, offset)
+	m44, offset4 := tx.Value()
+	if m44 != scale {
+		t.Errorf("expected %v; got %v", scale, m44)
+	}
+	if offset4 != offset {
+		t.Errorf("expected %v; got %v", offset, offset4)
+	}
+
+	cpy := tx.EditableCopy()
+	cpy.SetMatrix(identity)
+	cpy.SetOffset([4]float32{})
+	if val := cpy.Matrix(); val != identity {
+		t.Errorf("expected identity matrix; got %v", val)
+	}
+	if val := tx.Matrix(); val != scale {
+		t.Errorf("expected %v; got %v", scale, val)
+	}
+	if val := tx.Offset(); val != offset {
+		t.Errorf("expected %v; got %v", offset, val)
+	}
+	tx.Destroy()
+	cpy.Destroy()
+}
+
+func TestCDLTransformXML(t *testing.T) {
+	tx := NewCDLTransform()
+	defer tx.Destroy()
+
+	tx.SetID("shot_030")
+	tx.SetDescription("unittest grade")
+	tx.SetSlope([3]float32{1.1, 1.2, 1.3})
+	tx.SetOffset([3]float32{0.1, 0.2, 0.3})
+	tx.SetPower([3]float32{0.9, 0.8, 0.7})
+	tx.SetSat(0.5)
+
+	if val := tx.ID(); val != "shot_030" {
+		t.Errorf("expected 'shot_030'; got %q", val)
+	}
+	if val := tx.Description(); val != "unittest grade" {
+		t.Errorf("expected 'unittest grade'; got %q", val)
+	}
+
+	xml := tx.XML()
+	if !strings.Contains(xml, "shot_030") {
+		t.Errorf("expected XML to contain the id 'shot_030':\n%s", xml)
+	}
+
+	other := NewCDLTransform()
+	defer other.Destroy()
+	if other.Equals(tx) {
+		t.Error("expected a default CDLTransform to not equal the modified one")
+	}
+	if err := other.SetXML(xml); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !other.Equals(tx) {
+		t.Errorf("expected CDLTransform from XML to equal the original:\n%s\n%s", xml, other.XML())
+	}
+	if val := other.ID(); val != "shot_030" {
+		t.Errorf("expected 'shot_030'; got %q", val)
+	}
+
+	if err := other.SetXML("<bad xml"); err == nil {
+		t.Error("expected an error for invalid XML; got nil")
+	}
+}
+
+func TestCDLTransformFromFile(t *testing.T) {
+	tx, err := CDLTransformFromFile("testdata/cdl/grades.ccc", "shot_020")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer tx.Destroy()
+
+	if val := tx.ID(); val != "shot_020" {
+		t.Errorf("expected 'shot_020'; got %q", val)
+	}
+	if val := tx.Slope(); val != [3]float32{0.9, 1.0, 1.1} {
+		t.Errorf("expected slope [0.9 1 1.1]; got %v", val)
+	}
+	if val := tx.Offset(); val != [3]float32{-0.01, 0, 0.01} {
+		t.Errorf("expected offset [-0.01 0 0.01]; got %v", val)
+	}
+	if val := tx.Power(); val != [3]float32{1.2, 1.2, 1.2} {
+		t.Errorf("expected power [1.2 1.2 1.2]; got %v", val)
+	}
+	if val := tx.Sat(); val != 1 {
+		t.Errorf("expected sat 1; got %v", val)
+	}
+
+	if _, err = CDLTransformFromFile("testdata/cdl/grades.ccc", "__missing__"); err == nil {
+		t.Error("expected an error for a missing cccid; got nil")
+	}
+	if _, err = CDLTransformFromFile("testdata/cdl/__missing__.ccc", ""); err == nil {
+		t.Error("expected an error for a missing file; got nil")
+	}
+
+	// A .cdl file is read through SetXML
+	cdl, err := CDLTransformFromFile("testdata/cdl/grades.cdl", "shot_040")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cdl.Destroy()
+	if val := cdl.ID(); val != "shot_040" {
+		t.Errorf("expected 'shot_040'; got %q", val)
+	}
+	if val := cdl.Slope(); val != [3]float32{1.2, 1.2, 1.2} {
+		t.Errorf("expected slope [1.2 1.2 1.2]; got %v", val)
+	}
+	if val := cdl.Sat(); val != 1.1 {
+		t.Errorf("expected sat 1.1; got %v", val)
+	}
+
+	first, err := CDLTransformFromFile("testdata/cdl/grades.cdl", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer first.Destroy()
+	if val := first.ID(); val != "shot_030" {
+		t.Errorf("expected the first correction 'shot_030'; got %q", val)
+	}
+
+	if _, err = CDLTransformFromFile("testdata/cdl/grades.cdl", "__missing__"); err == nil {
+		t.Error("expected an error for a missing cccid in a .cdl file; got nil")
+	}
+}
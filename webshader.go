@@ -0,0 +1,317 @@
+package ocio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultWebShaderLutSize is the 3D LUT edge length used by
+// Processor.WebShader when WebShaderOptions.LutSize is not set
+const DefaultWebShaderLutSize = 33
+
+// WebShaderOptions controls the shader and 3D LUT
+// generated by Processor.WebShader
+type WebShaderOptions struct {
+	// FunctionName is the name of the generated shader function.
+	// The 3D LUT texture is named FunctionName + "_lut3d".
+	// Defaults to "OCIOConvert".
+	FunctionName string
+
+	// LutSize is the edge length of the sampled 3D LUT.
+	// Defaults to DefaultWebShaderLutSize.
+	LutSize int
+
+	// DomainMin and DomainMax are the input values mapped to the first
+	// and last 3D LUT samples. Input outside of the domain is clamped.
+	// If both are zero, the domain is [0, 1].
+	DomainMin, DomainMax [3]float32
+
+	// WGSLGroup and WGSLBinding are the bind group and binding of
+	// the 3D LUT texture in the WGSL source. Its sampler uses
+	// the next binding.
+	WGSLGroup, WGSLBinding int
+}
+
+/*
+WebShader is a self-contained fragment shader function, in GLSL ES 3.0
+(WebGL 2) and WGSL (WebGPU), that approximates a Processor with a sampled
+3D LUT, for applying the same transform in a browser.
+
+Both functions take and return an RGBA vec4, passing alpha through, and
+sample a 3D texture that must be uploaded from LutRGBA16F, LutRGBA8 or
+LutImage, with linear filtering and clamp-to-edge wrapping.
+*/
+type WebShader struct {
+	FunctionName string
+
+	// GLSL is the GLSL ES 3.0 source, declaring
+	// a "uniform highp sampler3D" for the 3D LUT
+	GLSL string
+
+	// WGSL is the WGSL source, declaring a texture_3d<f32>
+	// and a sampler for the 3D LUT
+	WGSL string
+
+	// LutSize is the edge length of the 3D LUT
+	LutSize int
+
+	// Lut holds LutSize^3 RGB values, with red changing fastest:
+	//
+	//	Lut[3*(r + LutSize*(g + LutSize*b))]
+	Lut []float32
+
+	// DomainMin and DomainMax are the input values
+	// mapped to the first and last 3D LUT samples
+	DomainMin, DomainMax [3]float32
+}
+
+var shaderIdentRE = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+/*
+WebShader samples the Processor into a 3D LUT, and generates GLSL ES 3.0
+and WGSL functions that apply it with trilinear filtering. A 3D LUT can
+only approximate a Processor within its domain, so transforms of scene-linear
+data should be applied to log or display-referred input.
+
+Use WebShader.Eval or WebShader.MaxError to check, in Go, how closely the
+browser will match the Processor.
+*/
+func (p *Processor) WebShader(opts WebShaderOptions) (*WebShader, error) {
+	if opts.FunctionName == "" {
+		opts.FunctionName = "OCIOConvert"
+	}
+	if !shaderIdentRE.MatchString(opts.FunctionName) {
+		return nil, fmt.Errorf("invalid shader function name: %q", opts.FunctionName)
+	}
+	if opts.LutSize == 0 {
+		opts.LutSize = DefaultWebShaderLutSize
+	}
+	if opts.LutSize < 2 {
+		return nil, fmt.Errorf("3D LUT size must be at least 2, got %d", opts.LutSize)
+	}
+	if opts.DomainMin == ([3]float32{}) && opts.DomainMax == ([3]float32{}) {
+		opts.DomainMax = [3]float32{1, 1, 1}
+	}
+	for i := range opts.DomainMin {
+		if !(opts.DomainMax[i] > opts.DomainMin[i]) {
+			return nil, fmt.Errorf("invalid 3D LUT domain: min %v, max %v",
+				opts.DomainMin, opts.DomainMax)
+		}
+	}
+	if opts.WGSLGroup < 0 || opts.WGSLBinding < 0 {
+		return nil, errors.New("WGSL group and binding must not be negative")
+	}
+
+	lut, err := p.sampleLut3D(opts.LutSize, func(c int, t float32) float32 {
+		return opts.DomainMin[c] + t*(opts.DomainMax[c]-opts.DomainMin[c])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s := &WebShader{
+		FunctionName: opts.FunctionName,
+		LutSize:      opts.LutSize,
+		Lut:          lut,
+		DomainMin:    opts.DomainMin,
+		DomainMax:    opts.DomainMax,
+	}
+	cacheID, _ := p.CpuCacheID()
+	s.GLSL = s.glslSource(cacheID)
+	s.WGSL = s.wgslSource(cacheID, opts.WGSLGroup, opts.WGSLBinding)
+	return s, nil
+}
+
+// Eval applies the 3D LUT to an RGB value the same way as the
+// generated shaders: the input is normalized to the domain and
+// clamped, then trilinearly interpolated. GPUs interpolate with
+// reduced precision, so results can differ slightly.
+func (s *WebShader) Eval(rgb [3]float32) [3]float32 {
+	return trilinearLut3D(s.Lut, s.LutSize, s.normalize(rgb))
+}
+
+/*
+MaxError returns the largest absolute difference, in any channel, between
+the Processor and Eval, over a grid of samples^3 input values spanning the
+domain. Sample points between the 3D LUT lattice points are where
+interpolation error is largest, so samples should not be a divisor of
+LutSize-1.
+*/
+func (s *WebShader) MaxError(p *Processor, samples int) (float32, error) {
+	if samples < 2 {
+		return 0, fmt.Errorf("number of samples must be at least 2, got %d", samples)
+	}
+	pixels := make([][3]float32, 0, samples*samples*samples)
+	last := float32(samples - 1)
+	for b := 0; b < samples; b++ {
+		for g := 0; g < samples; g++ {
+			for r := 0; r < samples; r++ {
+				var px [3]float32
+				for c, idx := range [3]int{r, g, b} {
+					t := float32(idx) / last
+					px[c] = s.DomainMin[c] + t*(s.DomainMax[c]-s.DomainMin[c])
+				}
+				pixels = append(pixels, px)
+			}
+		}
+	}
+
+	expect := make([][3]float32, len(pixels))
+	copy(expect, pixels)
+	if err := p.ApplyRGBPixels(expect); err != nil {
+		return 0, err
+	}
+
+	var maxErr float32
+	for i, px := range pixels {
+		actual := s.Eval(px)
+		for c := range actual {
+			if d := float32(math.Abs(float64(actual[c] - expect[i][c]))); d > maxErr || d != d {
+				maxErr = d
+			}
+		}
+	}
+	return maxErr, nil
+}
+
+// normalize maps an input value to the [0, 1] range of the 3D LUT
+func (s *WebShader) normalize(rgb [3]float32) [3]float32 {
+	for c := range rgb {
+		rgb[c] = clampUnit((rgb[c] - s.DomainMin[c]) / (s.DomainMax[c] - s.DomainMin[c]))
+	}
+	return rgb
+}
+
+// LutRGBA16F returns the 3D LUT as little-endian half-float RGBA texels,
+// with alpha set to 1, for a LutSize^3 RGBA16F (WebGL 2) or rgba16float
+// (WebGPU) 3D texture. Half-floats keep values outside of [0, 1], and
+// are filterable on all WebGL 2 and WebGPU devices.
+func (s *WebShader) LutRGBA16F() []byte {
+	one := NewHalf(1)
+	buf := make([]byte, 0, len(s.Lut)/3*8)
+	for i := 0; i+2 < len(s.Lut); i += 3 {
+		for _, h := range [4]Half{NewHalf(s.Lut[i]), NewHalf(s.Lut[i+1]), NewHalf(s.Lut[i+2]), one} {
+			buf = append(buf, byte(h), byte(h>>8))
+		}
+	}
+	return buf
+}
+
+// LutRGBA8 returns the 3D LUT as 8-bit RGBA texels, with alpha set
+// to 255, for a LutSize^3 RGBA8 (WebGL 2) or rgba8unorm (WebGPU)
+// 3D texture. Values are clamped to [0, 1], and quantized to 8 bits,
+// which adds up to 1/510 of error.
+func (s *WebShader) LutRGBA8() []byte {
+	buf := make([]byte, 0, len(s.Lut)/3*4)
+	for i := 0; i+2 < len(s.Lut); i += 3 {
+		buf = append(buf, to8(clampUnit(s.Lut[i])), to8(clampUnit(s.Lut[i+1])), to8(clampUnit(s.Lut[i+2])), 0xff)
+	}
+	return buf
+}
+
+// LutFloat32 returns the 3D LUT as little-endian float32 RGB texels,
+// for a LutSize^3 RGB32F texture. Float32 textures are only filterable
+// when the OES_texture_float_linear (WebGL 2) or float32-filterable
+// (WebGPU) extension is available.
+func (s *WebShader) LutFloat32() []byte {
+	buf := make([]byte, len(s.Lut)*4)
+	for i, v := range s.Lut {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// LutImage returns the 3D LUT as a LutSize wide by LutSize^2 tall image,
+// stacking the blue slices vertically, for encoding with image/png.
+// This is the layout WebGL 2 texImage3D expects when uploading an
+// image with a depth of LutSize. Values are clamped to [0, 1] and
+// stored with 16 bits per channel.
+func (s *WebShader) LutImage() *image.NRGBA64 {
+	size := s.LutSize
+	img := image.NewNRGBA64(image.Rect(0, 0, size, size*size))
+	for i := 0; i+2 < len(s.Lut); i += 3 {
+		texel := i / 3
+		x, y := texel%size, texel/size
+		pix := img.Pix[img.PixOffset(x, y):]
+		for c := 0; c < 4; c++ {
+			v := uint16(0xffff)
+			if c < 3 {
+				v = to16(clampUnit(s.Lut[i+c]))
+			}
+			pix[c*2], pix[c*2+1] = byte(v>>8), byte(v)
+		}
+	}
+	return img
+}
+
+// lutScaleOffset returns the scale and offset that map a normalized
+// [0, 1] value to the texture coordinate of the matching lattice point,
+// at the centers of the first and last texels
+func (s *WebShader) lutScaleOffset() (scale, offset float32) {
+	size := float32(s.LutSize)
+	return (size - 1) / size, 0.5 / size
+}
+
+func (s *WebShader) glslSource(cacheID string) string {
+	scale, offset := s.lutScaleOffset()
+	lutName := s.FunctionName + "_lut3d"
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by opencolorigo: %s\n", cacheID)
+	fmt.Fprintf(&b, "// %[1]dx%[1]dx%[1]d 3D LUT, sampled with LINEAR filtering and CLAMP_TO_EDGE\n", s.LutSize)
+	fmt.Fprintf(&b, "uniform highp sampler3D %s;\n\n", lutName)
+	fmt.Fprintf(&b, "vec4 %s(vec4 inPixel) {\n", s.FunctionName)
+	fmt.Fprintf(&b, "    vec3 rgb = clamp((inPixel.rgb - %s) / %s, 0.0, 1.0);\n",
+		glslVec3(s.DomainMin), glslVec3(domainRange(s.DomainMin, s.DomainMax)))
+	fmt.Fprintf(&b, "    vec3 coord = rgb * %s + %s;\n", shaderFloat(scale), shaderFloat(offset))
+	fmt.Fprintf(&b, "    return vec4(texture(%s, coord).rgb, inPixel.a);\n", lutName)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (s *WebShader) wgslSource(cacheID string, group, binding int) string {
+	scale, offset := s.lutScaleOffset()
+	lutName := s.FunctionName + "_lut3d"
+	samplerName := s.FunctionName + "_sampler"
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by opencolorigo: %s\n", cacheID)
+	fmt.Fprintf(&b, "// %[1]dx%[1]dx%[1]d 3D LUT, sampled with linear filtering and clamp-to-edge\n", s.LutSize)
+	fmt.Fprintf(&b, "@group(%d) @binding(%d) var %s: texture_3d<f32>;\n", group, binding, lutName)
+	fmt.Fprintf(&b, "@group(%d) @binding(%d) var %s: sampler;\n\n", group, binding+1, samplerName)
+	fmt.Fprintf(&b, "fn %s(inPixel: vec4<f32>) -> vec4<f32> {\n", s.FunctionName)
+	fmt.Fprintf(&b, "    let rgb = clamp((inPixel.rgb - %s) / %s, vec3<f32>(0.0), vec3<f32>(1.0));\n",
+		wgslVec3(s.DomainMin), wgslVec3(domainRange(s.DomainMin, s.DomainMax)))
+	fmt.Fprintf(&b, "    let coord = rgb * %s + vec3<f32>(%s);\n", shaderFloat(scale), shaderFloat(offset))
+	fmt.Fprintf(&b, "    return vec4<f32>(textureSampleLevel(%s, %s, coord, 0.0).rgb, inPixel.a);\n",
+		lutName, samplerName)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func domainRange(lo, hi [3]float32) [3]float32 {
+	return [3]float32{hi[0] - lo[0], hi[1] - lo[1], hi[2] - lo[2]}
+}
+
+// shaderFloat formats a float literal that is valid in GLSL and WGSL
+func shaderFloat(v float32) string {
+	s := strconv.FormatFloat(float64(v), 'g', -1, 32)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+func glslVec3(v [3]float32) string {
+	return fmt.Sprintf("vec3(%s, %s, %s)", shaderFloat(v[0]), shaderFloat(v[1]), shaderFloat(v[2]))
+}
+
+func wgslVec3(v [3]float32) string {
+	return fmt.Sprintf("vec3<f32>(%s, %s, %s)", shaderFloat(v[0]), shaderFloat(v[1]), shaderFloat(v[2]))
+}
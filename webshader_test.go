@@ -0,0 +1,140 @@
+package ocio
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestProcessorWebShader(t *testing.T) {
+	proc := getMatrixProcessor(t, 0.5, 0.1)
+	defer proc.Destroy()
+
+	shader, err := proc.WebShader(WebShaderOptions{
+		FunctionName: "reviewDisplay",
+		LutSize:      17,
+		WGSLGroup:    1,
+		WGSLBinding:  2,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, expect := range []string{
+		"uniform highp sampler3D reviewDisplay_lut3d;",
+		"vec4 reviewDisplay(vec4 inPixel)",
+		"texture(reviewDisplay_lut3d, coord)",
+	} {
+		if !strings.Contains(shader.GLSL, expect) {
+			t.Errorf("expected GLSL to contain %q; got:\n%s", expect, shader.GLSL)
+		}
+	}
+	for _, expect := range []string{
+		"@group(1) @binding(2) var reviewDisplay_lut3d: texture_3d<f32>;",
+		"@group(1) @binding(3) var reviewDisplay_sampler: sampler;",
+		"fn reviewDisplay(inPixel: vec4<f32>) -> vec4<f32>",
+	} {
+		if !strings.Contains(shader.WGSL, expect) {
+			t.Errorf("expected WGSL to contain %q; got:\n%s", expect, shader.WGSL)
+		}
+	}
+
+	texels := 17 * 17 * 17
+	if actual := len(shader.Lut); actual != texels*3 {
+		t.Errorf("expected %d LUT values, got %d", texels*3, actual)
+	}
+	if actual := len(shader.LutRGBA16F()); actual != texels*8 {
+		t.Errorf("expected %d bytes of RGBA16F texels, got %d", texels*8, actual)
+	}
+	if actual := len(shader.LutRGBA8()); actual != texels*4 {
+		t.Errorf("expected %d bytes of RGBA8 texels, got %d", texels*4, actual)
+	}
+	if actual := len(shader.LutFloat32()); actual != texels*12 {
+		t.Errorf("expected %d bytes of float32 texels, got %d", texels*12, actual)
+	}
+
+	img := shader.LutImage()
+	if b := img.Bounds(); b.Dx() != 17 || b.Dy() != 17*17 {
+		t.Errorf("expected a 17x289 LUT image, got %v", b)
+	}
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	maxErr, err := shader.MaxError(proc, 10)
+	checkExact(t, "the web shader", maxErr, err)
+
+	expect := [3]float32{0.1, 0.6, 0.35}
+	actual := shader.Eval([3]float32{0, 1, 0.5})
+	for c := range expect {
+		if d := actual[c] - expect[c]; d > 1e-5 || d < -1e-5 {
+			t.Errorf("expected Eval %v, got %v", expect, actual)
+			break
+		}
+	}
+}
+
+func TestProcessorWebShaderDisplay(t *testing.T) {
+	cfg, err := ConfigCreateFromEnv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer cfg.Destroy()
+
+	proc, err := cfg.ProcessorFromNames("lg10", "srgb8")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer proc.Destroy()
+
+	shader, err := proc.WebShader(WebShaderOptions{LutSize: 65})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	maxErr, err := shader.MaxError(proc, 23)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if maxErr > 0.02 {
+		t.Errorf("expected the web shader to match the Processor within 0.02, got %v", maxErr)
+	}
+}
+
+func TestProcessorWebShaderOptions(t *testing.T) {
+	proc := getMatrixProcessor(t, 1, 0)
+	defer proc.Destroy()
+
+	for _, opts := range []WebShaderOptions{
+		{FunctionName: "1convert"},
+		{FunctionName: "convert pixel"},
+		{LutSize: 1},
+		{DomainMin: [3]float32{1, 0, 0}, DomainMax: [3]float32{0, 1, 1}},
+		{WGSLBinding: -1},
+	} {
+		if _, err := proc.WebShader(opts); err == nil {
+			t.Errorf("expected an error for options %+v", opts)
+		}
+	}
+
+	shader, err := proc.WebShader(WebShaderOptions{
+		DomainMin: [3]float32{-0.5, -0.5, -0.5},
+		DomainMax: [3]float32{2, 2, 2},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if shader.FunctionName != "OCIOConvert" || shader.LutSize != DefaultWebShaderLutSize {
+		t.Errorf("expected default function name and LUT size, got %q and %d",
+			shader.FunctionName, shader.LutSize)
+	}
+	expect := [3]float32{1.5, -0.25, 2}
+	actual := shader.Eval([3]float32{1.5, -0.25, 3})
+	for c := range expect {
+		if d := actual[c] - expect[c]; d > 1e-5 || d < -1e-5 {
+			t.Errorf("expected input to be clamped to the domain %v, got %v", expect, actual)
+			break
+		}
+	}
+}